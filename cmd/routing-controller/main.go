@@ -22,8 +22,11 @@ import (
 	// This defines the shared main for injected controllers.
 	"knative.dev/pkg/injection/sharedmain"
 
+	"github.com/triggermesh/routing/pkg/reconciler/aggregator"
 	"github.com/triggermesh/routing/pkg/reconciler/filter"
+	"github.com/triggermesh/routing/pkg/reconciler/router"
 	"github.com/triggermesh/routing/pkg/reconciler/splitter"
+	"github.com/triggermesh/routing/pkg/reconciler/synchronizer"
 )
 
 const (
@@ -34,5 +37,8 @@ func main() {
 	sharedmain.Main(component,
 		filter.NewController,
 		splitter.NewController,
+		router.NewController,
+		aggregator.NewController,
+		synchronizer.NewController,
 	)
 }
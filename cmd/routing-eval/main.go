@@ -0,0 +1,169 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// routing-eval evaluates a Filter or Splitter against sample CloudEvents,
+// without a Kubernetes cluster.
+//
+// Usage:
+//
+//	routing-eval -f <manifest> [event file...]
+//
+// Events are read from standard input when no event file is given.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/triggermesh/routing/pkg/adapter/splitter"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/routingeval"
+)
+
+const stdinName = "-"
+
+func main() {
+	manifest := flag.String("f", "", "Path of the YAML or JSON manifest of the Filter or Splitter to evaluate.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -f <manifest> [event file...]\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Event files contain either structured JSON CloudEvents, or binary "+
+			"CloudEvents as HTTP messages. Events are read from standard input when no file is given.")
+		fmt.Fprintln(flag.CommandLine.Output())
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *manifest == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), os.Stdout, *manifest, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// run evaluates the object read from the given manifest against the events
+// read from the given files, and writes the results to w.
+func run(ctx context.Context, w io.Writer, manifest string, eventFiles []string) error {
+	f, err := os.Open(manifest)
+	if err != nil {
+		return fmt.Errorf("opening manifest: %w", err)
+	}
+	defer f.Close()
+
+	obj, err := routingeval.ReadObject(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	if len(eventFiles) == 0 {
+		eventFiles = []string{stdinName}
+	}
+
+	var eval func(*event.Event) error
+
+	switch o := obj.(type) {
+	case *v1alpha1.Filter:
+		fe, err := routingeval.NewFilterEvaluator(o)
+		if err != nil {
+			return err
+		}
+		eval = func(e *event.Event) error {
+			decision, err := fe.Evaluate(ctx, e)
+			if err != nil {
+				_, err = fmt.Fprintf(w, "%s\t%s (evaluation error: %v)\n", e.ID(), decision, err)
+				return err
+			}
+			_, err = fmt.Fprintf(w, "%s\t%s\n", e.ID(), decision)
+			return err
+		}
+
+	case *v1alpha1.Splitter:
+		eval = func(e *event.Event) error {
+			children, err := splitter.SplitEvent(o, e)
+			if err != nil {
+				return fmt.Errorf("splitting event %q: %w", e.ID(), err)
+			}
+			for _, c := range children {
+				if err := writeEvent(w, c); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	for _, file := range eventFiles {
+		events, err := readEvents(ctx, file)
+		if err != nil {
+			return fmt.Errorf("reading events from %s: %w", file, err)
+		}
+		for _, e := range events {
+			if err := eval(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// readEvents reads the CloudEvents contained in the given file.
+func readEvents(ctx context.Context, file string) ([]*event.Event, error) {
+	var data []byte
+	var err error
+
+	if file == stdinName {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return routingeval.ReadEvents(ctx, data)
+}
+
+// writeEvent writes the given event to w in the structured JSON format, on a
+// single line. Attributes are sorted by name so that the output is stable.
+func writeEvent(w io.Writer, e *event.Event) error {
+	// JSON data is written as is rather than base64-encoded
+	if e.DataMediaType() == event.ApplicationJSON {
+		e.DataBase64 = false
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding event %q: %w", e.ID(), err)
+	}
+
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(b, &attrs); err != nil {
+		return fmt.Errorf("decoding event %q: %w", e.ID(), err)
+	}
+
+	return json.NewEncoder(w).Encode(attrs)
+}
@@ -27,14 +27,24 @@ import (
 	"knative.dev/pkg/webhook"
 	"knative.dev/pkg/webhook/certificates"
 	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/pkg/webhook/resourcesemantics/conversion"
+	"knative.dev/pkg/webhook/resourcesemantics/defaulting"
 	"knative.dev/pkg/webhook/resourcesemantics/validation"
 
 	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1beta1"
 )
 
 var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
-	// List the types to validate.
-	v1alpha1.SchemeGroupVersion.WithKind("Filter"): &v1alpha1.Filter{},
+	// List the types to validate and default.
+	v1alpha1.SchemeGroupVersion.WithKind("Filter"):       &v1alpha1.Filter{},
+	v1alpha1.SchemeGroupVersion.WithKind("Router"):       &v1alpha1.Router{},
+	v1alpha1.SchemeGroupVersion.WithKind("Splitter"):     &v1alpha1.Splitter{},
+	v1alpha1.SchemeGroupVersion.WithKind("Aggregator"):   &v1alpha1.Aggregator{},
+	v1alpha1.SchemeGroupVersion.WithKind("Synchronizer"): &v1alpha1.Synchronizer{},
+
+	v1beta1.SchemeGroupVersion.WithKind("Filter"):   &v1beta1.Filter{},
+	v1beta1.SchemeGroupVersion.WithKind("Splitter"): &v1beta1.Splitter{},
 }
 
 var callbacks = map[schema.GroupVersionKind]validation.Callback{}
@@ -66,6 +76,68 @@ func NewFilterValidationAdmissionController(ctx context.Context, cmw configmap.W
 	)
 }
 
+func NewDefaultingAdmissionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	return defaulting.NewAdmissionController(ctx,
+
+		// Name of the resource webhook.
+		"defaulting.webhook.routing.triggermesh.io",
+
+		// The path on which to serve the webhook.
+		"/defaulting",
+
+		// The resources to default.
+		types,
+
+		// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
+		func(ctx context.Context) context.Context {
+			return ctx
+		},
+
+		// Whether to disallow unknown fields.
+		true,
+	)
+}
+
+func NewConversionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	var (
+		v1alpha1_ = v1alpha1.SchemeGroupVersion.Version
+		v1beta1_  = v1beta1.SchemeGroupVersion.Version
+	)
+
+	return conversion.NewConversionController(ctx,
+
+		// The path on which to serve the webhook.
+		"/resource-conversion",
+
+		// Specify the types of custom resource definitions that should be converted.
+		// v1alpha1 is the storage version, and performs conversions to and
+		// from all other versions.
+		map[schema.GroupKind]conversion.GroupKindConversion{
+			v1alpha1.Kind("Filter"): {
+				DefinitionName: v1alpha1.Resource("filters").String(),
+				HubVersion:     v1alpha1_,
+				Zygotes: map[string]conversion.ConvertibleObject{
+					v1alpha1_: &v1alpha1.Filter{},
+					v1beta1_:  &v1beta1.Filter{},
+				},
+			},
+			v1alpha1.Kind("Splitter"): {
+				DefinitionName: v1alpha1.Resource("splitters").String(),
+				HubVersion:     v1alpha1_,
+				Zygotes: map[string]conversion.ConvertibleObject{
+					v1alpha1_: &v1alpha1.Splitter{},
+					v1beta1_:  &v1beta1.Splitter{},
+				},
+			},
+		},
+
+		// A function that infuses the context passed to ConvertTo/ConvertFrom/SetDefaults with custom metadata.
+		func(ctx context.Context) context.Context {
+			return ctx
+		},
+	)
+}
+
 func main() {
 	// Set up a signal context with our webhook options
 	ctx := webhook.WithOptions(signals.NewContext(), webhook.Options{
@@ -78,5 +150,7 @@ func main() {
 	sharedmain.WebhookMainWithContext(ctx, webhook.NameFromEnv(),
 		certificates.NewController,
 		NewFilterValidationAdmissionController,
+		NewDefaultingAdmissionController,
+		NewConversionController,
 	)
 }
@@ -6,9 +6,11 @@ require (
 	github.com/cloudevents/sdk-go/v2 v2.4.1
 	github.com/google/cel-go v0.7.2
 	github.com/google/go-cmp v0.5.6
+	github.com/google/uuid v1.2.0
 	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/stretchr/testify v1.7.0
 	github.com/tidwall/gjson v1.6.8
+	go.opencensus.io v0.23.0
 	go.uber.org/zap v1.17.0
 	google.golang.org/genproto v0.0.0-20210416161957-9910b6c460de
 	google.golang.org/protobuf v1.26.0
@@ -20,4 +22,5 @@ require (
 	knative.dev/networking v0.0.0-20210512050647-ace2d3306f0b
 	knative.dev/pkg v0.0.0-20210701025203-30f9568e894e
 	knative.dev/serving v0.23.0
+	sigs.k8s.io/yaml v1.2.0
 )
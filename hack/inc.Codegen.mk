@@ -8,8 +8,12 @@ PKG := github.com/triggermesh/routing
 # List of API groups to generate code for
 # e.g. "filter/v1alpha1 filter/v1alpha2"
 API_GROUPS := flow/v1alpha1
+# List of API groups which are only served through conversion, and for which
+# no client is generated
+CONVERTED_API_GROUPS := flow/v1beta1
 # generates e.g. "PKG/pkg/apis/router/v1alpha1 PKG/pkg/apis/router/v1alpha2"
 api-import-paths := $(foreach group,$(API_GROUPS),$(PKG)/pkg/apis/$(group))
+converted-api-import-paths := $(foreach group,$(CONVERTED_API_GROUPS),$(PKG)/pkg/apis/$(group))
 
 generators := deepcopy client lister informer injection
 
@@ -23,10 +27,10 @@ space :=
 space +=
 
 deepcopy:
-	@echo "+ Generating deepcopy funcs for $(API_GROUPS)"
+	@echo "+ Generating deepcopy funcs for $(API_GROUPS) $(CONVERTED_API_GROUPS)"
 	@go run k8s.io/code-generator/cmd/deepcopy-gen \
 		--go-header-file hack/boilerplate/boilerplate.go.txt \
-		--input-dirs $(subst $(space),$(comma),$(api-import-paths))
+		--input-dirs $(subst $(space),$(comma),$(api-import-paths) $(converted-api-import-paths))
 
 client:
 	@echo "+ Generating clientsets for $(API_GROUPS)"
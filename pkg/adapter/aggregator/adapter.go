@@ -0,0 +1,584 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	pkgadapter "knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/utils"
+	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/env"
+	"github.com/triggermesh/routing/pkg/adapter/common/metrics"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventfilter"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+const serverPort int = 8080
+
+// expiryInterval is the interval at which expired batches are looked up.
+const expiryInterval = time.Second
+
+// retryInterval is the delay after which a batch which couldn't be sent is
+// sent again, unless the Aggregator's delivery options define a backoff.
+const retryInterval = 5 * time.Second
+
+// defaultRetryMax is the number of times a batch which couldn't be sent is
+// sent again when the Aggregator has no delivery options.
+const defaultRetryMax = 5
+
+// Handler parses Cloud Events, adds them to the batch they belong to, and
+// sends complete batches to a subscriber.
+type Handler struct {
+	// receiver receives incoming HTTP requests
+	receiver *kncloudevents.HTTPMessageReceiver
+	// sender sends requests to downstream services
+	sender *kncloudevents.HTTPMessageSender
+
+	logger *zap.SugaredLogger
+
+	// store holds the batches which are being aggregated
+	store Store
+	// aggregators is the routing table of the Aggregators served by the
+	// adapter
+	aggregators *routingTableStore
+}
+
+// NewEnvConfig satisfies env.ConfigConstructor.
+// Returns an accessor for the source's adapter envConfig.
+func NewEnvConfig() env.ConfigAccessor {
+	return &env.Config{}
+}
+
+// NewAdapter creates a new Handler and its associated MessageReceiver, which
+// keeps batches in memory. The caller is responsible for Start()ing the
+// returned Handler.
+func NewAdapter(component string) pkgadapter.AdapterConstructor {
+	return NewAdapterWithStore(component, NewMemoryStore())
+}
+
+// NewAdapterWithStore creates a new Handler and its associated
+// MessageReceiver, which keeps batches in the given Store. The caller is
+// responsible for Start()ing the returned Handler.
+func NewAdapterWithStore(component string, store Store) pkgadapter.AdapterConstructor {
+	return func(ctx context.Context, _ pkgadapter.EnvConfigAccessor,
+		ceClient cloudevents.Client) pkgadapter.Adapter {
+		logger := logging.FromContext(ctx)
+
+		sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+		if err != nil {
+			logger.Panicf("failed to create message sender: %v", err)
+		}
+
+		if err := metrics.RegisterViews(); err != nil {
+			logger.Panicf("failed to register metrics views: %v", err)
+		}
+
+		return &Handler{
+			receiver: kncloudevents.NewHTTPMessageReceiver(serverPort),
+			sender:   sender,
+			logger:   logger,
+
+			store:       store,
+			aggregators: newRoutingTableStore(),
+		}
+	}
+}
+
+// RegisterHandlerFor implements MTAdapter.
+func (h *Handler) RegisterHandlerFor(ctx context.Context, ag *v1alpha1.Aggregator) error {
+	prev, _ := h.aggregators.get(ag.Name)
+
+	e, err := newAggregatorEntry(ag, prev)
+	if err != nil {
+		// stop serving a state which no longer matches the Aggregator
+		h.aggregators.delete(ag.Name)
+		return err
+	}
+	h.aggregators.set(e)
+
+	return nil
+}
+
+// DeregisterHandlerFor implements MTAdapter.
+//
+// The batches of the Aggregator are kept, so that they can still be sent once
+// the Aggregator is registered again.
+func (h *Handler) DeregisterHandlerFor(ctx context.Context, key types.NamespacedName) error {
+	h.aggregators.delete(key.Name)
+	return nil
+}
+
+// DiscardBatchesOf implements MTAdapter.
+func (h *Handler) DiscardBatchesOf(ctx context.Context, key types.NamespacedName) error {
+	return h.store.DeleteAll(ctx, key.Name)
+}
+
+// Start begins to receive messages for the handler.
+//
+// HTTP POST requests to the root path (/) are accepted.
+//
+// This method will block until ctx is done.
+func (h *Handler) Start(ctx context.Context) error {
+	go h.runExpiry(ctx)
+	return h.receiver.StartListen(ctx, h)
+}
+
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	aggregator, err := parseRequestURI(request.RequestURI)
+	if err != nil {
+		h.logger.Info("Unable to parse path as aggregator", zap.Error(err), zap.String("path", request.RequestURI))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ag, exists := h.aggregators.get(aggregator)
+	if !exists {
+		h.logger.Info("Aggregator not found or not ready", zap.Any("aggregator", aggregator))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := request.Context()
+
+	message := cehttp.NewMessageFromHttpRequest(request)
+	// cannot be err, but makes linter complain about missing err check
+	//nolint
+	defer message.Finish(nil)
+
+	event, err := binding.ToEvent(ctx, message)
+	if err != nil {
+		h.logger.Warn("failed to extract event from request", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Received message", zap.Any("aggregator", aggregator))
+
+	key, ok := correlationKey(ag.spec.Correlation, event)
+	if !ok {
+		h.logger.Info("Event has no correlation key", zap.Any("aggregator", aggregator), zap.String("id", event.ID()))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var deadline time.Time
+	if ag.timeout != 0 {
+		deadline = time.Now().Add(ag.timeout)
+	}
+
+	count, err := h.store.Append(ctx, ag.name, key, deadline, *event)
+	if err != nil {
+		h.logger.Error("failed to store the event", zap.Error(err), zap.Any("aggregator", aggregator))
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	complete, err := isComplete(ctx, ag.spec.Completion, ag.completion, event, count)
+	if err != nil {
+		h.logger.Info("Failed to evaluate completion condition", zap.Error(err), zap.Any("aggregator", aggregator))
+		metrics.NewReporter(ag.namespace, ag.name).ReportFilterResult(ctx, metrics.FilterResultError)
+	}
+	if !complete {
+		writer.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	batch, err := h.store.Pop(ctx, ag.name, key)
+	if err != nil {
+		h.logger.Error("failed to retrieve the batch", zap.Error(err), zap.Any("aggregator", aggregator))
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if batch == nil {
+		// the batch was already sent, e.g. after expiring
+		writer.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if err := h.sendBatch(ctx, request.Header, ag, batch); err != nil {
+		// the event itself was stored successfully, so the sender
+		// shouldn't retry it
+		if err := h.handleFailedBatch(ctx, ag, batch, err); err != nil {
+			h.logger.Error("failed to restore the batch", zap.Error(err), zap.Any("aggregator", aggregator))
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writer.WriteHeader(http.StatusAccepted)
+}
+
+// runExpiry periodically sends the batches which have reached their deadline
+// until ctx is done.
+func (h *Handler) runExpiry(ctx context.Context) {
+	ticker := time.NewTicker(expiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.sendExpired(ctx, now)
+		}
+	}
+}
+
+// sendExpired sends the incomplete batches which have reached their deadline.
+func (h *Handler) sendExpired(ctx context.Context, now time.Time) {
+	batches, err := h.store.PopExpired(ctx, now)
+	if err != nil {
+		h.logger.Error("failed to retrieve expired batches", zap.Error(err))
+		return
+	}
+
+	for _, b := range batches {
+		ag, exists := h.aggregators.get(b.Aggregator)
+		if !exists {
+			// the Aggregator isn't served until its sink is resolved
+			// again, its batches are discarded if it gets deleted.
+			// Waiting doesn't count as a failed attempt.
+			h.logger.Info("Aggregator of expired batch not found or not ready, retrying later",
+				zap.String("aggregator", b.Aggregator), zap.String("key", b.Key))
+
+			b.Deadline = time.Now().Add(retryInterval)
+			if err := h.store.Restore(ctx, b); err != nil {
+				h.logger.Error("failed to restore expired batch", zap.Error(err),
+					zap.String("aggregator", b.Aggregator), zap.String("key", b.Key))
+			}
+			continue
+		}
+
+		if err := h.sendBatch(ctx, nil, ag, b); err != nil {
+			if err := h.handleFailedBatch(ctx, ag, b, err); err != nil {
+				h.logger.Error("failed to restore expired batch", zap.Error(err),
+					zap.String("aggregator", b.Aggregator), zap.String("key", b.Key))
+			}
+		}
+	}
+}
+
+// handleFailedBatch puts the given batch, which couldn't be sent, back into
+// the store to be sent again later. Once the retries allowed by the
+// Aggregator's delivery options are exhausted, the batch is sent to the dead
+// letter sink, if any, or dropped.
+func (h *Handler) handleFailedBatch(ctx context.Context, ag *aggregatorEntry, b *Batch, sendErr error) error {
+	b.Attempts++
+
+	retryMax := defaultRetryMax
+	if r := ag.delivery.Retry; r != nil {
+		retryMax = r.RetryMax
+	}
+
+	if b.Attempts <= retryMax {
+		h.logger.Info("Failed to send the batch, retrying later", zap.Error(sendErr),
+			zap.String("aggregator", ag.name), zap.String("key", b.Key), zap.Int("attempts", b.Attempts))
+
+		delay := retryInterval
+		if r := ag.delivery.Retry; r != nil && r.Backoff != nil {
+			if d := r.Backoff(b.Attempts, nil); d > 0 {
+				delay = d
+			}
+		}
+
+		b.Deadline = time.Now().Add(delay)
+		return h.store.Restore(ctx, b)
+	}
+
+	dls := ag.delivery.DeadLetterSink
+	if dls == nil {
+		h.logger.Error("Dropping batch which couldn't be sent", zap.Error(sendErr),
+			zap.String("aggregator", ag.name), zap.String("key", b.Key), zap.Int("attempts", b.Attempts))
+		return nil
+	}
+
+	h.logger.Info("Delivery failed, sending batch to the dead letter sink", zap.Error(sendErr),
+		zap.String("aggregator", ag.name), zap.String("key", b.Key), zap.Int("attempts", b.Attempts))
+
+	if err := h.sendBatchTo(ctx, nil, ag, dls.String(), b); err != nil {
+		h.logger.Error("Dropping batch which couldn't be sent to the dead letter sink", zap.Error(err),
+			zap.String("aggregator", ag.name), zap.String("key", b.Key))
+	}
+	return nil
+}
+
+// sendBatch sends the event aggregating the given batch to the Aggregator's
+// sink.
+func (h *Handler) sendBatch(ctx context.Context, headers http.Header, ag *aggregatorEntry, b *Batch) error {
+	return h.sendBatchTo(ctx, headers, ag, ag.sinkURI, b)
+}
+
+// sendBatchTo sends the event aggregating the given batch to the given target.
+func (h *Handler) sendBatchTo(ctx context.Context, headers http.Header, ag *aggregatorEntry,
+	target string, b *Batch) error {
+
+	event, err := batchEvent(ag, b)
+	if err != nil {
+		return fmt.Errorf("creating batch event: %w", err)
+	}
+
+	if r := ag.delivery.Retry; r != nil && r.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.RequestTimeout)
+		defer cancel()
+	}
+
+	resp, err := h.sendEvent(ctx, headers, target, event)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("target responded with status code %d", resp.StatusCode)
+	}
+
+	h.logger.Debug("Successfully dispatched batch", zap.String("aggregator", ag.name),
+		zap.String("key", b.Key), zap.Int("count", len(b.Events)))
+
+	return nil
+}
+
+func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target string, event *cloudevents.Event) (*http.Response, error) {
+	// Send the event to the subscriber
+	req, err := h.sender.NewCloudEventRequestWithTarget(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	message := binding.ToMessage(event)
+	// cannot be err, but makes linter complain about missing err check
+	//nolint
+	defer message.Finish(nil)
+
+	additionalHeaders := utils.PassThroughHeaders(headers)
+	err = kncloudevents.WriteHTTPRequestWithAdditionalHeaders(ctx, message, req, additionalHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+
+	resp, err := h.sender.Send(req)
+	if err != nil {
+		err = fmt.Errorf("failed to dispatch message: %w", err)
+	}
+
+	return resp, err
+}
+
+// correlationKey returns the key of the batch the given event belongs to.
+func correlationKey(corr v1alpha1.AggregatorCorrelation, e *cloudevents.Event) (string, bool) {
+	if corr.Path != "" {
+		val := gjson.GetBytes(e.Data(), corr.Path)
+		if !val.Exists() {
+			return "", false
+		}
+		return val.String(), true
+	}
+
+	var key string
+	switch corr.Attribute {
+	case "id":
+		key = e.ID()
+	case "source":
+		key = e.Source()
+	case "type":
+		key = e.Type()
+	case "subject":
+		key = e.Subject()
+	default:
+		ext, ok := e.Extensions()[corr.Attribute]
+		if !ok {
+			return "", false
+		}
+		str, err := cetypes.Format(ext)
+		if err != nil {
+			return "", false
+		}
+		key = str
+	}
+
+	return key, key != ""
+}
+
+// isComplete returns whether the batch which the given event was added to is
+// complete. A completion condition which fails to evaluate doesn't complete
+// the batch, and the evaluation error is returned.
+func isComplete(ctx context.Context, compl v1alpha1.AggregatorCompletion, cond *cel.ConditionalFilter,
+	e *cloudevents.Event, count int) (bool, error) {
+
+	if compl.Count != nil && count >= int(*compl.Count) {
+		return true, nil
+	}
+
+	if compl.TotalExtension != "" {
+		if ext, ok := e.Extensions()[compl.TotalExtension]; ok {
+			if total, err := cetypes.ToInteger(ext); err == nil && count >= int(total) {
+				return true, nil
+			}
+		}
+	}
+
+	if cond == nil {
+		return false, nil
+	}
+
+	res, err := cond.Evaluate(ctx, *e)
+	if err != nil {
+		return false, err
+	}
+	return res == eventfilter.PassFilter, nil
+}
+
+// templateData is the data available to output templates.
+type templateData struct {
+	// Key is the correlation key of the batch.
+	Key string
+	// Count is the number of events in the batch.
+	Count int
+	// Items are the decoded payloads of the events in the batch.
+	Items []interface{}
+}
+
+// batchEvent returns the event which aggregates the given batch.
+func batchEvent(ag *aggregatorEntry, b *Batch) (*cloudevents.Event, error) {
+	events := sortedEvents(b.Events)
+
+	items := make([]interface{}, len(events))
+	for i := range events {
+		items[i] = payload(&events[i])
+	}
+
+	var data []byte
+	var err error
+
+	if ag.output == nil {
+		if data, err = json.Marshal(items); err != nil {
+			return nil, fmt.Errorf("encoding batch: %w", err)
+		}
+	} else {
+		for i := range items {
+			// decode payloads so their fields are accessible in templates
+			if raw, ok := items[i].(json.RawMessage); ok {
+				var v interface{}
+				if err := json.Unmarshal(raw, &v); err != nil {
+					return nil, fmt.Errorf("decoding item %d: %w", i, err)
+				}
+				items[i] = v
+			}
+		}
+
+		var buf bytes.Buffer
+		tplData := templateData{
+			Key:   b.Key,
+			Count: len(items),
+			Items: items,
+		}
+		if err := ag.output.Execute(&buf, tplData); err != nil {
+			return nil, fmt.Errorf("executing output template: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.New().String())
+	event.SetType(ag.outputType)
+	event.SetSource(ag.outputSource)
+	for key, value := range ag.spec.Output.CEContext.Extensions {
+		event.SetExtension(key, value)
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("setting batch data: %w", err)
+	}
+
+	return &event, nil
+}
+
+// sortedEvents returns the given events ordered by their Splitter index when
+// all of them carry one, or in their order of arrival otherwise.
+func sortedEvents(events []cloudevents.Event) []cloudevents.Event {
+	type indexedEvent struct {
+		index int32
+		event cloudevents.Event
+	}
+
+	indexed := make([]indexedEvent, len(events))
+	for i := range events {
+		ext, ok := events[i].Extensions()[v1alpha1.SplitterExtensionIndex]
+		if !ok {
+			return events
+		}
+		idx, err := cetypes.ToInteger(ext)
+		if err != nil {
+			return events
+		}
+		indexed[i] = indexedEvent{index: idx, event: events[i]}
+	}
+
+	sort.SliceStable(indexed, func(i, j int) bool {
+		return indexed[i].index < indexed[j].index
+	})
+
+	sorted := make([]cloudevents.Event, len(indexed))
+	for i := range indexed {
+		sorted[i] = indexed[i].event
+	}
+
+	return sorted
+}
+
+// payload returns the payload of the given event as a JSON value.
+func payload(e *cloudevents.Event) interface{} {
+	data := e.Data()
+	if json.Valid(data) {
+		return json.RawMessage(data)
+	}
+	return string(data)
+}
+
+func parseRequestURI(path string) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("incorrect number of parts in the path, expected 2, actual %d, '%s'", len(parts), path)
+	}
+	return parts[1], nil
+}
@@ -0,0 +1,566 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/metrics/metricstest"
+	_ "knative.dev/pkg/metrics/testing"
+	"knative.dev/pkg/ptr"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/metrics"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+func TestHandler(t *testing.T) {
+	sink := newTestSink(t)
+
+	ag := newTestAggregator(t, sink.URL)
+	h := newTestHandler(t, ag)
+	ctx := context.Background()
+
+	t.Run("complete batches", func(t *testing.T) {
+		sink.reset(http.StatusAccepted)
+
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":1}`))
+		assert.Empty(t, sink.events(), "Incomplete batches aren't sent")
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":2}`))
+
+		// a second batch with the same correlation key
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":3}`))
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":4}`))
+
+		events := sink.events()
+		require.Len(t, events, 2)
+		assert.Equal(t, "io.triggermesh.batch", events[0].Type())
+		assert.JSONEq(t, `{"key":"k1","items":[{"n":1},{"n":2}]}`, string(events[0].Data()))
+		assert.JSONEq(t, `{"key":"k1","items":[{"n":3},{"n":4}]}`, string(events[1].Data()))
+		assert.NotEqual(t, events[0].ID(), events[1].ID(), "Batch events have unique IDs")
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		sink.reset(http.StatusInternalServerError)
+
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k2", `{"n":1}`))
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k2", `{"n":2}`),
+			"The last event is accepted even though the batch couldn't be sent")
+		assert.Len(t, sink.events(), 1)
+
+		sink.reset(http.StatusAccepted)
+
+		h.sendExpired(ctx, time.Now())
+		assert.Empty(t, sink.events(), "Failed batches are retried after a delay")
+
+		h.sendExpired(ctx, time.Now().Add(retryInterval+time.Second))
+		events := sink.events()
+		require.Len(t, events, 1, "Failed batches are retried")
+		assert.JSONEq(t, `{"key":"k2","items":[{"n":1},{"n":2}]}`, string(events[0].Data()))
+	})
+
+	t.Run("unknown aggregator", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, postEvent(h, "/other", "k3", `{"n":1}`))
+	})
+}
+
+func TestHandlerDelivery(t *testing.T) {
+	linear := eventingduckv1.BackoffPolicyLinear
+
+	testCases := map[string]struct {
+		delivery *eventingduckv1.DeliverySpec
+		withDLS  bool
+		// retries are the times at which expired batches are looked up,
+		// relative to the first attempt to send the batch
+		retries []time.Duration
+		// expectSinkEvents is the number of attempts to send the batch
+		// to the sink
+		expectSinkEvents int
+		expectDLSEvents  int
+	}{
+		"default retries": {
+			retries: []time.Duration{
+				1 * (retryInterval + time.Second),
+				2 * (retryInterval + time.Second),
+				3 * (retryInterval + time.Second),
+				4 * (retryInterval + time.Second),
+				5 * (retryInterval + time.Second),
+				6 * (retryInterval + time.Second),
+				// no attempt is left
+				time.Hour,
+			},
+			expectSinkEvents: 1 + defaultRetryMax,
+		},
+		"retries exhausted": {
+			delivery: &eventingduckv1.DeliverySpec{
+				Retry: ptr.Int32(1),
+			},
+			retries: []time.Duration{
+				1 * (retryInterval + time.Second),
+				2 * (retryInterval + time.Second),
+				time.Hour,
+			},
+			expectSinkEvents: 2,
+		},
+		"backoff": {
+			delivery: &eventingduckv1.DeliverySpec{
+				Retry:         ptr.Int32(1),
+				BackoffPolicy: &linear,
+				BackoffDelay:  ptr.String("PT1M"),
+			},
+			retries: []time.Duration{
+				1 * (retryInterval + time.Second),
+				2 * (retryInterval + time.Second),
+			},
+			expectSinkEvents: 1,
+		},
+		"dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{
+				Retry: ptr.Int32(1),
+			},
+			withDLS: true,
+			retries: []time.Duration{
+				1 * (retryInterval + time.Second),
+				time.Hour,
+			},
+			expectSinkEvents: 2,
+			expectDLSEvents:  1,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			sink := newTestSink(t)
+			sink.reset(http.StatusInternalServerError)
+			dls := newTestSink(t)
+
+			ag := newTestAggregator(t, sink.URL)
+			ag.Spec.Delivery = tc.delivery
+			if tc.withDLS {
+				dlsURI, err := apis.ParseURL(dls.URL)
+				require.NoError(t, err)
+				ag.Status.DeadLetterSinkURI = dlsURI
+			}
+			h := newTestHandler(t, ag)
+
+			start := time.Now()
+
+			assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":1}`))
+			assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":2}`))
+
+			for _, r := range tc.retries {
+				h.sendExpired(context.Background(), start.Add(r))
+			}
+
+			assert.Len(t, sink.events(), tc.expectSinkEvents)
+
+			dlsEvents := dls.events()
+			require.Len(t, dlsEvents, tc.expectDLSEvents)
+			for _, e := range dlsEvents {
+				assert.JSONEq(t, `{"key":"k1","items":[{"n":1},{"n":2}]}`, string(e.Data()))
+			}
+		})
+	}
+}
+
+func TestHandlerEvaluationError(t *testing.T) {
+	require.NoError(t, metrics.RegisterViews())
+
+	sink := newTestSink(t)
+	sink.reset(http.StatusAccepted)
+
+	ag := newTestAggregator(t, sink.URL)
+	// fails to evaluate for events without this extension
+	ag.Spec.Completion.Expression = `ce.extensions["missing"] == "x"`
+	h := newTestHandler(t, ag)
+
+	errorTags := map[string]string{
+		"namespace_name": ag.Namespace,
+		"router_name":    ag.Name,
+		"result":         string(metrics.FilterResultError),
+	}
+
+	assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":1}`))
+	assert.Empty(t, sink.events(), "Failed evaluations don't complete batches")
+	metricstest.CheckCountData(t, "filter_result_count", errorTags, 1)
+
+	assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":2}`))
+	assert.Len(t, sink.events(), 1, "Batches are still completed by other conditions")
+	metricstest.CheckCountData(t, "filter_result_count", errorTags, 1)
+}
+
+func TestNewAggregatorEntry(t *testing.T) {
+	testCases := map[string]struct {
+		mutate    func(*v1alpha1.Aggregator)
+		expectErr string
+	}{
+		"up-to-date status": {
+			mutate: func(*v1alpha1.Aggregator) {},
+		},
+		"outdated status": {
+			mutate: func(ag *v1alpha1.Aggregator) {
+				ag.Generation = 2
+			},
+			expectErr: "status of generation 1 doesn't reflect the current generation 2",
+		},
+		"unresolved sink": {
+			mutate: func(ag *v1alpha1.Aggregator) {
+				ag.Status.SinkURI = nil
+			},
+			expectErr: "sink URI isn't resolved",
+		},
+		"invalid template": {
+			mutate: func(ag *v1alpha1.Aggregator) {
+				ag.Spec.Output.Template = `{{ .Items `
+			},
+			expectErr: "parsing output template",
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ag := newTestAggregator(t, "http://sink.ns.svc.cluster.local")
+			tc.mutate(ag)
+
+			e, err := newAggregatorEntry(ag, nil)
+			if tc.expectErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectErr)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "http://sink.ns.svc.cluster.local", e.sinkURI)
+			assert.NotNil(t, e.output)
+		})
+	}
+}
+
+func TestHandlerOutdatedStatus(t *testing.T) {
+	oldSink := newTestSink(t)
+	newSink := newTestSink(t)
+
+	ag := newTestAggregator(t, oldSink.URL)
+	h := newTestHandler(t, ag)
+	r := &Reconciler{adapter: h}
+	ctx := context.Background()
+
+	ag = ag.DeepCopy()
+	ag.Generation = 2
+	sinkURI, err := apis.ParseURL(newSink.URL)
+	require.NoError(t, err)
+	ag.Status.SinkURI = sinkURI
+
+	assert.Error(t, r.ObserveKind(ctx, ag), "The status doesn't reflect the current generation")
+
+	postEvent(h, "/test", "k1", `{"n":1}`)
+	postEvent(h, "/test", "k1", `{"n":2}`)
+	assert.Len(t, oldSink.events(), 1, "The previous generation remains served")
+	assert.Empty(t, newSink.events())
+
+	ag.Status.ObservedGeneration = 2
+	require.NoError(t, r.ObserveKind(ctx, ag))
+
+	postEvent(h, "/test", "k1", `{"n":1}`)
+	postEvent(h, "/test", "k1", `{"n":2}`)
+	assert.Len(t, oldSink.events(), 1)
+	assert.Len(t, newSink.events(), 1, "The current generation is served once its status caught up")
+}
+
+func TestHandlerDeregistration(t *testing.T) {
+	sink := newTestSink(t)
+
+	ag := newTestAggregator(t, sink.URL)
+	h := newTestHandler(t, ag)
+	r := &Reconciler{adapter: h}
+	ctx := context.Background()
+
+	t.Run("unresolved sink", func(t *testing.T) {
+		sink.reset(http.StatusAccepted)
+
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":1}`))
+
+		sinkless := ag.DeepCopy()
+		sinkless.Status.SinkURI = nil
+		assert.Error(t, r.ObserveKind(ctx, sinkless))
+		assert.Equal(t, http.StatusBadRequest, postEvent(h, "/test", "k1", `{"n":2}`),
+			"Aggregators without sink aren't served")
+
+		require.NoError(t, r.ObserveKind(ctx, ag))
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k1", `{"n":3}`))
+
+		events := sink.events()
+		require.Len(t, events, 1, "Batches are kept while the Aggregator isn't served")
+		assert.JSONEq(t, `{"key":"k1","items":[{"n":1},{"n":3}]}`, string(events[0].Data()))
+	})
+
+	t.Run("deletion", func(t *testing.T) {
+		sink.reset(http.StatusAccepted)
+
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k2", `{"n":1}`))
+
+		require.NoError(t, r.ObserveDeletion(ctx, keyOf(ag)))
+		require.NoError(t, r.ObserveKind(ctx, ag))
+
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "k2", `{"n":2}`))
+		assert.Empty(t, sink.events(), "Batches of deleted Aggregators are discarded")
+	})
+}
+
+func TestIsComplete(t *testing.T) {
+	count := int32(3)
+
+	testCases := map[string]struct {
+		completion v1alpha1.AggregatorCompletion
+		extensions map[string]interface{}
+		count      int
+		expect     bool
+		expectErr  bool
+	}{
+		"count reached": {
+			completion: v1alpha1.AggregatorCompletion{Count: &count},
+			count:      3,
+			expect:     true,
+		},
+		"count not reached": {
+			completion: v1alpha1.AggregatorCompletion{Count: &count},
+			count:      2,
+			expect:     false,
+		},
+		"total extension reached": {
+			completion: v1alpha1.AggregatorCompletion{TotalExtension: "total"},
+			extensions: map[string]interface{}{"total": int32(2)},
+			count:      2,
+			expect:     true,
+		},
+		"expression matches": {
+			completion: v1alpha1.AggregatorCompletion{Expression: `ce.extensions["last"] == true`},
+			extensions: map[string]interface{}{"last": true},
+			count:      1,
+			expect:     true,
+		},
+		"expression doesn't match": {
+			completion: v1alpha1.AggregatorCompletion{Expression: `ce.extensions["last"] == true`},
+			extensions: map[string]interface{}{"last": false},
+			count:      1,
+			expect:     false,
+		},
+		"expression fails": {
+			completion: v1alpha1.AggregatorCompletion{Expression: `ce.extensions["last"] == true`},
+			count:      1,
+			expect:     false,
+			expectErr:  true,
+		},
+		"expression fails after count reached": {
+			completion: v1alpha1.AggregatorCompletion{Count: &count, Expression: `ce.extensions["last"] == true`},
+			count:      3,
+			expect:     true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			var cond *cel.ConditionalFilter
+			if expr := tc.completion.Expression; expr != "" {
+				c, err := cel.CompileExpression(expr)
+				require.NoError(t, err)
+				cond = &c
+			}
+
+			e := cloudevents.NewEvent()
+			for k, v := range tc.extensions {
+				e.SetExtension(k, v)
+			}
+
+			complete, err := isComplete(context.Background(), tc.completion, cond, &e, tc.count)
+			assert.Equal(t, tc.expect, complete)
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBatchEvent(t *testing.T) {
+	ag := newTestAggregator(t, "http://sink.ns")
+	ag.Spec.Output = v1alpha1.AggregatorOutput{}
+
+	b := &Batch{
+		Aggregator: ag.Name,
+		Key:        "k1",
+		Events:     make([]cloudevents.Event, 3),
+	}
+	for i, data := range []string{`{"n":1}`, `"text"`, `not json`} {
+		b.Events[i] = cloudevents.NewEvent()
+		b.Events[i].SetExtension(v1alpha1.SplitterExtensionIndex, int32(2-i))
+		require.NoError(t, b.Events[i].SetData(cloudevents.ApplicationJSON, []byte(data)))
+	}
+
+	entry, err := newAggregatorEntry(ag, nil)
+	require.NoError(t, err)
+
+	e, err := batchEvent(entry, b)
+	require.NoError(t, err)
+
+	assert.Equal(t, v1alpha1.AggregatorGenericEventType, e.Type())
+	assert.Equal(t, ag.AsRouter(), e.Source())
+	assert.NotEqual(t, b.Key, e.ID())
+	assert.JSONEq(t, `["not json","text",{"n":1}]`, string(e.Data()),
+		"Payloads are ordered by Splitter index and non-JSON payloads are quoted")
+}
+
+// testSink is a HTTP server which records the events it receives, and
+// replies with a configurable status code.
+type testSink struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	received []cloudevents.Event
+}
+
+func newTestSink(t *testing.T) *testSink {
+	s := &testSink{status: http.StatusAccepted}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.received = append(s.received, *e)
+		w.WriteHeader(s.status)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// reset discards the received events and sets the status code of future
+// replies.
+func (s *testSink) reset(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = nil
+	s.status = status
+}
+
+// events returns the received events.
+func (s *testSink) events() []cloudevents.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]cloudevents.Event(nil), s.received...)
+}
+
+// newTestAggregator returns an Aggregator named "test" which aggregates pairs
+// of events correlated by their "corrid" extension.
+func newTestAggregator(t *testing.T, sink string) *v1alpha1.Aggregator {
+	count := int32(2)
+
+	ag := &v1alpha1.Aggregator{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "test",
+			Generation: 1,
+		},
+		Spec: v1alpha1.AggregatorSpec{
+			Correlation: v1alpha1.AggregatorCorrelation{
+				Attribute: "corrid",
+			},
+			Completion: v1alpha1.AggregatorCompletion{
+				Count: &count,
+			},
+			Output: v1alpha1.AggregatorOutput{
+				CEContext: v1alpha1.CloudEventContext{
+					Type: "io.triggermesh.batch",
+				},
+				Template: `{"key": {{ json .Key }}, "items": {{ json .Items }}}`,
+			},
+		},
+	}
+
+	sinkURI, err := apis.ParseURL(sink)
+	require.NoError(t, err)
+	ag.Status.SinkURI = sinkURI
+	ag.Status.ObservedGeneration = ag.Generation
+
+	return ag
+}
+
+// newTestHandler returns a Handler which serves the given Aggregators.
+func newTestHandler(t *testing.T, ags ...*v1alpha1.Aggregator) *Handler {
+	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	require.NoError(t, err)
+
+	h := &Handler{
+		sender: sender,
+		logger: zap.NewNop().Sugar(),
+
+		store:       NewMemoryStore(),
+		aggregators: newRoutingTableStore(),
+	}
+
+	for _, ag := range ags {
+		require.NoError(t, h.RegisterHandlerFor(context.Background(), ag))
+	}
+
+	return h
+}
+
+// postEvent sends a structured CloudEvent with the given correlation key and
+// JSON payload to the given path of the Handler, and returns the status code
+// of the response.
+func postEvent(h *Handler, path, key, data string) int {
+	event := `{"specversion":"1.0","id":"0","type":"test","source":"test","corrid":"` + key + `",` +
+		`"datacontenttype":"application/json","data":` + data + `}`
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(event))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec.Code
+}
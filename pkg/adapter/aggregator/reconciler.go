@@ -0,0 +1,148 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/reconciler"
+
+	adaptercontroller "github.com/triggermesh/routing/pkg/adapter/common/controller"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	reconcilerv1alpha1 "github.com/triggermesh/routing/pkg/client/generated/injection/reconciler/flow/v1alpha1/aggregator"
+)
+
+// Reconciler implements controller.Reconciler for the event source type.
+type Reconciler struct {
+	adapter MTAdapter
+	// scope determines which Aggregators are served by the adapter
+	scope adaptercontroller.Scope
+	// patch acknowledges the configuration loaded by the adapter
+	patch adaptercontroller.PatchFunc
+}
+
+// Check the interfaces Reconciler should implement.
+var (
+	_ reconcilerv1alpha1.Interface         = (*Reconciler)(nil)
+	_ reconcilerv1alpha1.ReadOnlyInterface = (*Reconciler)(nil)
+	_ reconcilerv1alpha1.ReadOnlyFinalizer = (*Reconciler)(nil)
+	_ reconciler.OnDeletionInterface       = (*Reconciler)(nil)
+)
+
+// ReconcileKind implements reconcilerv1alpha1.Interface.
+func (r *Reconciler) ReconcileKind(ctx context.Context, ag *v1alpha1.Aggregator) reconciler.Event {
+	return r.reconcile(ctx, ag, true)
+}
+
+// ObserveKind implements reconcilerv1alpha1.ReadOnlyInterface.
+func (r *Reconciler) ObserveKind(ctx context.Context, ag *v1alpha1.Aggregator) reconciler.Event {
+	return r.reconcile(ctx, ag, false)
+}
+
+// reconcile registers the given Aggregator with the adapter. Only the leader
+// acknowledges the outcome of the registration, so that acknowledgement
+// reflects the state of the leader. Other replicas register the same
+// generation from their own informer, and may lag slightly behind.
+func (r *Reconciler) reconcile(ctx context.Context, ag *v1alpha1.Aggregator, acknowledge bool) error {
+	if !r.scope.Serves(ag) {
+		// The Aggregator is served by another adapter, which also
+		// acknowledges its registration.
+		if err := r.adapter.DeregisterHandlerFor(ctx, keyOf(ag)); err != nil {
+			return fmt.Errorf("deregistering HTTP handler: %w", err)
+		}
+		return nil
+	}
+
+	if ag.Status.SinkURI == nil {
+		// Stop serving a state which no longer matches the Aggregator.
+		if err := r.adapter.DeregisterHandlerFor(ctx, keyOf(ag)); err != nil {
+			return fmt.Errorf("deregistering HTTP handler: %w", err)
+		}
+
+		// Mark that error as permanent so we don't retry until the
+		// source's status has been updated, which automatically
+		// triggers a new reconciliation.
+		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning, ReasonSourceNotReady,
+			"Sink URL wasn't resolved yet. Skipping adapter configuration"))
+	}
+
+	if ag.Status.ObservedGeneration != ag.Generation {
+		// The sink URI is only paired with the spec of the generation
+		// it was resolved for. The previous generation remains served
+		// until the status catches up.
+		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeNormal, ReasonSourceNotReady,
+			"Sink URL wasn't resolved for generation %d yet. Skipping adapter configuration", ag.Generation))
+	}
+
+	err := r.adapter.RegisterHandlerFor(ctx, ag)
+
+	if acknowledge {
+		if ackErr := adaptercontroller.AcknowledgeSync(ctx, ag, err, r.patch); ackErr != nil {
+			return fmt.Errorf("acknowledging adapter sync: %w", ackErr)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("registering HTTP handler: %w", err)
+	}
+
+	return nil
+}
+
+// ObserveFinalizeKind implements reconcilerv1alpha1.ReadOnlyFinalizer.
+func (r *Reconciler) ObserveFinalizeKind(ctx context.Context, ag *v1alpha1.Aggregator) reconciler.Event {
+	return r.finalize(ctx, ag)
+}
+
+func (r *Reconciler) finalize(ctx context.Context, ag *v1alpha1.Aggregator) error {
+	if err := r.deregister(ctx, keyOf(ag)); err != nil {
+		return err
+	}
+
+	return reconciler.NewEvent(corev1.EventTypeNormal, ReasonHandlerDeregistered,
+		"HTTP handler deregistered")
+}
+
+// ObserveDeletion implements reconciler.OnDeletionInterface.
+//
+// Aggregators don't carry a finalizer, so their deletion is only observed as the
+// disappearance of the object from the informer's cache.
+func (r *Reconciler) ObserveDeletion(ctx context.Context, key types.NamespacedName) error {
+	return r.deregister(ctx, key)
+}
+
+// deregister deregisters the deleted Aggregator with the given key from the
+// adapter, and discards its pending batches.
+func (r *Reconciler) deregister(ctx context.Context, key types.NamespacedName) error {
+	if err := r.adapter.DeregisterHandlerFor(ctx, key); err != nil {
+		return fmt.Errorf("deregistering HTTP handler: %w", err)
+	}
+	if err := r.adapter.DiscardBatchesOf(ctx, key); err != nil {
+		return fmt.Errorf("discarding batches: %w", err)
+	}
+	return nil
+}
+
+// keyOf returns the key of the given Aggregator.
+func keyOf(ag *v1alpha1.Aggregator) types.NamespacedName {
+	return types.NamespacedName{Namespace: ag.Namespace, Name: ag.Name}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// Batch is a set of correlated events.
+type Batch struct {
+	// Aggregator is the name of the Aggregator the batch belongs to.
+	Aggregator string
+	// Key is the correlation key shared by all events of the batch.
+	Key string
+	// Deadline is the time after which the batch expires. A zero value
+	// means the batch never expires.
+	Deadline time.Time
+	// Events are the events of the batch, in their order of arrival.
+	Events []cloudevents.Event
+	// Attempts is the number of failed attempts to send the batch.
+	Attempts int
+}
+
+// Store persists the batches of events which are being aggregated.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Append adds an event to the batch identified by the given
+	// Aggregator and key, creating the batch if it doesn't exist yet, and
+	// returns the number of events in the batch.
+	Append(ctx context.Context, aggregator, key string, deadline time.Time, e cloudevents.Event) (int, error)
+	// Pop removes the batch identified by the given Aggregator and key
+	// from the store and returns it. A nil batch is returned if no such
+	// batch exists, e.g. because it was already popped.
+	Pop(ctx context.Context, aggregator, key string) (*Batch, error)
+	// PopExpired removes all batches whose deadline is before the given
+	// time from the store and returns them.
+	PopExpired(ctx context.Context, now time.Time) ([]*Batch, error)
+	// Restore puts back a batch which was popped from the store but
+	// couldn't be sent. If events were added to the same batch in the
+	// meantime, they are appended to the events of the restored batch, and
+	// the earliest of both deadlines is kept.
+	Restore(ctx context.Context, b *Batch) error
+	// DeleteAll removes all batches of the given Aggregator.
+	DeleteAll(ctx context.Context, aggregator string) error
+}
+
+// memoryStore is a Store which keeps batches in memory.
+type memoryStore struct {
+	sync.Mutex
+	batches map[batchID]*Batch
+}
+
+type batchID struct {
+	aggregator string
+	key        string
+}
+
+// NewMemoryStore returns a Store which keeps batches in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		batches: make(map[batchID]*Batch),
+	}
+}
+
+// Append implements Store.
+func (s *memoryStore) Append(_ context.Context, aggregator, key string, deadline time.Time, e cloudevents.Event) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	id := batchID{aggregator: aggregator, key: key}
+
+	b, exists := s.batches[id]
+	if !exists {
+		b = &Batch{
+			Aggregator: aggregator,
+			Key:        key,
+			Deadline:   deadline,
+		}
+		s.batches[id] = b
+	}
+	b.Events = append(b.Events, e)
+
+	return len(b.Events), nil
+}
+
+// Pop implements Store.
+func (s *memoryStore) Pop(_ context.Context, aggregator, key string) (*Batch, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	id := batchID{aggregator: aggregator, key: key}
+
+	b := s.batches[id]
+	delete(s.batches, id)
+
+	return b, nil
+}
+
+// PopExpired implements Store.
+func (s *memoryStore) PopExpired(_ context.Context, now time.Time) ([]*Batch, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var expired []*Batch
+	for id, b := range s.batches {
+		if !b.Deadline.IsZero() && b.Deadline.Before(now) {
+			expired = append(expired, b)
+			delete(s.batches, id)
+		}
+	}
+
+	return expired, nil
+}
+
+// Restore implements Store.
+func (s *memoryStore) Restore(_ context.Context, b *Batch) error {
+	s.Lock()
+	defer s.Unlock()
+
+	id := batchID{aggregator: b.Aggregator, key: b.Key}
+
+	if curr, exists := s.batches[id]; exists {
+		b.Events = append(b.Events, curr.Events...)
+		if b.Deadline.IsZero() || (!curr.Deadline.IsZero() && curr.Deadline.Before(b.Deadline)) {
+			b.Deadline = curr.Deadline
+		}
+	}
+	s.batches[id] = b
+
+	return nil
+}
+
+// DeleteAll implements Store.
+func (s *memoryStore) DeleteAll(_ context.Context, aggregator string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for id := range s.batches {
+		if id.aggregator == aggregator {
+			delete(s.batches, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	newEvent := func(id string) cloudevents.Event {
+		e := cloudevents.NewEvent()
+		e.SetID(id)
+		return e
+	}
+
+	t.Run("append and pop", func(t *testing.T) {
+		s := NewMemoryStore()
+
+		n, err := s.Append(ctx, "agg", "k1", time.Time{}, newEvent("1"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		n, err = s.Append(ctx, "agg", "k1", time.Time{}, newEvent("2"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		n, err = s.Append(ctx, "other", "k1", time.Time{}, newEvent("3"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, n, "Batches of different Aggregators are distinct")
+
+		b, err := s.Pop(ctx, "agg", "k1")
+		require.NoError(t, err)
+		require.NotNil(t, b)
+		assert.Equal(t, []string{"1", "2"}, eventIDs(b.Events))
+
+		b, err = s.Pop(ctx, "agg", "k1")
+		require.NoError(t, err)
+		assert.Nil(t, b, "Batches can only be popped once")
+	})
+
+	t.Run("pop expired", func(t *testing.T) {
+		s := NewMemoryStore()
+
+		_, _ = s.Append(ctx, "agg", "expired", now.Add(-time.Second), newEvent("1"))
+		_, _ = s.Append(ctx, "agg", "pending", now.Add(time.Minute), newEvent("2"))
+		_, _ = s.Append(ctx, "agg", "no-deadline", time.Time{}, newEvent("3"))
+
+		bs, err := s.PopExpired(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, bs, 1)
+		assert.Equal(t, "expired", bs[0].Key)
+
+		bs, err = s.PopExpired(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, bs)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		s := NewMemoryStore()
+
+		_, _ = s.Append(ctx, "agg", "k1", time.Time{}, newEvent("1"))
+		b, _ := s.Pop(ctx, "agg", "k1")
+
+		// event received while the batch was being sent
+		_, _ = s.Append(ctx, "agg", "k1", now.Add(time.Minute), newEvent("2"))
+
+		b.Deadline = now.Add(time.Second)
+		require.NoError(t, s.Restore(ctx, b))
+
+		n, err := s.Append(ctx, "agg", "k1", time.Time{}, newEvent("3"))
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+
+		bs, err := s.PopExpired(ctx, now.Add(2*time.Second))
+		require.NoError(t, err)
+		require.Len(t, bs, 1, "The earliest deadline is kept")
+		assert.Equal(t, []string{"1", "2", "3"}, eventIDs(bs[0].Events))
+	})
+
+	t.Run("delete all", func(t *testing.T) {
+		s := NewMemoryStore()
+
+		_, _ = s.Append(ctx, "agg", "k1", time.Time{}, newEvent("1"))
+		_, _ = s.Append(ctx, "agg", "k2", time.Time{}, newEvent("2"))
+		_, _ = s.Append(ctx, "other", "k1", time.Time{}, newEvent("3"))
+
+		require.NoError(t, s.DeleteAll(ctx, "agg"))
+
+		b, _ := s.Pop(ctx, "agg", "k1")
+		assert.Nil(t, b)
+		b, _ = s.Pop(ctx, "agg", "k2")
+		assert.Nil(t, b)
+		b, _ = s.Pop(ctx, "other", "k1")
+		assert.NotNil(t, b, "Batches of other Aggregators are kept")
+	})
+}
+
+// eventIDs returns the IDs of the given events.
+func eventIDs(events []cloudevents.Event) []string {
+	ids := make([]string, len(events))
+	for i := range events {
+		ids[i] = events[i].ID()
+	}
+	return ids
+}
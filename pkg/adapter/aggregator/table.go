@@ -0,0 +1,182 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/delivery"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventaggregator"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// aggregatorEntry is the resolved state of an Aggregator, i.e. everything the
+// adapter needs to know about an Aggregator to handle the events it receives.
+// Entries are immutable once they are part of a routingTable.
+type aggregatorEntry struct {
+	namespace  string
+	name       string
+	uid        types.UID
+	generation int64
+
+	// spec is the Aggregator's spec with defaults applied.
+	spec v1alpha1.AggregatorSpec
+	// timeout is zero when batches never expire.
+	timeout time.Duration
+	// completion is nil when the Aggregator has no completion expression.
+	completion *cel.ConditionalFilter
+	// output is nil when the Aggregator has no output template.
+	output *template.Template
+
+	outputType   string
+	outputSource string
+
+	sinkURI string
+
+	delivery delivery.Config
+}
+
+// newAggregatorEntry resolves the state of the given Aggregator. The status of
+// the Aggregator must reflect its current generation, so that the sink URI
+// matches the spec it was resolved for. The compiled expression and template
+// of prev are reused if they match the Aggregator's generation.
+func newAggregatorEntry(ag *v1alpha1.Aggregator, prev *aggregatorEntry) (*aggregatorEntry, error) {
+	if ag.Status.ObservedGeneration != ag.Generation {
+		return nil, fmt.Errorf("status of generation %d doesn't reflect the current generation %d",
+			ag.Status.ObservedGeneration, ag.Generation)
+	}
+	if ag.Status.SinkURI == nil {
+		return nil, fmt.Errorf("sink URI isn't resolved")
+	}
+
+	// objects persisted before a default was introduced are only
+	// defaulted by the webhook on their next update
+	ag = ag.DeepCopy()
+	ag.SetDefaults(context.Background())
+
+	e := &aggregatorEntry{
+		namespace:  ag.Namespace,
+		name:       ag.Name,
+		uid:        ag.UID,
+		generation: ag.Generation,
+
+		spec: ag.Spec,
+
+		outputType:   ag.OutputType(),
+		outputSource: ag.OutputSource(),
+
+		sinkURI: ag.Status.SinkURI.String(),
+	}
+
+	if t := ag.Spec.Completion.Timeout; t != nil {
+		// the timeout is validated by the webhook
+		e.timeout, _ = time.ParseDuration(*t)
+	}
+
+	dc, err := delivery.NewConfig(ag.Spec.Delivery, ag.Status.DeadLetterSinkURI.DeepCopy())
+	if err != nil {
+		return nil, fmt.Errorf("invalid delivery options: %w", err)
+	}
+	e.delivery = dc
+
+	if prev != nil && prev.uid == ag.UID && prev.generation == ag.Generation {
+		e.completion = prev.completion
+		e.output = prev.output
+		return e, nil
+	}
+
+	if expr := ag.Spec.Completion.Expression; expr != "" {
+		cond, err := cel.CompileExpression(expr)
+		if err != nil {
+			return nil, fmt.Errorf("compiling completion expression: %w", err)
+		}
+		e.completion = &cond
+	}
+
+	if t := ag.Spec.Output.Template; t != "" {
+		tpl, err := eventaggregator.ParseTemplate(ag.Name, t)
+		if err != nil {
+			return nil, fmt.Errorf("parsing output template: %w", err)
+		}
+		e.output = tpl
+	}
+
+	return e, nil
+}
+
+// routingTable maps the names of Aggregators to their resolved state.
+type routingTable map[string]*aggregatorEntry
+
+// routingTableStore holds a routingTable which can be read without locking.
+// Writers replace the whole table with an updated copy.
+type routingTableStore struct {
+	// serializes writers
+	mu sync.Mutex
+	// always holds a routingTable
+	table atomic.Value
+}
+
+func newRoutingTableStore() *routingTableStore {
+	s := &routingTableStore{}
+	s.table.Store(make(routingTable))
+	return s
+}
+
+// get returns the resolved state of the Aggregator with the given name.
+func (s *routingTableStore) get(name string) (*aggregatorEntry, bool) {
+	e, exists := s.table.Load().(routingTable)[name]
+	return e, exists
+}
+
+// set inserts or replaces the resolved state of an Aggregator.
+func (s *routingTableStore) set(e *aggregatorEntry) {
+	s.update(func(t routingTable) {
+		t[e.name] = e
+	})
+}
+
+// delete removes the resolved state of the Aggregator with the given name.
+func (s *routingTableStore) delete(name string) {
+	s.update(func(t routingTable) {
+		delete(t, name)
+	})
+}
+
+// update applies the given function to a copy of the current table, and
+// atomically swaps the current table for that copy.
+func (s *routingTableStore) update(fn func(routingTable)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	curr := s.table.Load().(routingTable)
+
+	next := make(routingTable, len(curr)+1)
+	for k, v := range curr {
+		next[k] = v
+	}
+	fn(next)
+
+	s.table.Store(next)
+}
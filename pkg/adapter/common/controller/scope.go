@@ -0,0 +1,71 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+// Scope determines which routers an adapter serves. The zero value is the
+// scope of a multi-tenant adapter.
+type Scope struct {
+	// routerName is the name of the single router served by a dedicated
+	// adapter. It is empty for multi-tenant adapters.
+	routerName string
+}
+
+// NewScope returns the Scope of an adapter which serves the router with the
+// given name, or of a multi-tenant adapter if the name is empty.
+func NewScope(routerName string) Scope {
+	return Scope{routerName: routerName}
+}
+
+// Serves returns whether the given router is served by the adapter. A
+// dedicated adapter only serves its own router, while a multi-tenant adapter
+// serves all routers which didn't opt into a dedicated adapter.
+func (s Scope) Serves(obj metav1.Object) bool {
+	if s.routerName != "" {
+		return obj.GetName() == s.routerName
+	}
+	return !flow.HasDedicatedAdapter(obj)
+}
+
+// Filter is a filter function for informer event handlers which selects the
+// routers served by the adapter.
+func (s Scope) Filter(obj interface{}) bool {
+	o, ok := obj.(metav1.Object)
+	return ok && s.Serves(o)
+}
+
+type scopeKey struct{}
+
+// WithScope returns a copy of the given context which carries the Scope of
+// the adapter.
+func WithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// ScopeFromContext returns the Scope of the adapter carried by the given
+// context, or the scope of a multi-tenant adapter if the context carries none.
+func ScopeFromContext(ctx context.Context) Scope {
+	s, _ := ctx.Value(scopeKey{}).(Scope)
+	return s
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+func TestScope(t *testing.T) {
+	newObject := func(name, isolation string) *metav1.ObjectMeta {
+		obj := &metav1.ObjectMeta{Namespace: "ns", Name: name}
+		if isolation != "" {
+			obj.Annotations = map[string]string{flow.AdapterIsolationAnnotation: isolation}
+		}
+		return obj
+	}
+
+	testCases := map[string]struct {
+		scope        Scope
+		obj          interface{}
+		expectServed bool
+	}{
+		"multi-tenant adapter, default isolation": {
+			scope:        NewScope(""),
+			obj:          newObject("a", ""),
+			expectServed: true,
+		},
+		"multi-tenant adapter, shared router": {
+			scope:        NewScope(""),
+			obj:          newObject("a", flow.AdapterIsolationShared),
+			expectServed: true,
+		},
+		"multi-tenant adapter, dedicated router": {
+			scope: NewScope(""),
+			obj:   newObject("a", flow.AdapterIsolationDedicated),
+		},
+		"dedicated adapter, own router": {
+			scope:        NewScope("a"),
+			obj:          newObject("a", flow.AdapterIsolationDedicated),
+			expectServed: true,
+		},
+		"dedicated adapter, other router": {
+			scope: NewScope("a"),
+			obj:   newObject("b", flow.AdapterIsolationDedicated),
+		},
+		"not an object": {
+			scope: NewScope(""),
+			obj:   "a",
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expectServed, tc.scope.Filter(tc.obj))
+		})
+	}
+}
+
+func TestScopeFromContext(t *testing.T) {
+	assert.Equal(t, NewScope(""), ScopeFromContext(context.Background()),
+		"Adapters are multi-tenant by default")
+
+	ctx := WithScope(context.Background(), NewScope("a"))
+	assert.Equal(t, NewScope("a"), ScopeFromContext(ctx))
+}
@@ -0,0 +1,78 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+// PatchFunc applies a JSON merge patch to the object with the given namespace
+// and name.
+type PatchFunc func(ctx context.Context, namespace, name string, patch []byte) error
+
+// AcknowledgeSync annotates the given router with its generation that was
+// processed by the adapter, along with the error which prevented the adapter
+// from loading it, if any. The router is not patched if it is already
+// annotated accordingly.
+func AcknowledgeSync(ctx context.Context, obj metav1.Object, syncErr error, patchFn PatchFunc) error {
+	patch, err := syncPatch(obj, syncErr)
+	if err != nil {
+		return fmt.Errorf("creating patch: %w", err)
+	}
+	if patch == nil {
+		return nil
+	}
+
+	return patchFn(ctx, obj.GetNamespace(), obj.GetName(), patch)
+}
+
+// syncPatch returns a JSON merge patch which sets the annotations that
+// acknowledge the processing of the given router's generation, or nil if no
+// patch is required.
+func syncPatch(obj metav1.Object, syncErr error) ([]byte, error) {
+	var errMsg string
+	if syncErr != nil {
+		errMsg = syncErr.Error()
+	}
+
+	gen, currErrMsg := flow.AdapterSync(obj)
+	if gen == obj.GetGeneration() && currErrMsg == errMsg {
+		return nil, nil
+	}
+
+	annotations := map[string]interface{}{
+		flow.AdapterGenerationAnnotation: strconv.FormatInt(obj.GetGeneration(), 10),
+		// a null value removes the annotation
+		flow.AdapterErrorAnnotation: nil,
+	}
+	if errMsg != "" {
+		annotations[flow.AdapterErrorAnnotation] = errMsg
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+}
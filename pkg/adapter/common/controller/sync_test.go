@@ -0,0 +1,83 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+func TestSyncPatch(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		syncErr     error
+		expectPatch string
+	}{
+		"not acknowledged yet": {
+			annotations: nil,
+			expectPatch: `{"metadata":{"annotations":{` +
+				`"flow.triggermesh.io/adapter-error":null,` +
+				`"flow.triggermesh.io/adapter-generation":"2"}}}`,
+		},
+		"previous generation acknowledged": {
+			annotations: map[string]string{flow.AdapterGenerationAnnotation: "1"},
+			expectPatch: `{"metadata":{"annotations":{` +
+				`"flow.triggermesh.io/adapter-error":null,` +
+				`"flow.triggermesh.io/adapter-generation":"2"}}}`,
+		},
+		"current generation acknowledged": {
+			annotations: map[string]string{flow.AdapterGenerationAnnotation: "2"},
+			expectPatch: "",
+		},
+		"current generation failed to load": {
+			annotations: map[string]string{flow.AdapterGenerationAnnotation: "2"},
+			syncErr:     errors.New("bad expression"),
+			expectPatch: `{"metadata":{"annotations":{` +
+				`"flow.triggermesh.io/adapter-error":"bad expression",` +
+				`"flow.triggermesh.io/adapter-generation":"2"}}}`,
+		},
+		"current generation failure acknowledged": {
+			annotations: map[string]string{
+				flow.AdapterGenerationAnnotation: "2",
+				flow.AdapterErrorAnnotation:      "bad expression",
+			},
+			syncErr:     errors.New("bad expression"),
+			expectPatch: "",
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Generation:  2,
+				Annotations: tc.annotations,
+			}
+
+			patch, err := syncPatch(obj, tc.syncErr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectPatch, string(patch))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package delivery contains helpers for retrying and dead-lettering the
+// delivery of events by router adapters.
+package delivery
+
+import (
+	"fmt"
+	"net/http"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+)
+
+// Config contains the delivery options of a router.
+type Config struct {
+	// Retry is the retry configuration of requests. A nil value disables
+	// retries.
+	Retry *kncloudevents.RetryConfig
+	// DeadLetterSink is the URI of the sink which receives events that
+	// couldn't be delivered. A nil value disables dead-lettering.
+	DeadLetterSink *apis.URL
+}
+
+// NewConfig returns the delivery Config matching the given delivery spec and
+// resolved dead letter sink URI.
+func NewConfig(spec *eventingduckv1.DeliverySpec, deadLetterSinkURI *apis.URL) (Config, error) {
+	var cfg Config
+
+	if spec == nil {
+		return cfg, nil
+	}
+
+	retryCfg, err := kncloudevents.RetryConfigFromDeliverySpec(*spec)
+	if err != nil {
+		return cfg, fmt.Errorf("parsing delivery spec: %w", err)
+	}
+	cfg.Retry = &retryCfg
+	cfg.DeadLetterSink = deadLetterSinkURI
+
+	return cfg, nil
+}
+
+// Failed returns whether the outcome of a request denotes a failed delivery.
+func Failed(resp *http.Response, err error) bool {
+	return err != nil || resp == nil ||
+		resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestNewConfig(t *testing.T) {
+	dlsURI := apis.HTTP("dls.ns.svc.cluster.local")
+	linear := eventingduckv1.BackoffPolicyLinear
+
+	testCases := map[string]struct {
+		spec        *eventingduckv1.DeliverySpec
+		dlsURI      *apis.URL
+		expectRetry int
+		expectDLS   *apis.URL
+		expectErr   bool
+	}{
+		"no delivery spec": {
+			spec:   nil,
+			dlsURI: dlsURI,
+		},
+		"retries": {
+			spec: &eventingduckv1.DeliverySpec{
+				Retry:         ptr.Int32(3),
+				BackoffPolicy: &linear,
+				BackoffDelay:  ptr.String("PT0.1S"),
+			},
+			expectRetry: 3,
+		},
+		"dead letter sink": {
+			spec: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{URI: dlsURI},
+			},
+			dlsURI:    dlsURI,
+			expectDLS: dlsURI,
+		},
+		"invalid backoff delay": {
+			spec: &eventingduckv1.DeliverySpec{
+				Retry:         ptr.Int32(3),
+				BackoffPolicy: &linear,
+				BackoffDelay:  ptr.String("1s"),
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			cfg, err := NewConfig(tc.spec, tc.dlsURI)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectDLS, cfg.DeadLetterSink)
+
+			if tc.spec == nil {
+				assert.Nil(t, cfg.Retry)
+				return
+			}
+			require.NotNil(t, cfg.Retry)
+			assert.Equal(t, tc.expectRetry, cfg.Retry.RetryMax)
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	linear := eventingduckv1.BackoffPolicyLinear
+
+	testCases := map[string]struct {
+		failures      int32
+		retry         int32
+		expectFailed  bool
+		expectAttempt int32
+	}{
+		"succeeds after failures": {
+			failures:      2,
+			retry:         3,
+			expectAttempt: 3,
+		},
+		"retries exhausted": {
+			failures:      10,
+			retry:         2,
+			expectFailed:  true,
+			expectAttempt: 3,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if atomic.AddInt32(&attempts, 1) <= tc.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer sink.Close()
+
+			cfg, err := NewConfig(&eventingduckv1.DeliverySpec{
+				Retry:         ptr.Int32(tc.retry),
+				BackoffPolicy: &linear,
+				BackoffDelay:  ptr.String("PT0.01S"),
+			}, nil)
+			require.NoError(t, err)
+
+			sender, err := kncloudevents.NewHTTPMessageSenderWithTarget(sink.URL)
+			require.NoError(t, err)
+
+			req, err := sender.NewCloudEventRequest(context.Background())
+			require.NoError(t, err)
+
+			resp, err := sender.SendWithRetries(req, cfg.Retry)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			assert.Equal(t, tc.expectFailed, Failed(resp, err))
+			assert.Equal(t, tc.expectAttempt, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestFailed(t *testing.T) {
+	testCases := map[string]struct {
+		resp   *http.Response
+		err    error
+		expect bool
+	}{
+		"accepted": {
+			resp:   &http.Response{StatusCode: http.StatusAccepted},
+			expect: false,
+		},
+		"rejected": {
+			resp:   &http.Response{StatusCode: http.StatusBadRequest},
+			expect: true,
+		},
+		"redirected": {
+			resp:   &http.Response{StatusCode: http.StatusMovedPermanently},
+			expect: true,
+		},
+		"transport error": {
+			err:    errors.New("connection refused"),
+			expect: true,
+		},
+		"no response": {
+			expect: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, Failed(tc.resp, tc.err))
+		})
+	}
+}
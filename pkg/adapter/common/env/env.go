@@ -28,11 +28,17 @@ type ConfigAccessor interface {
 	adapter.EnvConfigAccessor
 	// Get the component name.
 	GetComponent() string
+	// Get the name of the router served by a dedicated adapter.
+	GetRouterName() string
 }
 
 // Config is the minimal set of configuration parameters source adapters should support.
 type Config struct {
 	*adapter.EnvConfig
+
+	// RouterName is the name of the single router served by a dedicated
+	// adapter. Multi-tenant adapters leave it empty.
+	RouterName string `envconfig:"ROUTER_NAME"`
 }
 
 // Verify that Config implements ConfigAccessor.
@@ -43,6 +49,11 @@ func (c *Config) GetComponent() string {
 	return c.Component
 }
 
+// GetRouterName implements ConfigAccessor.
+func (c *Config) GetRouterName() string {
+	return c.RouterName
+}
+
 // ConfigConstructor is a callback function that returns a ConfigAccessor.
 type ConfigConstructor func() ConfigAccessor
 
@@ -0,0 +1,203 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains the OpenCensus measures and views reported by
+// router adapters.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"knative.dev/pkg/metrics"
+	"knative.dev/pkg/metrics/metricskey"
+)
+
+// FilterResult is the outcome of the evaluation of an event by a Filter.
+type FilterResult string
+
+// Possible outcomes of the evaluation of an event by a Filter.
+const (
+	// FilterResultPass is reported for events which are forwarded to the sink.
+	FilterResultPass FilterResult = "pass"
+	// FilterResultDrop is reported for events which are discarded.
+	FilterResultDrop FilterResult = "drop"
+	// FilterResultError is reported for each failed evaluation of an
+	// expression, regardless of the error policy of the Filter. It is also
+	// reported by Routers and Aggregators, for their route and completion
+	// conditions.
+	FilterResultError FilterResult = "error"
+)
+
+var (
+	// eventCountM is a counter which records the number of events
+	// received by a router.
+	eventCountM = stats.Int64(
+		"event_count",
+		"Number of events received by a router",
+		stats.UnitDimensionless,
+	)
+
+	// filterResultCountM is a counter which records the outcome of the
+	// evaluation of events by a Filter.
+	filterResultCountM = stats.Int64(
+		"filter_result_count",
+		"Number of events evaluated by a filter",
+		stats.UnitDimensionless,
+	)
+
+	// splitSizeM records the number of events produced by splitting a
+	// single event.
+	splitSizeM = stats.Int64(
+		"split_size",
+		"Number of events produced by splitting an event",
+		stats.UnitDimensionless,
+	)
+
+	// dispatchLatencyM records the time spent dispatching an event to a
+	// sink, including retries.
+	dispatchLatencyM = stats.Float64(
+		"dispatch_latencies",
+		"The time spent dispatching an event to a sink",
+		stats.UnitMilliseconds,
+	)
+)
+
+// Tag keys
+var (
+	namespaceKey         = tag.MustNewKey(metricskey.LabelNamespaceName)
+	nameKey              = tag.MustNewKey("router_name")
+	resultKey            = tag.MustNewKey("result")
+	responseCodeKey      = tag.MustNewKey(metricskey.LabelResponseCode)
+	responseCodeClassKey = tag.MustNewKey(metricskey.LabelResponseCodeClass)
+)
+
+var registerOnce sync.Once
+
+// RegisterViews registers the views of all measures reported by router
+// adapters. It is safe to call it multiple times.
+func RegisterViews() error {
+	var err error
+	registerOnce.Do(func() {
+		err = registerViews()
+	})
+	return err
+}
+
+// registerViews registers the views of all measures reported by router
+// adapters.
+func registerViews() error {
+	routerKeys := []tag.Key{namespaceKey, nameKey}
+
+	return view.Register(
+		&view.View{
+			Description: eventCountM.Description(),
+			Measure:     eventCountM,
+			Aggregation: view.Count(),
+			TagKeys:     routerKeys,
+		},
+		&view.View{
+			Description: filterResultCountM.Description(),
+			Measure:     filterResultCountM,
+			Aggregation: view.Count(),
+			TagKeys:     append(routerKeys, resultKey),
+		},
+		&view.View{
+			Description: splitSizeM.Description(),
+			Measure:     splitSizeM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 1000)...),
+			TagKeys:     routerKeys,
+		},
+		&view.View{
+			Description: dispatchLatencyM.Description(),
+			Measure:     dispatchLatencyM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...),
+			TagKeys:     append(routerKeys, responseCodeKey, responseCodeClassKey),
+		},
+		&view.View{
+			Name:        "dispatch_count",
+			Description: "Number of events dispatched to a sink",
+			Measure:     dispatchLatencyM,
+			Aggregation: view.Count(),
+			TagKeys:     append(routerKeys, responseCodeKey, responseCodeClassKey),
+		},
+	)
+}
+
+// Reporter records the measures of a given router.
+type Reporter struct {
+	namespace string
+	name      string
+}
+
+// NewReporter returns a Reporter which tags measures with the given router
+// namespace and name.
+func NewReporter(namespace, name string) *Reporter {
+	return &Reporter{
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// ReportEventReceived records the reception of an event by the router.
+func (r *Reporter) ReportEventReceived(ctx context.Context) {
+	r.record(ctx, eventCountM.M(1))
+}
+
+// ReportFilterResult records the outcome of the evaluation of an event by a
+// Filter, or the failed evaluation of a condition by another router.
+func (r *Reporter) ReportFilterResult(ctx context.Context, res FilterResult) {
+	r.record(ctx, filterResultCountM.M(1),
+		tag.Insert(resultKey, string(res)),
+	)
+}
+
+// ReportSplitSize records the number of events produced by splitting an
+// event.
+func (r *Reporter) ReportSplitSize(ctx context.Context, size int) {
+	r.record(ctx, splitSizeM.M(int64(size)))
+}
+
+// ReportDispatch records the dispatching of an event to a sink. Requests which
+// didn't receive any response are reported with a response code of 0.
+func (r *Reporter) ReportDispatch(ctx context.Context, resp *http.Response, latency time.Duration) {
+	var responseCode int
+	if resp != nil {
+		responseCode = resp.StatusCode
+	}
+
+	r.record(ctx, dispatchLatencyM.M(float64(latency)/float64(time.Millisecond)),
+		tag.Insert(responseCodeKey, strconv.Itoa(responseCode)),
+		tag.Insert(responseCodeClassKey, metrics.ResponseCodeClass(responseCode)),
+	)
+}
+
+// record records the given measurement, tagged with the router's namespace
+// and name as well as the given tag mutators.
+func (r *Reporter) record(ctx context.Context, m stats.Measurement, mutators ...tag.Mutator) {
+	mutators = append(mutators,
+		tag.Insert(namespaceKey, r.namespace),
+		tag.Insert(nameKey, r.name),
+	)
+	metrics.Record(ctx, m, stats.WithTags(mutators...))
+}
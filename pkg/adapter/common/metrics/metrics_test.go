@@ -0,0 +1,107 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"knative.dev/pkg/metrics/metricstest"
+	_ "knative.dev/pkg/metrics/testing"
+)
+
+func TestReporter(t *testing.T) {
+	const ns, routerName = "ns", "router"
+
+	tags := func(kv ...string) map[string]string {
+		m := map[string]string{
+			"namespace_name": ns,
+			"router_name":    routerName,
+		}
+		for i := 0; i < len(kv); i += 2 {
+			m[kv[i]] = kv[i+1]
+		}
+		return m
+	}
+
+	testCases := map[string]struct {
+		report func(context.Context, *Reporter)
+		check  func(*testing.T)
+	}{
+		"received events": {
+			report: func(ctx context.Context, r *Reporter) {
+				r.ReportEventReceived(ctx)
+				r.ReportEventReceived(ctx)
+			},
+			check: func(t *testing.T) {
+				metricstest.CheckCountData(t, "event_count", tags(), 2)
+			},
+		},
+		"dropped events": {
+			report: func(ctx context.Context, r *Reporter) {
+				r.ReportFilterResult(ctx, FilterResultDrop)
+				r.ReportFilterResult(ctx, FilterResultDrop)
+			},
+			check: func(t *testing.T) {
+				metricstest.CheckCountData(t, "filter_result_count", tags("result", "drop"), 2)
+			},
+		},
+		"split size": {
+			report: func(ctx context.Context, r *Reporter) {
+				r.ReportSplitSize(ctx, 3)
+				r.ReportSplitSize(ctx, 5)
+			},
+			check: func(t *testing.T) {
+				metricstest.CheckDistributionData(t, "split_size", tags(), 2, 3, 5)
+			},
+		},
+		"dispatch with response": {
+			report: func(ctx context.Context, r *Reporter) {
+				r.ReportDispatch(ctx, &http.Response{StatusCode: http.StatusAccepted}, 5*time.Millisecond)
+			},
+			check: func(t *testing.T) {
+				dispatchTags := tags("response_code", "202", "response_code_class", "2xx")
+				metricstest.CheckDistributionData(t, "dispatch_latencies", dispatchTags, 1, 5, 5)
+				metricstest.CheckCountData(t, "dispatch_count", dispatchTags, 1)
+			},
+		},
+		"dispatch without response": {
+			report: func(ctx context.Context, r *Reporter) {
+				r.ReportDispatch(ctx, nil, time.Second)
+			},
+			check: func(t *testing.T) {
+				metricstest.CheckCountData(t, "dispatch_count", tags("response_code", "0", "response_code_class", "0xx"), 1)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			metricstest.Unregister("event_count", "filter_result_count", "split_size",
+				"dispatch_latencies", "dispatch_count")
+			if err := registerViews(); err != nil {
+				t.Fatalf("Failed to register views: %v", err)
+			}
+
+			tc.report(context.Background(), NewReporter(ns, routerName))
+			tc.check(t)
+		})
+	}
+}
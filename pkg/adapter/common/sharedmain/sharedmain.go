@@ -21,6 +21,7 @@ import (
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/signals"
 
+	"github.com/triggermesh/routing/pkg/adapter/common/controller"
 	"github.com/triggermesh/routing/pkg/adapter/common/env"
 )
 
@@ -32,6 +33,7 @@ type namedAdapterConstructor func(component string) adapter.AdapterConstructor
 //  * process environment variables
 //  * enable leader election / HA
 //  * set the scope to a single namespace
+//  * set the scope to a single router, for dedicated adapters
 //  * inject the given controller constructor
 func MainWithController(envCtor env.ConfigConstructor,
 	cCtor namedControllerConstructor, aCtor namedAdapterConstructor) {
@@ -43,6 +45,7 @@ func MainWithController(envCtor env.ConfigConstructor,
 	ctx := signals.NewContext()
 	ctx = adapter.WithHAEnabled(ctx)
 	ctx = injection.WithNamespaceScope(ctx, ns)
+	ctx = controller.WithScope(ctx, controller.NewScope(envAcc.GetRouterName()))
 	ctx = adapter.WithController(ctx, cCtor(component))
 
 	adapter.MainWithEnv(ctx, component, envAcc, aCtor(component))
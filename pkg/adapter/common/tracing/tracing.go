@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing contains helpers for tracing the handling of events by
+// router adapters.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/trace"
+)
+
+// Names of the spans created by router adapters.
+const (
+	SpanReceive   = "receive"
+	SpanEvaluate  = "evaluate"
+	SpanTransform = "transform"
+	SpanSplit     = "split"
+	SpanDispatch  = "dispatch"
+)
+
+// Attribute keys of the spans created by router adapters.
+const (
+	attrRouterKind      = "routing.router.kind"
+	attrRouterNamespace = "routing.router.namespace"
+	attrRouterName      = "routing.router.name"
+	attrEventID         = "cloudevents.id"
+	attrEventType       = "cloudevents.type"
+	attrEventSource     = "cloudevents.source"
+)
+
+var format = &tracecontext.HTTPFormat{}
+
+// StartReceiveSpan starts a span for the handling of the given event by a
+// router. If the event carries a distributed tracing extension, the span is
+// linked to the span this extension refers to.
+func StartReceiveSpan(ctx context.Context, kind, namespace, name string, e *event.Event) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, kind+"."+SpanReceive, trace.WithSpanKind(trace.SpanKindServer))
+
+	span.AddAttributes(
+		trace.StringAttribute(attrRouterKind, kind),
+		trace.StringAttribute(attrRouterNamespace, namespace),
+		trace.StringAttribute(attrRouterName, name),
+	)
+	span.AddAttributes(EventAttributes(e)...)
+
+	if sc, ok := SpanContextFromEvent(e); ok && sc.TraceID != span.SpanContext().TraceID {
+		span.AddLink(trace.Link{
+			TraceID: sc.TraceID,
+			SpanID:  sc.SpanID,
+			Type:    trace.LinkTypeParent,
+		})
+	}
+
+	return ctx, span
+}
+
+// StartSpan starts a child span of the span contained in the given context.
+func StartSpan(ctx context.Context, name string, attrs ...trace.Attribute) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, name)
+	span.AddAttributes(attrs...)
+	return ctx, span
+}
+
+// EventAttributes returns the span attributes which identify the given event.
+func EventAttributes(e *event.Event) []trace.Attribute {
+	return []trace.Attribute{
+		trace.StringAttribute(attrEventID, e.ID()),
+		trace.StringAttribute(attrEventType, e.Type()),
+		trace.StringAttribute(attrEventSource, e.Source()),
+	}
+}
+
+// SetError marks the given span as failed.
+func SetError(span *trace.Span, err error) {
+	span.SetStatus(trace.Status{
+		Code:    trace.StatusCodeUnknown,
+		Message: err.Error(),
+	})
+}
+
+// SetResponseStatus sets the status of the given span according to the given
+// HTTP response.
+func SetResponseStatus(span *trace.Span, resp *http.Response) {
+	span.AddAttributes(trace.Int64Attribute(ochttp.StatusCodeAttribute, int64(resp.StatusCode)))
+	span.SetStatus(ochttp.TraceStatus(resp.StatusCode, resp.Status))
+}
+
+// SetEventSpanContext sets the distributed tracing extension of the given
+// event to the span contained in the given context, so that consumers of the
+// event can relate their own spans to it.
+func SetEventSpanContext(ctx context.Context, e *event.Event) {
+	span := trace.FromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	tp, ts := format.SpanContextToHeaders(span.SpanContext())
+	e.SetExtension(extensions.TraceParentExtension, tp)
+
+	// a nil value removes any tracestate inherited from the parent event
+	var tsVal interface{}
+	if ts != "" {
+		tsVal = ts
+	}
+	e.SetExtension(extensions.TraceStateExtension, tsVal)
+}
+
+// SpanContextFromEvent returns the span context contained in the distributed
+// tracing extension of the given event.
+func SpanContextFromEvent(e *event.Event) (trace.SpanContext, bool) {
+	dte, ok := extensions.GetDistributedTracingExtension(*e)
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+	return format.SpanContextFromHeaders(dte.TraceParent, dte.TraceState)
+}
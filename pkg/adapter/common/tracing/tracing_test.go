@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+func TestEventSpanContext(t *testing.T) {
+	testCases := map[string]struct {
+		withSpan   bool
+		traceState string
+	}{
+		"without span": {},
+		"with span": {
+			withSpan: true,
+		},
+		"replaces inherited tracestate": {
+			withSpan:   true,
+			traceState: "vendor=value",
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			e := cloudevents.NewEvent()
+			if tc.traceState != "" {
+				e.SetExtension(extensions.TraceParentExtension, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+				e.SetExtension(extensions.TraceStateExtension, tc.traceState)
+			}
+
+			ctx := context.Background()
+			if !tc.withSpan {
+				SetEventSpanContext(ctx, &e)
+				_, ok := SpanContextFromEvent(&e)
+				assert.False(t, ok)
+				return
+			}
+
+			ctx, span := trace.StartSpan(ctx, "test", trace.WithSampler(trace.AlwaysSample()))
+			defer span.End()
+
+			SetEventSpanContext(ctx, &e)
+
+			sc, ok := SpanContextFromEvent(&e)
+			assert.True(t, ok)
+			assert.Equal(t, span.SpanContext().TraceID, sc.TraceID)
+			assert.Equal(t, span.SpanContext().SpanID, sc.SpanID)
+			assert.NotContains(t, e.Extensions(), extensions.TraceStateExtension)
+		})
+	}
+}
@@ -22,28 +22,40 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/event"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	pkgadapter "knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/eventing/pkg/kncloudevents"
 	"knative.dev/eventing/pkg/utils"
-	"knative.dev/pkg/injection"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/logging"
 
+	"github.com/triggermesh/routing/pkg/adapter/common/delivery"
+	"github.com/triggermesh/routing/pkg/adapter/common/env"
+	"github.com/triggermesh/routing/pkg/adapter/common/metrics"
+	"github.com/triggermesh/routing/pkg/adapter/common/tracing"
 	routingv1alpha1 "github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
-	informerv1alpha1 "github.com/triggermesh/routing/pkg/client/generated/injection/informers/flow/v1alpha1/filter"
-	routinglisters "github.com/triggermesh/routing/pkg/client/generated/listers/flow/v1alpha1"
 	"github.com/triggermesh/routing/pkg/eventfilter"
 	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+	"github.com/triggermesh/routing/pkg/eventtransform"
 )
 
 const serverPort int = 8080
 
+// extensionError is the name of the CloudEvent extension which describes the
+// error that occurred while evaluating an event routed to the error sink.
+const extensionError = "celerror"
+
 // Handler parses Cloud Events, determines if they pass a filter, and sends them to a subscriber.
 type Handler struct {
 	// receiver receives incoming HTTP requests
@@ -51,41 +63,63 @@ type Handler struct {
 	// sender sends requests to downstream services
 	sender *kncloudevents.HTTPMessageSender
 
-	filterLister routinglisters.FilterNamespaceLister
-	logger       *zap.SugaredLogger
+	logger *zap.SugaredLogger
 
-	// expressions is the map of trigger refs with precompiled CEL expressions
-	// TODO (tzununbekov): Add cleanup
-	expressions *expressionStorage
+	// filters is the routing table of the Filters served by the adapter
+	filters *routingTableStore
 }
 
 // NewEnvConfig satisfies env.ConfigConstructor.
 // Returns an accessor for the source's adapter envConfig.
-func NewEnvConfig() pkgadapter.EnvConfigAccessor {
-	return &pkgadapter.EnvConfig{}
+func NewEnvConfig() env.ConfigAccessor {
+	return &env.Config{}
 }
 
 // NewAdapter creates a new Handler and its associated MessageReceiver. The caller is responsible for
 // Start()ing the returned Handler.
-func NewAdapter(ctx context.Context, envAcc pkgadapter.EnvConfigAccessor, ceClient cloudevents.Client) pkgadapter.Adapter {
-	logger := logging.FromContext(ctx)
+func NewAdapter(component string) pkgadapter.AdapterConstructor {
+	return func(ctx context.Context, _ pkgadapter.EnvConfigAccessor,
+		ceClient cloudevents.Client) pkgadapter.Adapter {
+		logger := logging.FromContext(ctx)
 
-	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
-	if err != nil {
-		logger.Panicf("failed to create message sender: %v", err)
-	}
+		sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+		if err != nil {
+			logger.Panicf("failed to create message sender: %v", err)
+		}
+
+		if err := metrics.RegisterViews(); err != nil {
+			logger.Panicf("failed to register metrics views: %v", err)
+		}
 
-	informer := informerv1alpha1.Get(ctx)
-	ns := injection.GetNamespaceScope(ctx)
+		return &Handler{
+			receiver: kncloudevents.NewHTTPMessageReceiver(serverPort),
+			sender:   sender,
+			logger:   logger,
+
+			filters: newRoutingTableStore(),
+		}
+	}
+}
 
-	return &Handler{
-		receiver:     kncloudevents.NewHTTPMessageReceiver(serverPort),
-		sender:       sender,
-		filterLister: informer.Lister().Filters(ns),
-		logger:       logger,
+// RegisterHandlerFor implements MTAdapter.
+func (h *Handler) RegisterHandlerFor(ctx context.Context, f *routingv1alpha1.Filter) error {
+	prev, _ := h.filters.get(f.Name)
 
-		expressions: newExpressionStorage(),
+	e, err := newFilterEntry(f, prev)
+	if err != nil {
+		// stop serving a state which no longer matches the Filter
+		h.filters.delete(f.Name)
+		return err
 	}
+	h.filters.set(e)
+
+	return nil
+}
+
+// DeregisterHandlerFor implements MTAdapter.
+func (h *Handler) DeregisterHandlerFor(ctx context.Context, key types.NamespacedName) error {
+	h.filters.delete(key.Name)
+	return nil
 }
 
 // Start begins to receive messages for the handler.
@@ -126,44 +160,136 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 
 	h.logger.Debug("Received message", zap.Any("filter", filter))
 
-	f, err := h.getFilter(filter)
-	if err != nil {
-		h.logger.Info("Unable to get the Filter", zap.Error(err), zap.Any("filter", filter))
+	f, exists := h.filters.get(filter)
+	if !exists {
+		h.logger.Info("Filter not found or not ready", zap.Any("filter", filter))
 		writer.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	cond, exists := h.expressions.get(f.UID, f.Generation)
-	if !exists {
-		cond, err = cel.CompileExpression(f.Spec.Expression)
-		if err != nil {
-			h.logger.Info("Failed to compile filter expression", zap.Error(err), zap.Any("filter", filter))
-			writer.WriteHeader(http.StatusBadRequest)
+	reporter := metrics.NewReporter(f.namespace, f.name)
+	reporter.ReportEventReceived(ctx)
+
+	ctx, span := tracing.StartReceiveSpan(ctx, "filter", f.namespace, f.name, event)
+	defer span.End()
+
+	filterResult, err := h.evaluate(ctx, f.compiled.condition, event)
+	if err != nil {
+		h.logger.Info("Failed to evaluate filter expression", zap.Error(err), zap.Any("filter", filter))
+		reporter.ReportFilterResult(ctx, metrics.FilterResultError)
+
+		switch f.onError {
+		case routingv1alpha1.ErrorPolicyDrop:
+			return
+		case routingv1alpha1.ErrorPolicyRoute:
+			h.sendError(ctx, writer, request.Header, f.errorSinkURI, event, err, f.delivery, reporter)
 			return
 		}
-		h.expressions.set(f.UID, f.Generation, cond)
+	} else {
+		if filterResult == eventfilter.FailFilter {
+			reporter.ReportFilterResult(ctx, metrics.FilterResultDrop)
+			return
+		}
+		reporter.ReportFilterResult(ctx, metrics.FilterResultPass)
 	}
 
-	filterResult := filterEvent(ctx, cond, *event)
-	if filterResult == eventfilter.FailFilter {
-		return
+	if f.compiled.transform != nil {
+		out := event.Clone()
+		updateAttributes(f.ceOverrides, &out)
+
+		err := h.transform(ctx, f.compiled.transform, event, &out)
+		if err == nil {
+			h.send(ctx, writer, request.Header, f.sinkURI, &out, f.delivery, reporter)
+			return
+		}
+
+		h.logger.Info("Failed to transform event", zap.Error(err), zap.Any("filter", filter))
+
+		// with the "pass" policy, events which can't be transformed
+		// are forwarded unmodified
+		switch f.onError {
+		case routingv1alpha1.ErrorPolicyDrop:
+			return
+		case routingv1alpha1.ErrorPolicyRoute:
+			h.sendError(ctx, writer, request.Header, f.errorSinkURI, event, err, f.delivery, reporter)
+			return
+		}
 	}
 
-	event = updateAttributes(f.Status, event)
-	h.send(ctx, writer, request.Header, f.Status.SinkURI.String(), event)
+	event = updateAttributes(f.ceOverrides, event)
+	h.send(ctx, writer, request.Header, f.sinkURI, event, f.delivery, reporter)
 }
 
-func updateAttributes(fs routingv1alpha1.RouterStatus, event *event.Event) *event.Event {
-	if len(fs.CloudEventAttributes) == 1 {
-		event.SetType(fs.CloudEventAttributes[0].Type)
-		event.SetSource(fs.CloudEventAttributes[0].Source)
+// compileFilter compiles the expression and the transform of the given
+// Filter.
+func compileFilter(f *routingv1alpha1.Filter) (compiledFilter, error) {
+	var compiled compiledFilter
+
+	cond, err := cel.CompileExpression(f.Spec.Expression)
+	if err != nil {
+		return compiled, fmt.Errorf("compiling expression: %w", err)
+	}
+	compiled.condition = cond
+
+	if t := f.Spec.Transform; t != nil {
+		if compiled.transform, err = eventtransform.Compile(t.TransformOperations()); err != nil {
+			return compiled, fmt.Errorf("compiling transform: %w", err)
+		}
+	}
+
+	return compiled, nil
+}
+
+// transform applies the given transform to the event out within its own
+// span. Expressions and templates are evaluated against the received event.
+func (h *Handler) transform(ctx context.Context, t *eventtransform.Transformer, received, out *cloudevents.Event) error {
+	ctx, span := tracing.StartSpan(ctx, "filter."+tracing.SpanTransform)
+	defer span.End()
+
+	if err := t.ApplyTo(ctx, received, out); err != nil {
+		tracing.SetError(span, err)
+		return err
+	}
+	return nil
+}
+
+// evaluate evaluates the given event against the filter condition within its
+// own span.
+func (h *Handler) evaluate(ctx context.Context, cond cel.ConditionalFilter, event *cloudevents.Event) (eventfilter.FilterResult, error) {
+	ctx, span := tracing.StartSpan(ctx, "filter."+tracing.SpanEvaluate)
+	defer span.End()
+
+	res, err := filterEvent(ctx, cond, *event)
+	if err != nil {
+		tracing.SetError(span, err)
+		return res, err
+	}
+
+	span.AddAttributes(trace.StringAttribute("filter.result", string(res)))
+	return res, nil
+}
+
+func updateAttributes(overrides *duckv1.CloudEventAttributes, event *event.Event) *event.Event {
+	if overrides != nil {
+		event.SetType(overrides.Type)
+		event.SetSource(overrides.Source)
 	}
 	return event
 }
 
-func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers http.Header, target string, event *cloudevents.Event) {
+func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers http.Header, target string,
+	event *cloudevents.Event, dc delivery.Config, reporter *metrics.Reporter) {
+
 	// send the event to trigger's subscriber
-	response, err := h.sendEvent(ctx, headers, target, event)
+	response, err := h.sendEvent(ctx, headers, target, event, dc.Retry, reporter)
+	if dc.DeadLetterSink != nil && delivery.Failed(response, err) {
+		if response != nil {
+			response.Body.Close()
+		}
+		h.logger.Info("Delivery failed, sending event to the dead letter sink", zap.Error(err), zap.Any("target", target))
+		h.deadLetter(ctx, writer, headers, dc.DeadLetterSink.String(), event, reporter)
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to send event", zap.Error(err))
 		writer.WriteHeader(http.StatusInternalServerError)
@@ -179,7 +305,50 @@ func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers
 	}
 }
 
-func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target string, event *cloudevents.Event) (*http.Response, error) {
+// deadLetter sends an event which couldn't be delivered to the dead letter
+// sink, and acknowledges it if it was accepted there.
+func (h *Handler) deadLetter(ctx context.Context, writer http.ResponseWriter, headers http.Header,
+	target string, event *cloudevents.Event, reporter *metrics.Reporter) {
+
+	response, err := h.sendEvent(ctx, headers, target, event, nil, reporter)
+	if delivery.Failed(response, err) {
+		if response != nil {
+			response.Body.Close()
+		}
+		h.logger.Error("failed to send event to the dead letter sink", zap.Error(err))
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	response.Body.Close()
+
+	writer.WriteHeader(http.StatusAccepted)
+}
+
+// sendError sends the original event to the error sink, along with an
+// extension describing the error which occurred while evaluating it.
+func (h *Handler) sendError(ctx context.Context, writer http.ResponseWriter, headers http.Header,
+	target string, event *cloudevents.Event, evalErr error, dc delivery.Config, reporter *metrics.Reporter) {
+
+	if target == "" {
+		h.logger.Error("Unable to route event to the error sink: URI is not set")
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	event.SetExtension(extensionError, evalErr.Error())
+	h.send(ctx, writer, headers, target, event, dc, reporter)
+}
+
+func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target string, event *cloudevents.Event,
+	retry *kncloudevents.RetryConfig, reporter *metrics.Reporter) (*http.Response, error) {
+
+	ctx, span := tracing.StartSpan(ctx, "filter."+tracing.SpanDispatch,
+		trace.StringAttribute(ochttp.URLAttribute, target))
+	defer span.End()
+	span.AddAttributes(tracing.EventAttributes(event)...)
+
+	tracing.SetEventSpanContext(ctx, event)
+
 	// Send the event to the subscriber
 	req, err := h.sender.NewCloudEventRequestWithTarget(ctx, target)
 	if err != nil {
@@ -197,9 +366,14 @@ func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target str
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
 
-	resp, err := h.sender.Send(req)
+	start := time.Now()
+	resp, err := h.sender.SendWithRetries(req, retry)
+	reporter.ReportDispatch(ctx, resp, time.Since(start))
 	if err != nil {
 		err = fmt.Errorf("failed to dispatch message: %w", err)
+		tracing.SetError(span, err)
+	} else {
+		tracing.SetResponseStatus(span, resp)
 	}
 
 	return resp, err
@@ -254,23 +428,18 @@ func (h *Handler) writeResponse(ctx context.Context, writer http.ResponseWriter,
 	return resp.StatusCode, nil
 }
 
-func (h *Handler) getFilter(filter string) (*routingv1alpha1.Filter, error) {
-	return h.filterLister.Get(filter)
-}
-
-func filterEvent(ctx context.Context, filter cel.ConditionalFilter, event cloudevents.Event) eventfilter.FilterResult {
-	var filters eventfilter.Filters
-	if filter.Expression != nil {
-		filters = append(filters, &filter)
+func filterEvent(ctx context.Context, filter cel.ConditionalFilter, event cloudevents.Event) (eventfilter.FilterResult, error) {
+	if filter.Expression == nil {
+		return eventfilter.NoFilter, nil
 	}
 
-	return filters.Filter(ctx, event)
+	return filter.Evaluate(ctx, event)
 }
 
 func parseRequestURI(path string) (string, error) {
 	parts := strings.Split(path, "/")
-	if len(parts) != 1 {
+	if len(parts) != 2 {
 		return "", fmt.Errorf("incorrect number of parts in the path, expected 2, actual %d, '%s'", len(parts), path)
 	}
-	return parts[0], nil
+	return parts[1], nil
 }
@@ -0,0 +1,213 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/metrics/metricstest"
+	_ "knative.dev/pkg/metrics/testing"
+	"knative.dev/pkg/ptr"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/metrics"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestDelivery(t *testing.T) {
+	testCases := map[string]struct {
+		sinkFailures    int32
+		dlsStatus       int
+		expectStatus    int
+		expectSinkCalls int32
+		expectDLSCalls  int32
+	}{
+		"delivered after retries": {
+			sinkFailures:    2,
+			expectStatus:    http.StatusAccepted,
+			expectSinkCalls: 3,
+			expectDLSCalls:  0,
+		},
+		"dead-lettered": {
+			sinkFailures:    10,
+			dlsStatus:       http.StatusAccepted,
+			expectStatus:    http.StatusAccepted,
+			expectSinkCalls: 3,
+			expectDLSCalls:  1,
+		},
+		"dead letter sink fails": {
+			sinkFailures:    10,
+			dlsStatus:       http.StatusInternalServerError,
+			expectStatus:    http.StatusInternalServerError,
+			expectSinkCalls: 3,
+			expectDLSCalls:  1,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			var sinkCalls, dlsCalls int32
+
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if atomic.AddInt32(&sinkCalls, 1) <= tc.sinkFailures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer sink.Close()
+
+			dls := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				atomic.AddInt32(&dlsCalls, 1)
+				w.WriteHeader(tc.dlsStatus)
+			}))
+			defer dls.Close()
+
+			dlsURI, err := apis.ParseURL(dls.URL)
+			require.NoError(t, err)
+			sinkURI, err := apis.ParseURL(sink.URL)
+			require.NoError(t, err)
+
+			linear := eventingduckv1.BackoffPolicyLinear
+
+			f := &v1alpha1.Filter{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  "ns",
+					Name:       "test",
+					Generation: 1,
+				},
+				Spec: v1alpha1.FilterSpec{
+					Expression: `ce.type == "test"`,
+					Delivery: &eventingduckv1.DeliverySpec{
+						Retry:          ptr.Int32(2),
+						BackoffPolicy:  &linear,
+						BackoffDelay:   ptr.String("PT0.01S"),
+						DeadLetterSink: &duckv1.Destination{URI: dlsURI},
+					},
+				},
+			}
+			f.Status.SinkURI = sinkURI
+			// the adapter only knows about the resolved URI of the dead
+			// letter sink, reported by the controller
+			f.Status.DeadLetterSinkURI = dlsURI
+
+			h := newTestHandler(t)
+			r := &Reconciler{adapter: h}
+			require.NoError(t, r.ObserveKind(context.Background(), f))
+
+			assert.Equal(t, tc.expectStatus, postEvent(h, "/test"))
+			assert.Equal(t, tc.expectSinkCalls, atomic.LoadInt32(&sinkCalls))
+			assert.Equal(t, tc.expectDLSCalls, atomic.LoadInt32(&dlsCalls))
+		})
+	}
+}
+
+func TestOnError(t *testing.T) {
+	require.NoError(t, metrics.RegisterViews())
+
+	var sinkCalls, errorSinkCalls int32
+	var celError atomic.Value
+
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&sinkCalls, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	errorSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&errorSinkCalls, 1)
+		celError.Store(r.Header.Get("ce-" + extensionError))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer errorSink.Close()
+
+	sinkURI, err := apis.ParseURL(sink.URL)
+	require.NoError(t, err)
+	errorSinkURI, err := apis.ParseURL(errorSink.URL)
+	require.NoError(t, err)
+
+	f := &v1alpha1.Filter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "test",
+		},
+		Spec: v1alpha1.FilterSpec{
+			// fails to evaluate for events without this extension
+			Expression: `ce.extensions["missing"] == "x"`,
+		},
+	}
+	f.Status.SinkURI = sinkURI
+	f.Status.ErrorSinkURI = errorSinkURI
+
+	errorTags := map[string]string{
+		"namespace_name": f.Namespace,
+		"router_name":    f.Name,
+		"result":         string(metrics.FilterResultError),
+	}
+
+	h := newTestHandler(t)
+	r := &Reconciler{adapter: h}
+
+	// the error result is reported regardless of the policy, so it
+	// accumulates across test cases
+	testCases := []struct {
+		name            string
+		onError         v1alpha1.ErrorPolicy
+		expectStatus    int
+		expectSinkCalls int32
+		expectErrCalls  int32
+	}{
+		{name: "unset", onError: "", expectStatus: http.StatusAccepted, expectSinkCalls: 1},
+		{name: "pass", onError: v1alpha1.ErrorPolicyPass, expectStatus: http.StatusAccepted, expectSinkCalls: 1},
+		{name: "drop", onError: v1alpha1.ErrorPolicyDrop, expectStatus: http.StatusOK},
+		{name: "route", onError: v1alpha1.ErrorPolicyRoute, expectStatus: http.StatusAccepted, expectErrCalls: 1},
+	}
+
+	for i, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&sinkCalls, 0)
+			atomic.StoreInt32(&errorSinkCalls, 0)
+
+			f := f.DeepCopy()
+			f.Spec.OnError = tc.onError
+			f.Generation = int64(i + 1)
+			require.NoError(t, r.ObserveKind(context.Background(), f))
+
+			assert.Equal(t, tc.expectStatus, postEvent(h, "/test"))
+			assert.Equal(t, tc.expectSinkCalls, atomic.LoadInt32(&sinkCalls))
+			assert.Equal(t, tc.expectErrCalls, atomic.LoadInt32(&errorSinkCalls))
+			if tc.expectErrCalls > 0 {
+				assert.Contains(t, celError.Load(), "no such key")
+			}
+
+			metricstest.CheckCountData(t, "filter_result_count", errorTags, int64(i+1))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	pkgadapter "knative.dev/eventing/pkg/adapter/v2"
+	pkgcontroller "knative.dev/pkg/controller"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/controller"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	routingclient "github.com/triggermesh/routing/pkg/client/generated/injection/client"
+	informerv1alpha1 "github.com/triggermesh/routing/pkg/client/generated/injection/informers/flow/v1alpha1/filter"
+	reconcilerv1alpha1 "github.com/triggermesh/routing/pkg/client/generated/injection/reconciler/flow/v1alpha1/filter"
+)
+
+// MTAdapter allows the multi-tenant adapter to expose methods the reconciler
+// can call while reconciling a source object.
+type MTAdapter interface {
+	// Registers a HTTP handler for the given source.
+	RegisterHandlerFor(context.Context, *v1alpha1.Filter) error
+	// Deregisters the HTTP handler for the source with the given key.
+	DeregisterHandlerFor(context.Context, types.NamespacedName) error
+}
+
+// NewController returns a constructor for the event source's Reconciler.
+func NewController(component string) pkgadapter.ControllerConstructor {
+	return func(ctx context.Context, a pkgadapter.Adapter) *pkgcontroller.Impl {
+		client := routingclient.Get(ctx).FlowV1alpha1()
+
+		scope := controller.ScopeFromContext(ctx)
+
+		r := &Reconciler{
+			adapter: a.(MTAdapter),
+			scope:   scope,
+			patch: func(ctx context.Context, ns, name string, patch []byte) error {
+				_, err := client.Filters(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+				return err
+			},
+		}
+		impl := reconcilerv1alpha1.NewImpl(ctx, r, controller.Opts(component))
+
+		informerv1alpha1.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: scope.Filter,
+			Handler:    pkgcontroller.HandleAll(impl.Enqueue),
+		})
+
+		return impl
+	}
+}
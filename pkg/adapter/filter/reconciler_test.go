@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestReconcilerEviction(t *testing.T) {
+	var received int32
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	f := &v1alpha1.Filter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "test",
+			Generation: 1,
+		},
+		Spec: v1alpha1.FilterSpec{
+			Expression: `ce.type == "test"`,
+		},
+	}
+	sinkURI, err := apis.ParseURL(sink.URL)
+	require.NoError(t, err)
+	f.Status.SinkURI = sinkURI
+
+	h := newTestHandler(t)
+	r := &Reconciler{adapter: h}
+	ctx := context.Background()
+
+	require.NoError(t, r.ObserveKind(ctx, f))
+	assert.Equal(t, http.StatusAccepted, postEvent(h, "/test"))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&received))
+
+	t.Run("deleted object", func(t *testing.T) {
+		require.NoError(t, r.ObserveKind(ctx, f))
+		require.NoError(t, r.ObserveDeletion(ctx, keyOf(f)))
+
+		assert.Equal(t, http.StatusBadRequest, postEvent(h, "/test"))
+		assert.EqualValues(t, 1, atomic.LoadInt32(&received), "Events are no longer forwarded")
+	})
+
+	t.Run("unresolved sink", func(t *testing.T) {
+		require.NoError(t, r.ObserveKind(ctx, f))
+
+		f := f.DeepCopy()
+		f.Status.SinkURI = nil
+		assert.Error(t, r.ObserveKind(ctx, f))
+
+		assert.Equal(t, http.StatusBadRequest, postEvent(h, "/test"))
+		assert.EqualValues(t, 1, atomic.LoadInt32(&received), "Events are no longer forwarded")
+	})
+}
+
+// newTestHandler returns a Handler which serves no Filter.
+func newTestHandler(t *testing.T) *Handler {
+	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	require.NoError(t, err)
+
+	return &Handler{
+		sender:  sender,
+		logger:  zap.NewNop().Sugar(),
+		filters: newRoutingTableStore(),
+	}
+}
+
+// postEvent sends a structured CloudEvent of type "test" to the given path
+// of the Handler, and returns the status code of the response.
+func postEvent(h *Handler, path string) int {
+	const event = `{"specversion":"1.0","id":"0","type":"test","source":"test"}`
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(event))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec.Code
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/delivery"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+	"github.com/triggermesh/routing/pkg/eventtransform"
+)
+
+// compiledFilter holds the compiled expression and transform of a Filter.
+type compiledFilter struct {
+	condition cel.ConditionalFilter
+	// transform is nil when the Filter doesn't transform events.
+	transform *eventtransform.Transformer
+}
+
+// filterEntry is the resolved state of a Filter, i.e. everything the adapter
+// needs to know about a Filter to handle the events it receives. Entries are
+// immutable once they are part of a routingTable.
+type filterEntry struct {
+	namespace  string
+	name       string
+	uid        types.UID
+	generation int64
+
+	compiled compiledFilter
+
+	sinkURI      string
+	errorSinkURI string
+	// ceOverrides is nil when the Filter doesn't override the context
+	// attributes of the events it forwards.
+	ceOverrides *duckv1.CloudEventAttributes
+
+	onError  v1alpha1.ErrorPolicy
+	delivery delivery.Config
+}
+
+// newFilterEntry resolves the state of the given Filter. The compiled
+// expressions of prev are reused if they match the Filter's generation.
+func newFilterEntry(f *v1alpha1.Filter, prev *filterEntry) (*filterEntry, error) {
+	e := &filterEntry{
+		namespace:  f.Namespace,
+		name:       f.Name,
+		uid:        f.UID,
+		generation: f.Generation,
+
+		sinkURI: f.Status.SinkURI.String(),
+
+		onError: f.Spec.OnError,
+	}
+
+	if prev != nil && prev.uid == f.UID && prev.generation == f.Generation {
+		e.compiled = prev.compiled
+	} else {
+		compiled, err := compileFilter(f)
+		if err != nil {
+			return nil, err
+		}
+		e.compiled = compiled
+	}
+
+	if u := f.Status.ErrorSinkURI; u != nil {
+		e.errorSinkURI = u.String()
+	}
+
+	if attrs := f.Status.CloudEventAttributes; len(attrs) == 1 {
+		e.ceOverrides = attrs[0].DeepCopy()
+	}
+
+	dc, err := delivery.NewConfig(f.Spec.Delivery, f.Status.DeadLetterSinkURI.DeepCopy())
+	if err != nil {
+		return nil, fmt.Errorf("invalid delivery options: %w", err)
+	}
+	e.delivery = dc
+
+	return e, nil
+}
+
+// routingTable maps the names of Filters to their resolved state.
+type routingTable map[string]*filterEntry
+
+// routingTableStore holds a routingTable which can be read without locking.
+// Writers replace the whole table with an updated copy.
+type routingTableStore struct {
+	// serializes writers
+	mu sync.Mutex
+	// always holds a routingTable
+	table atomic.Value
+}
+
+func newRoutingTableStore() *routingTableStore {
+	s := &routingTableStore{}
+	s.table.Store(make(routingTable))
+	return s
+}
+
+// get returns the resolved state of the Filter with the given name.
+func (s *routingTableStore) get(name string) (*filterEntry, bool) {
+	e, exists := s.table.Load().(routingTable)[name]
+	return e, exists
+}
+
+// set inserts or replaces the resolved state of a Filter.
+func (s *routingTableStore) set(e *filterEntry) {
+	s.update(func(t routingTable) {
+		t[e.name] = e
+	})
+}
+
+// delete removes the resolved state of the Filter with the given name.
+func (s *routingTableStore) delete(name string) {
+	s.update(func(t routingTable) {
+		delete(t, name)
+	})
+}
+
+// update applies the given function to a copy of the current table, and
+// atomically swaps the current table for that copy.
+func (s *routingTableStore) update(fn func(routingTable)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	curr := s.table.Load().(routingTable)
+
+	next := make(routingTable, len(curr)+1)
+	for k, v := range curr {
+		next[k] = v
+	}
+	fn(next)
+
+	s.table.Store(next)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestRoutingTableStore(t *testing.T) {
+	f := &v1alpha1.Filter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "test",
+			UID:        "00000000-0000-0000-0000-000000000000",
+			Generation: 1,
+		},
+		Spec: v1alpha1.FilterSpec{
+			Expression: `$type.(string) == "test"`,
+		},
+	}
+	f.Status.SinkURI = apis.HTTP("sink.ns")
+
+	s := newRoutingTableStore()
+	snapshot := s.table.Load().(routingTable)
+
+	e, err := newFilterEntry(f, nil)
+	require.NoError(t, err)
+	s.set(e)
+
+	got, exists := s.get("test")
+	require.True(t, exists)
+	assert.Equal(t, "http://sink.ns", got.sinkURI)
+	assert.Empty(t, snapshot, "Previous snapshots of the table are never mutated")
+
+	t.Run("status update reuses compiled expressions", func(t *testing.T) {
+		f := f.DeepCopy()
+		f.Status.SinkURI = apis.HTTP("other-sink.ns")
+
+		e, err := newFilterEntry(f, got)
+		require.NoError(t, err)
+		assert.Equal(t, "http://other-sink.ns", e.sinkURI)
+		assert.Same(t, got.compiled.condition.Expression, e.compiled.condition.Expression)
+	})
+
+	t.Run("spec update recompiles expressions", func(t *testing.T) {
+		f := f.DeepCopy()
+		f.Generation++
+		f.Spec.Expression = `$type.(string) == "other"`
+
+		e, err := newFilterEntry(f, got)
+		require.NoError(t, err)
+		assert.NotSame(t, got.compiled.condition.Expression, e.compiled.condition.Expression)
+	})
+
+	s.delete("test")
+	_, exists = s.get("test")
+	assert.False(t, exists)
+}
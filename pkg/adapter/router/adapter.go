@@ -0,0 +1,326 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	pkgadapter "knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/utils"
+	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/env"
+	"github.com/triggermesh/routing/pkg/adapter/common/metrics"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventfilter"
+)
+
+const serverPort int = 8080
+
+// Handler parses Cloud Events, determines which routes they match, and sends
+// them to the sinks of those routes.
+type Handler struct {
+	// receiver receives incoming HTTP requests
+	receiver *kncloudevents.HTTPMessageReceiver
+	// sender sends requests to downstream services
+	sender *kncloudevents.HTTPMessageSender
+
+	logger *zap.SugaredLogger
+
+	// routers is the routing table of the Routers served by the adapter
+	routers *routingTableStore
+}
+
+// NewEnvConfig satisfies env.ConfigConstructor.
+// Returns an accessor for the source's adapter envConfig.
+func NewEnvConfig() env.ConfigAccessor {
+	return &env.Config{}
+}
+
+// NewAdapter creates a new Handler and its associated MessageReceiver. The caller is responsible for
+// Start()ing the returned Handler.
+func NewAdapter(component string) pkgadapter.AdapterConstructor {
+	return func(ctx context.Context, _ pkgadapter.EnvConfigAccessor,
+		ceClient cloudevents.Client) pkgadapter.Adapter {
+		logger := logging.FromContext(ctx)
+
+		sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+		if err != nil {
+			logger.Panicf("failed to create message sender: %v", err)
+		}
+
+		if err := metrics.RegisterViews(); err != nil {
+			logger.Panicf("failed to register metrics views: %v", err)
+		}
+
+		return &Handler{
+			receiver: kncloudevents.NewHTTPMessageReceiver(serverPort),
+			sender:   sender,
+			logger:   logger,
+
+			routers: newRoutingTableStore(),
+		}
+	}
+}
+
+// RegisterHandlerFor implements MTAdapter.
+func (h *Handler) RegisterHandlerFor(ctx context.Context, rt *v1alpha1.Router) error {
+	prev, _ := h.routers.get(rt.Name)
+
+	e, err := newRouterEntry(rt, prev)
+	if err != nil {
+		// stop serving a state which no longer matches the Router
+		h.routers.delete(rt.Name)
+		return err
+	}
+	h.routers.set(e)
+
+	return nil
+}
+
+// DeregisterHandlerFor implements MTAdapter.
+func (h *Handler) DeregisterHandlerFor(ctx context.Context, key types.NamespacedName) error {
+	h.routers.delete(key.Name)
+	return nil
+}
+
+// Start begins to receive messages for the handler.
+//
+// HTTP POST requests to the root path (/) are accepted.
+//
+// This method will block until ctx is done.
+func (h *Handler) Start(ctx context.Context) error {
+	return h.receiver.StartListen(ctx, h)
+}
+
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	router, err := parseRequestURI(request.RequestURI)
+	if err != nil {
+		h.logger.Info("Unable to parse path as router", zap.Error(err), zap.String("path", request.RequestURI))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := request.Context()
+
+	message := cehttp.NewMessageFromHttpRequest(request)
+	// cannot be err, but makes linter complain about missing err check
+	//nolint
+	defer message.Finish(nil)
+
+	event, err := binding.ToEvent(ctx, message)
+	if err != nil {
+		h.logger.Warn("failed to extract event from request", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Received message", zap.Any("router", router))
+
+	e, exists := h.routers.get(router)
+	if !exists {
+		h.logger.Info("Router not found or not ready", zap.Any("router", router))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	targets := h.matchingTargets(ctx, e, *event)
+	switch len(targets) {
+	case 0:
+		h.logger.Debug("Event did not match any route", zap.Any("router", router))
+		return
+	case 1:
+		h.send(ctx, writer, request.Header, targets[0], event)
+		return
+	}
+
+	var failed bool
+	for _, target := range targets {
+		// responses of multiple sinks can not be merged into a single reply
+		if err := h.sendAndCheck(ctx, request.Header, target, event); err != nil {
+			h.logger.Error("failed to send the event", zap.Error(err), zap.String("target", target))
+			failed = true
+		}
+	}
+
+	if failed {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writer.WriteHeader(http.StatusAccepted)
+}
+
+// matchingTargets returns the URLs of the sinks the event should be
+// dispatched to, according to the Router's routing mode. Routes whose
+// condition fails to evaluate don't match the event.
+func (h *Handler) matchingTargets(ctx context.Context, e *routerEntry, event cloudevents.Event) []string {
+	var targets []string
+
+	for i := range e.routes {
+		res, err := e.routes[i].condition.Evaluate(ctx, event)
+		if err != nil {
+			h.logger.Info("Failed to evaluate route condition", zap.Error(err),
+				zap.String("router", e.name), zap.Int("route", i))
+			metrics.NewReporter(e.namespace, e.name).ReportFilterResult(ctx, metrics.FilterResultError)
+			continue
+		}
+		if res != eventfilter.PassFilter {
+			continue
+		}
+
+		targets = append(targets, e.routes[i].sinkURI)
+		if e.mode != v1alpha1.RoutingModeAllMatches {
+			break
+		}
+	}
+
+	if len(targets) == 0 && e.defaultSinkURI != "" {
+		targets = append(targets, e.defaultSinkURI)
+	}
+
+	return targets
+}
+
+func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers http.Header, target string, event *cloudevents.Event) {
+	response, err := h.sendEvent(ctx, headers, target, event)
+	if err != nil {
+		h.logger.Error("failed to send event", zap.Error(err))
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Debug("Successfully dispatched message", zap.Any("target", target))
+
+	// If there is an event in the response write it to the response
+	_, err = h.writeResponse(ctx, writer, response, target)
+	if err != nil {
+		h.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// sendAndCheck sends the event to the given target and discards the
+// response, which is considered a failure if its status code is not 2xx.
+func (h *Handler) sendAndCheck(ctx context.Context, headers http.Header, target string, event *cloudevents.Event) error {
+	resp, err := h.sendEvent(ctx, headers, target, event)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("target responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target string, event *cloudevents.Event) (*http.Response, error) {
+	// Send the event to the subscriber
+	req, err := h.sender.NewCloudEventRequestWithTarget(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	message := binding.ToMessage(event)
+	// cannot be err, but makes linter complain about missing err check
+	//nolint
+	defer message.Finish(nil)
+
+	additionalHeaders := utils.PassThroughHeaders(headers)
+	err = kncloudevents.WriteHTTPRequestWithAdditionalHeaders(ctx, message, req, additionalHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+
+	resp, err := h.sender.Send(req)
+	if err != nil {
+		err = fmt.Errorf("failed to dispatch message: %w", err)
+	}
+
+	return resp, err
+}
+
+// The return values are the status
+func (h *Handler) writeResponse(ctx context.Context, writer http.ResponseWriter, resp *http.Response, target string) (int, error) {
+	response := cehttp.NewMessageFromHttpResponse(resp)
+	// cannot be err, but makes linter complain about missing err check
+	//nolint
+	defer response.Finish(nil)
+
+	if response.ReadEncoding() == binding.EncodingUnknown {
+		// Response doesn't have a ce-specversion header nor a content-type matching a cloudevent event format
+		// Just read a byte out of the reader to see if it's non-empty, we don't care what it is,
+		// just that it is not empty. This means there was a response and it's not valid, so treat
+		// as delivery failure.
+		body := make([]byte, 1)
+		n, _ := response.BodyReader.Read(body)
+		response.BodyReader.Close()
+		if n != 0 {
+			writer.WriteHeader(http.StatusBadGateway)
+			return http.StatusBadGateway, errors.New("received a non-empty response not recognized as CloudEvent. The response MUST be or empty or a valid CloudEvent")
+		}
+		h.logger.Debug("Response doesn't contain a CloudEvent, replying with an empty response", zap.Any("target", target))
+		writer.WriteHeader(resp.StatusCode)
+		return resp.StatusCode, nil
+	}
+
+	event, err := binding.ToEvent(ctx, response)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadGateway)
+		// Malformed event, reply with err
+		return http.StatusBadGateway, err
+	}
+
+	eventResponse := binding.ToMessage(event)
+	// cannot be err, but makes linter complain about missing err check
+	//nolint
+	defer eventResponse.Finish(nil)
+
+	if err := cehttp.WriteResponseWriter(ctx, eventResponse, resp.StatusCode, writer); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to write response event: %w", err)
+	}
+
+	h.logger.Debug("Replied with a CloudEvent response", zap.Any("target", target))
+
+	return resp.StatusCode, nil
+}
+
+func parseRequestURI(path string) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("incorrect number of parts in the path, expected 2, actual %d, '%s'", len(parts), path)
+	}
+	return parts[1], nil
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/metrics/metricstest"
+	_ "knative.dev/pkg/metrics/testing"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/metrics"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestHandler(t *testing.T) {
+	sinks := newTestSinks(t)
+
+	testCases := map[string]struct {
+		mode        v1alpha1.RoutingMode
+		noDefault   bool
+		failingSink string
+		eventType   string
+		expectCode  int
+		expectSinks []string
+	}{
+		"first match": {
+			eventType:   "both",
+			expectCode:  http.StatusAccepted,
+			expectSinks: []string{"a"},
+		},
+		"all matches": {
+			mode:        v1alpha1.RoutingModeAllMatches,
+			eventType:   "both",
+			expectCode:  http.StatusAccepted,
+			expectSinks: []string{"a", "b"},
+		},
+		"single match": {
+			eventType:   "b",
+			expectCode:  http.StatusAccepted,
+			expectSinks: []string{"b"},
+		},
+		"default sink": {
+			eventType:   "none",
+			expectCode:  http.StatusAccepted,
+			expectSinks: []string{"default"},
+		},
+		"all matches with failing sink": {
+			mode:        v1alpha1.RoutingModeAllMatches,
+			failingSink: "b",
+			eventType:   "both",
+			expectCode:  http.StatusInternalServerError,
+			expectSinks: []string{"a", "b"},
+		},
+		"no match": {
+			noDefault:   true,
+			eventType:   "none",
+			expectCode:  http.StatusOK,
+			expectSinks: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			sinks.reset()
+			sinks.fail(tc.failingSink)
+
+			rt := newTestRouter(t, sinks)
+			rt.Spec.Mode = tc.mode
+			if tc.noDefault {
+				rt.Status.SinkURI = nil
+			}
+
+			h := newTestHandler(t)
+			require.NoError(t, h.RegisterHandlerFor(context.Background(), rt))
+
+			assert.Equal(t, tc.expectCode, postEvent(h, "/test", tc.eventType))
+			assert.ElementsMatch(t, tc.expectSinks, sinks.received())
+		})
+	}
+
+	t.Run("unknown router", func(t *testing.T) {
+		sinks.reset()
+
+		h := newTestHandler(t)
+		assert.Equal(t, http.StatusBadRequest, postEvent(h, "/test", "both"))
+		assert.Empty(t, sinks.received())
+	})
+}
+
+func TestHandlerEvaluationError(t *testing.T) {
+	require.NoError(t, metrics.RegisterViews())
+
+	sinks := newTestSinks(t)
+
+	rt := newTestRouter(t, sinks)
+	// fails to evaluate for events without this extension
+	rt.Spec.Routes[0].Expression = `ce.extensions["missing"] == "x"`
+
+	h := newTestHandler(t)
+	require.NoError(t, h.RegisterHandlerFor(context.Background(), rt))
+
+	errorTags := map[string]string{
+		"namespace_name": rt.Namespace,
+		"router_name":    rt.Name,
+		"result":         string(metrics.FilterResultError),
+	}
+
+	t.Run("next route matches", func(t *testing.T) {
+		sinks.reset()
+
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "b"))
+		assert.Equal(t, []string{"b"}, sinks.received())
+		metricstest.CheckCountData(t, "filter_result_count", errorTags, 1)
+	})
+
+	t.Run("default sink", func(t *testing.T) {
+		sinks.reset()
+
+		assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "none"))
+		assert.Equal(t, []string{"default"}, sinks.received())
+		metricstest.CheckCountData(t, "filter_result_count", errorTags, 2)
+	})
+}
+
+func TestNewRouterEntry(t *testing.T) {
+	sinks := newTestSinks(t)
+
+	testCases := map[string]struct {
+		mutate    func(*v1alpha1.Router)
+		expectErr string
+	}{
+		"up-to-date status": {
+			mutate: func(*v1alpha1.Router) {},
+		},
+		"outdated status": {
+			mutate: func(rt *v1alpha1.Router) {
+				rt.Generation = 2
+			},
+			expectErr: "status of generation 1 doesn't reflect the current generation 2",
+		},
+		"missing route status": {
+			mutate: func(rt *v1alpha1.Router) {
+				rt.Status.Routes = rt.Status.Routes[:1]
+			},
+			expectErr: "status contains 1 routes, expected 2",
+		},
+		"unresolved route sink": {
+			mutate: func(rt *v1alpha1.Router) {
+				rt.Status.Routes[1].SinkURI = nil
+			},
+			expectErr: "sink URI of route 1 isn't resolved",
+		},
+		"invalid expression": {
+			mutate: func(rt *v1alpha1.Router) {
+				rt.Spec.Routes[0].Expression = `ce.type ==`
+			},
+			expectErr: "compiling expression of route 0",
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			rt := newTestRouter(t, sinks)
+			tc.mutate(rt)
+
+			e, err := newRouterEntry(rt, nil)
+			if tc.expectErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectErr)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, e.routes, 2)
+			assert.Equal(t, sinks.url("a"), e.routes[0].sinkURI)
+			assert.Equal(t, sinks.url("b"), e.routes[1].sinkURI)
+			assert.Equal(t, sinks.url("default"), e.defaultSinkURI)
+		})
+	}
+}
+
+// newTestRouter returns a Router named "test" with two routes, matching
+// events of type "a" or "both", and "b" or "both" respectively, and a default
+// sink. Its status reflects its current generation.
+func newTestRouter(t *testing.T, sinks *testSinks) *v1alpha1.Router {
+	rt := &v1alpha1.Router{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "test",
+			Generation: 1,
+		},
+		Spec: v1alpha1.RouterSpec{
+			Routes: []v1alpha1.Route{{
+				Expression: `ce.type == "a" || ce.type == "both"`,
+				Sink:       &duckv1.Destination{URI: sinks.uri(t, "a")},
+			}, {
+				Expression: `ce.type == "b" || ce.type == "both"`,
+				Sink:       &duckv1.Destination{URI: sinks.uri(t, "b")},
+			}},
+			DefaultSink: &duckv1.Destination{URI: sinks.uri(t, "default")},
+		},
+	}
+
+	rt.Status.ObservedGeneration = rt.Generation
+	rt.Status.SinkURI = sinks.uri(t, "default")
+	rt.Status.Routes = []v1alpha1.RouteStatus{
+		{SinkURI: sinks.uri(t, "a")},
+		{SinkURI: sinks.uri(t, "b")},
+	}
+
+	return rt
+}
+
+// newTestHandler returns a Handler which serves no Router.
+func newTestHandler(t *testing.T) *Handler {
+	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	require.NoError(t, err)
+
+	return &Handler{
+		sender:  sender,
+		logger:  zap.NewNop().Sugar(),
+		routers: newRoutingTableStore(),
+	}
+}
+
+// postEvent sends a structured CloudEvent of the given type to the given
+// path of the Handler, and returns the status code of the response.
+func postEvent(h *Handler, path, typ string) int {
+	event := `{"specversion":"1.0","id":"0","type":"` + typ + `","source":"test"}`
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(event))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+// testSinks is a single server which acts as multiple named sinks, each
+// served at the path "/<name>", and records which sinks received events.
+type testSinks struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	names   []string
+	failing string
+}
+
+// newTestSinks returns testSinks which accept all events, unless told to
+// fail.
+func newTestSinks(t *testing.T) *testSinks {
+	s := &testSinks{}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+
+		s.mu.Lock()
+		s.names = append(s.names, name)
+		failing := s.failing
+		s.mu.Unlock()
+
+		if name == failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// url returns the URL of the sink with the given name.
+func (s *testSinks) url(name string) string {
+	return s.URL + "/" + name
+}
+
+// uri returns the URL of the sink with the given name as an apis.URL.
+func (s *testSinks) uri(t *testing.T, name string) *apis.URL {
+	u, err := apis.ParseURL(s.url(name))
+	require.NoError(t, err)
+	return u
+}
+
+// received returns the names of the sinks which received events.
+func (s *testSinks) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+// reset forgets the events received by the sinks, and makes all sinks
+// accept events.
+func (s *testSinks) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = nil
+	s.failing = ""
+}
+
+// fail makes the sink with the given name reply with a server error.
+func (s *testSinks) fail(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = name
+}
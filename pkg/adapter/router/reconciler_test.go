@@ -0,0 +1,181 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"knative.dev/pkg/controller"
+
+	adaptercontroller "github.com/triggermesh/routing/pkg/adapter/common/controller"
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestReconcilerReorderedRoutes(t *testing.T) {
+	sinks := newTestSinks(t)
+
+	rt := newTestRouter(t, sinks)
+
+	h := newTestHandler(t)
+	r := &Reconciler{adapter: h}
+	ctx := context.Background()
+
+	require.NoError(t, r.ObserveKind(ctx, rt))
+	assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "a"))
+	assert.Equal(t, []string{"a"}, sinks.received())
+
+	// the routes are swapped, but the controller didn't resolve the sinks
+	// of the new generation yet
+	rt = rt.DeepCopy()
+	rt.Generation++
+	rt.Spec.Routes[0], rt.Spec.Routes[1] = rt.Spec.Routes[1], rt.Spec.Routes[0]
+
+	sinks.reset()
+	err := r.ObserveKind(ctx, rt)
+	assert.True(t, controller.IsPermanentError(err), "Expected a permanent error, got %v", err)
+	assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "a"))
+	assert.Equal(t, []string{"a"}, sinks.received(), "Events are routed according to the previous generation")
+
+	// the controller reflects the new generation in the status
+	rt.Status.ObservedGeneration = rt.Generation
+	rt.Status.Routes[0], rt.Status.Routes[1] = rt.Status.Routes[1], rt.Status.Routes[0]
+
+	sinks.reset()
+	require.NoError(t, r.ObserveKind(ctx, rt))
+	assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "a"))
+	assert.Equal(t, http.StatusAccepted, postEvent(h, "/test", "both"))
+	assert.Equal(t, []string{"a", "b"}, sinks.received(), "The first matching route is now the one of sink b")
+}
+
+func TestReconcilerEviction(t *testing.T) {
+	sinks := newTestSinks(t)
+
+	rt := newTestRouter(t, sinks)
+
+	h := newTestHandler(t)
+	r := &Reconciler{adapter: h}
+	ctx := context.Background()
+
+	testCases := map[string]func(*testing.T){
+		"deleted object": func(t *testing.T) {
+			require.NoError(t, r.ObserveDeletion(ctx, keyOf(rt)))
+		},
+		"unresolved sinks": func(t *testing.T) {
+			rt := rt.DeepCopy()
+			rt.Status.Routes = nil
+			assert.Error(t, r.ObserveKind(ctx, rt))
+		},
+		"invalid expression": func(t *testing.T) {
+			rt := rt.DeepCopy()
+			rt.Generation++
+			rt.Status.ObservedGeneration = rt.Generation
+			rt.Spec.Routes[0].Expression = `ce.type ==`
+			assert.Error(t, r.ObserveKind(ctx, rt))
+		},
+	}
+
+	for name, evict := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, r.ObserveKind(ctx, rt))
+
+			evict(t)
+
+			sinks.reset()
+			assert.Equal(t, http.StatusBadRequest, postEvent(h, "/test", "a"))
+			assert.Empty(t, sinks.received(), "Events are no longer forwarded")
+		})
+	}
+}
+
+func TestReconcilerScope(t *testing.T) {
+	sinks := newTestSinks(t)
+
+	// newRouter returns a Router with the given name, which opted into a
+	// dedicated adapter if dedicated is true.
+	newRouter := func(name string, dedicated bool) *v1alpha1.Router {
+		rt := newTestRouter(t, sinks)
+		rt.Name = name
+		if dedicated {
+			rt.Annotations = map[string]string{flow.AdapterIsolationAnnotation: flow.AdapterIsolationDedicated}
+		}
+		return rt
+	}
+
+	testCases := map[string]struct {
+		scope        adaptercontroller.Scope
+		router       *v1alpha1.Router
+		expectServed bool
+	}{
+		"dedicated adapter, own router": {
+			scope:        adaptercontroller.NewScope("test"),
+			router:       newRouter("test", true),
+			expectServed: true,
+		},
+		"dedicated adapter, other dedicated router": {
+			scope:  adaptercontroller.NewScope("test"),
+			router: newRouter("other", true),
+		},
+		"dedicated adapter, shared router": {
+			scope:  adaptercontroller.NewScope("test"),
+			router: newRouter("other", false),
+		},
+		"shared adapter, shared router": {
+			scope:        adaptercontroller.NewScope(""),
+			router:       newRouter("test", false),
+			expectServed: true,
+		},
+		"shared adapter, dedicated router": {
+			scope:  adaptercontroller.NewScope(""),
+			router: newRouter("test", true),
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			var patched []string
+
+			h := newTestHandler(t)
+			r := &Reconciler{
+				adapter: h,
+				scope:   tc.scope,
+				patch: func(_ context.Context, _, name string, _ []byte) error {
+					patched = append(patched, name)
+					return nil
+				},
+			}
+
+			require.NoError(t, r.ReconcileKind(context.Background(), tc.router))
+
+			_, registered := h.routers.get(tc.router.Name)
+			assert.Equal(t, tc.expectServed, registered)
+
+			if tc.expectServed {
+				assert.Equal(t, []string{tc.router.Name}, patched, "The adapter acknowledges its own Routers")
+			} else {
+				assert.Empty(t, patched, "The adapter doesn't acknowledge Routers served by other adapters")
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// routeEntry pairs the compiled expression of a route with the resolved URI
+// of its sink.
+type routeEntry struct {
+	condition cel.ConditionalFilter
+	sinkURI   string
+}
+
+// routerEntry is the resolved state of a Router, i.e. everything the adapter
+// needs to know about a Router to handle the events it receives. Entries are
+// immutable once they are part of a routingTable.
+type routerEntry struct {
+	namespace  string
+	name       string
+	uid        types.UID
+	generation int64
+
+	mode v1alpha1.RoutingMode
+	// routes are in the order of their declaration.
+	routes []routeEntry
+	// defaultSinkURI is empty when the Router has no default sink.
+	defaultSinkURI string
+}
+
+// newRouterEntry resolves the state of the given Router. The status of the
+// Router must reflect its current generation, so that the sink URI of each
+// route matches the route declared at the same position. The compiled
+// expressions of prev are reused if they match the Router's generation.
+func newRouterEntry(rt *v1alpha1.Router, prev *routerEntry) (*routerEntry, error) {
+	if rt.Status.ObservedGeneration != rt.Generation {
+		return nil, fmt.Errorf("status of generation %d doesn't reflect the current generation %d",
+			rt.Status.ObservedGeneration, rt.Generation)
+	}
+	if len(rt.Status.Routes) != len(rt.Spec.Routes) {
+		return nil, fmt.Errorf("status contains %d routes, expected %d",
+			len(rt.Status.Routes), len(rt.Spec.Routes))
+	}
+
+	e := &routerEntry{
+		namespace:  rt.Namespace,
+		name:       rt.Name,
+		uid:        rt.UID,
+		generation: rt.Generation,
+
+		mode:   rt.Spec.Mode,
+		routes: make([]routeEntry, len(rt.Spec.Routes)),
+	}
+
+	reuse := prev != nil && prev.uid == rt.UID && prev.generation == rt.Generation
+
+	for i, r := range rt.Spec.Routes {
+		sinkURI := rt.Status.Routes[i].SinkURI
+		if sinkURI == nil {
+			return nil, fmt.Errorf("sink URI of route %d isn't resolved", i)
+		}
+		e.routes[i].sinkURI = sinkURI.String()
+
+		if reuse {
+			e.routes[i].condition = prev.routes[i].condition
+			continue
+		}
+
+		cond, err := cel.CompileExpression(r.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("compiling expression of route %d: %w", i, err)
+		}
+		e.routes[i].condition = cond
+	}
+
+	if u := rt.Status.SinkURI; u != nil {
+		e.defaultSinkURI = u.String()
+	}
+
+	return e, nil
+}
+
+// routingTable maps the names of Routers to their resolved state.
+type routingTable map[string]*routerEntry
+
+// routingTableStore holds a routingTable which can be read without locking.
+// Writers replace the whole table with an updated copy.
+type routingTableStore struct {
+	// serializes writers
+	mu sync.Mutex
+	// always holds a routingTable
+	table atomic.Value
+}
+
+func newRoutingTableStore() *routingTableStore {
+	s := &routingTableStore{}
+	s.table.Store(make(routingTable))
+	return s
+}
+
+// get returns the resolved state of the Router with the given name.
+func (s *routingTableStore) get(name string) (*routerEntry, bool) {
+	e, exists := s.table.Load().(routingTable)[name]
+	return e, exists
+}
+
+// set inserts or replaces the resolved state of a Router.
+func (s *routingTableStore) set(e *routerEntry) {
+	s.update(func(t routingTable) {
+		t[e.name] = e
+	})
+}
+
+// delete removes the resolved state of the Router with the given name.
+func (s *routingTableStore) delete(name string) {
+	s.update(func(t routingTable) {
+		delete(t, name)
+	})
+}
+
+// update applies the given function to a copy of the current table, and
+// atomically swaps the current table for that copy.
+func (s *routingTableStore) update(fn func(routingTable)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	curr := s.table.Load().(routingTable)
+
+	next := make(routingTable, len(curr)+1)
+	for k, v := range curr {
+		next[k] = v
+	}
+	fn(next)
+
+	s.table.Store(next)
+}
@@ -21,24 +21,30 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/event"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
-	"github.com/tidwall/gjson"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	pkgadapter "knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/eventing/pkg/kncloudevents"
 	"knative.dev/eventing/pkg/utils"
-	"knative.dev/pkg/injection"
 	"knative.dev/pkg/logging"
 
+	"github.com/triggermesh/routing/pkg/adapter/common/delivery"
 	"github.com/triggermesh/routing/pkg/adapter/common/env"
+	"github.com/triggermesh/routing/pkg/adapter/common/metrics"
+	"github.com/triggermesh/routing/pkg/adapter/common/tracing"
 	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
-	informerv1alpha1 "github.com/triggermesh/routing/pkg/client/generated/injection/informers/flow/v1alpha1/splitter"
-	routinglisters "github.com/triggermesh/routing/pkg/client/generated/listers/flow/v1alpha1"
 )
 
 const serverPort int = 8080
@@ -50,8 +56,10 @@ type Handler struct {
 	// sender sends requests to downstream services
 	sender *kncloudevents.HTTPMessageSender
 
-	splitterLister routinglisters.SplitterNamespaceLister
-	logger         *zap.SugaredLogger
+	logger *zap.SugaredLogger
+
+	// splitters is the routing table of the Splitters served by the adapter
+	splitters *routingTableStore
 }
 
 // NewEnvConfig satisfies env.ConfigConstructor.
@@ -72,25 +80,36 @@ func NewAdapter(component string) pkgadapter.AdapterConstructor {
 			logger.Panicf("failed to create message sender: %v", err)
 		}
 
-		informer := informerv1alpha1.Get(ctx)
-		ns := injection.GetNamespaceScope(ctx)
+		if err := metrics.RegisterViews(); err != nil {
+			logger.Panicf("failed to register metrics views: %v", err)
+		}
 
 		return &Handler{
-			receiver:       kncloudevents.NewHTTPMessageReceiver(serverPort),
-			sender:         sender,
-			splitterLister: informer.Lister().Splitters(ns),
-			logger:         logger,
+			receiver: kncloudevents.NewHTTPMessageReceiver(serverPort),
+			sender:   sender,
+			logger:   logger,
+
+			splitters: newRoutingTableStore(),
 		}
 	}
 }
 
 // RegisterHandlerFor implements MTAdapter.
 func (h *Handler) RegisterHandlerFor(ctx context.Context, s *v1alpha1.Splitter) error {
+	e, err := newSplitterEntry(s)
+	if err != nil {
+		// stop serving a state which no longer matches the Splitter
+		h.splitters.delete(s.Name)
+		return err
+	}
+	h.splitters.set(e)
+
 	return nil
 }
 
 // DeregisterHandlerFor implements MTAdapter.
-func (h *Handler) DeregisterHandlerFor(ctx context.Context, s *v1alpha1.Splitter) error {
+func (h *Handler) DeregisterHandlerFor(ctx context.Context, key types.NamespacedName) error {
+	h.splitters.delete(key.Name)
 	return nil
 }
 
@@ -132,46 +151,168 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 
 	h.logger.Debug("Received message", zap.Any("splitter", splitter))
 
-	s, err := h.splitterLister.Get(splitter)
+	e, exists := h.splitters.get(splitter)
+	if !exists {
+		h.logger.Info("Splitter not found or not ready", zap.Any("splitter", splitter))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s := e.splitter
+
+	reporter := metrics.NewReporter(s.Namespace, s.Name)
+	reporter.ReportEventReceived(ctx)
+
+	ctx, span := tracing.StartReceiveSpan(ctx, "splitter", s.Namespace, s.Name, event)
+	defer span.End()
+
+	events, err := split(ctx, s, event)
 	if err != nil {
-		h.logger.Info("Unable to get the Splitter", zap.Error(err), zap.Any("splitter", splitter))
+		h.logger.Info("Unable to split the event", zap.Error(err), zap.Any("splitter", splitter))
 		writer.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	for i, e := range h.split(s.Spec.Path, event) {
-		e.SetID(fmt.Sprintf("%s-%d", event.ID(), i))
-		e.SetType(s.Spec.CEContext.Type)
-		e.SetSource(s.Spec.CEContext.Source)
-		for key, value := range s.Spec.CEContext.Extensions {
-			e.SetExtension(key, value)
+	reporter.ReportSplitSize(ctx, len(events))
+
+	var failed int
+	if e.ordered {
+		failed = h.dispatchOrdered(ctx, request.Header, e.sinkURI, events, e.delivery, reporter)
+	} else {
+		failed = h.dispatchParallel(ctx, request.Header, e.sinkURI, events, e.parallelism, e.delivery, reporter)
+	}
+
+	if failed > 0 {
+		h.logger.Error("Failed to deliver split events", zap.Any("splitter", splitter),
+			zap.Int("failed", failed), zap.Int("total", len(events)))
+		writer.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+}
+
+// split splits the given event within its own span.
+func split(ctx context.Context, s *v1alpha1.Splitter, e *event.Event) ([]*event.Event, error) {
+	_, span := tracing.StartSpan(ctx, "splitter."+tracing.SpanSplit)
+	defer span.End()
+
+	events, err := SplitEvent(s, e)
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	span.AddAttributes(trace.Int64Attribute("splitter.size", int64(len(events))))
+	return events, nil
+}
+
+// dispatchOrdered delivers the given events one at a time, in order, and
+// stops at the first failure. It returns the number of undelivered events.
+func (h *Handler) dispatchOrdered(ctx context.Context, headers http.Header, target string,
+	events []*event.Event, dc delivery.Config, reporter *metrics.Reporter) int {
+
+	for i, e := range events {
+		if err := h.deliver(ctx, headers, target, e, dc, reporter); err != nil {
+			h.logger.Error("failed to send the event", zap.Error(err), zap.String("id", e.ID()))
+			return len(events) - i
 		}
-		// we may want to keep responses and send them back to the source
-		_, err := h.sendEvent(ctx, request.Header, s.Status.SinkURI.String(), e)
-		if err != nil {
-			h.logger.Error("failed to send the event", zap.Error(err))
+	}
+	return 0
+}
+
+// dispatchParallel delivers the given events using at most parallelism
+// concurrent requests. No event is dispatched anymore once ctx is done. It
+// returns the number of undelivered events.
+func (h *Handler) dispatchParallel(ctx context.Context, headers http.Header, target string,
+	events []*event.Event, parallelism int, dc delivery.Config, reporter *metrics.Reporter) int {
+
+	var failed int32
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, parallelism)
+
+	for i, e := range events {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
 		}
+		if err := ctx.Err(); err != nil {
+			h.logger.Error("Stopped dispatching split events", zap.Error(err))
+			atomic.AddInt32(&failed, int32(len(events)-i))
+			break
+		}
+
+		wg.Add(1)
+
+		go func(e *event.Event) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := h.deliver(ctx, headers, target, e, dc, reporter); err != nil {
+				h.logger.Error("failed to send the event", zap.Error(err), zap.String("id", e.ID()))
+				atomic.AddInt32(&failed, 1)
+			}
+		}(e)
 	}
 
-	writer.WriteHeader(http.StatusOK)
+	wg.Wait()
+
+	return int(failed)
+}
+
+// deliver sends the given event to the target, retrying and dead-lettering
+// it according to the given delivery options, and returns an error if
+// neither the target nor the dead letter sink acknowledge it.
+func (h *Handler) deliver(ctx context.Context, headers http.Header, target string, e *event.Event,
+	dc delivery.Config, reporter *metrics.Reporter) error {
+
+	// we may want to keep responses and send them back to the source
+	err := h.sendAndCheck(ctx, headers, target, e, dc.Retry, reporter)
+	if err == nil || dc.DeadLetterSink == nil {
+		return err
+	}
+
+	h.logger.Info("Delivery failed, sending event to the dead letter sink", zap.Error(err), zap.String("id", e.ID()))
+
+	if err := h.sendAndCheck(ctx, headers, dc.DeadLetterSink.String(), e, nil, reporter); err != nil {
+		return fmt.Errorf("sending to the dead letter sink: %w", err)
+	}
+	return nil
 }
 
-func (h *Handler) split(path string, e *event.Event) []*event.Event {
-	var result []*event.Event
+// sendAndCheck sends the given event to the target and returns an error if
+// the target doesn't acknowledge it.
+func (h *Handler) sendAndCheck(ctx context.Context, headers http.Header, target string, e *event.Event,
+	retry *kncloudevents.RetryConfig, reporter *metrics.Reporter) error {
 
-	val := gjson.Get(string(e.Data()), path)
-	if !val.IsArray() {
-		return result
+	resp, err := h.sendEvent(ctx, headers, target, e, retry, reporter)
+	if resp != nil {
+		resp.Body.Close()
 	}
-	for _, v := range val.Array() {
-		newCE := cloudevents.NewEvent()
-		newCE.SetData(cloudevents.ApplicationJSON, v.Raw)
-		result = append(result, &newCE)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("target responded with status code %d", resp.StatusCode)
 	}
-	return result
+	return nil
 }
 
-func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target string, event *cloudevents.Event) (*http.Response, error) {
+func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target string, event *cloudevents.Event,
+	retry *kncloudevents.RetryConfig, reporter *metrics.Reporter) (*http.Response, error) {
+
+	ctx, span := tracing.StartSpan(ctx, "splitter."+tracing.SpanDispatch,
+		trace.StringAttribute(ochttp.URLAttribute, target))
+	defer span.End()
+	span.AddAttributes(tracing.EventAttributes(event)...)
+
+	// child events refer to the dispatch span, which descends from the
+	// span of their parent event
+	tracing.SetEventSpanContext(ctx, event)
+
 	// Send the event to the subscriber
 	req, err := h.sender.NewCloudEventRequestWithTarget(ctx, target)
 	if err != nil {
@@ -189,9 +330,14 @@ func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target str
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
 
-	resp, err := h.sender.Send(req)
+	start := time.Now()
+	resp, err := h.sender.SendWithRetries(req, retry)
+	reporter.ReportDispatch(ctx, resp, time.Since(start))
 	if err != nil {
 		err = fmt.Errorf("failed to dispatch message: %w", err)
+		tracing.SetError(span, err)
+	} else {
+		tracing.SetResponseStatus(span, resp)
 	}
 
 	return resp, err
@@ -0,0 +1,228 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package splitter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/delivery"
+	"github.com/triggermesh/routing/pkg/adapter/common/metrics"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestDispatchParallel(t *testing.T) {
+	const parallelism = 3
+
+	testCases := map[string]struct {
+		failIDs      map[string]bool
+		cancel       bool
+		expectFailed int
+		expectSent   int
+	}{
+		"all delivered": {
+			expectSent: 10,
+		},
+		"some failures": {
+			failIDs:      map[string]bool{"2": true, "7": true},
+			expectFailed: 2,
+			expectSent:   10,
+		},
+		"cancelled context": {
+			cancel:       true,
+			expectFailed: 10,
+			expectSent:   0,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			sink := newRecordingSink(t, tc.failIDs, 20*time.Millisecond)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+
+			h := newTestHandler(t)
+			failed := h.dispatchParallel(ctx, http.Header{}, sink.URL, newTestEvents(10),
+				parallelism, delivery.Config{}, metrics.NewReporter("ns", "test"))
+
+			assert.Equal(t, tc.expectFailed, failed)
+			assert.Len(t, sink.received(), tc.expectSent)
+			assert.LessOrEqual(t, sink.maxInFlight(), parallelism, "Concurrent requests exceed the parallelism")
+		})
+	}
+}
+
+func TestDispatchOrdered(t *testing.T) {
+	testCases := map[string]struct {
+		failIDs        map[string]bool
+		expectFailed   int
+		expectReceived []string
+	}{
+		"all delivered": {
+			expectReceived: []string{"0", "1", "2", "3", "4"},
+		},
+		"stops at first failure": {
+			failIDs:        map[string]bool{"2": true},
+			expectFailed:   3,
+			expectReceived: []string{"0", "1", "2"},
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			sink := newRecordingSink(t, tc.failIDs, 0)
+
+			h := newTestHandler(t)
+			failed := h.dispatchOrdered(context.Background(), http.Header{}, sink.URL, newTestEvents(5),
+				delivery.Config{}, metrics.NewReporter("ns", "test"))
+
+			assert.Equal(t, tc.expectFailed, failed)
+			assert.Equal(t, tc.expectReceived, sink.received())
+			assert.Equal(t, 1, sink.maxInFlight())
+		})
+	}
+}
+
+func TestNewSplitterEntry(t *testing.T) {
+	testCases := map[string]struct {
+		mutate    func(*v1alpha1.Splitter)
+		expectErr string
+	}{
+		"up-to-date status": {
+			mutate: func(*v1alpha1.Splitter) {},
+		},
+		"outdated status": {
+			mutate: func(s *v1alpha1.Splitter) {
+				s.Generation = 2
+			},
+			expectErr: "status of generation 1 doesn't reflect the current generation 2",
+		},
+		"unresolved sink": {
+			mutate: func(s *v1alpha1.Splitter) {
+				s.Status.SinkURI = nil
+			},
+			expectErr: "sink URI isn't resolved",
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			s := newTestSplitter(t, "http://sink.ns.svc.cluster.local")
+			tc.mutate(s)
+
+			e, err := newSplitterEntry(s)
+			if tc.expectErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectErr)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "http://sink.ns.svc.cluster.local", e.sinkURI)
+		})
+	}
+}
+
+// newTestEvents returns the given number of events, with their index as ID.
+func newTestEvents(n int) []*event.Event {
+	events := make([]*event.Event, n)
+	for i := range events {
+		e := cloudevents.NewEvent()
+		e.SetID(strconv.Itoa(i))
+		e.SetType("io.triggermesh.item")
+		e.SetSource("splitter")
+		events[i] = &e
+	}
+	return events
+}
+
+// recordingSink is a sink which records the IDs of the events it receives and
+// the maximum number of requests it served concurrently.
+type recordingSink struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	ids      []string
+	inFlight int
+	peak     int
+}
+
+// newRecordingSink returns a recordingSink which rejects events with the
+// given IDs, and holds each request for the given delay before responding.
+func newRecordingSink(t *testing.T, failIDs map[string]bool, delay time.Duration) *recordingSink {
+	s := &recordingSink{}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("ce-id")
+
+		s.mu.Lock()
+		s.ids = append(s.ids, id)
+		s.inFlight++
+		if s.inFlight > s.peak {
+			s.peak = s.inFlight
+		}
+		s.mu.Unlock()
+
+		time.Sleep(delay)
+
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+
+		if failIDs[id] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// received returns the IDs of the events received by the sink, in their order
+// of arrival.
+func (s *recordingSink) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.ids...)
+}
+
+// maxInFlight returns the maximum number of requests served concurrently.
+func (s *recordingSink) maxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peak
+}
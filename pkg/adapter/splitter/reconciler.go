@@ -21,10 +21,12 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/reconciler"
 
+	adaptercontroller "github.com/triggermesh/routing/pkg/adapter/common/controller"
 	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
 	reconcilerv1alpha1 "github.com/triggermesh/routing/pkg/client/generated/injection/reconciler/flow/v1alpha1/splitter"
 )
@@ -32,6 +34,10 @@ import (
 // Reconciler implements controller.Reconciler for the event source type.
 type Reconciler struct {
 	adapter MTAdapter
+	// scope determines which Splitters are served by the adapter
+	scope adaptercontroller.Scope
+	// patch acknowledges the configuration loaded by the adapter
+	patch adaptercontroller.PatchFunc
 }
 
 // Check the interfaces Reconciler should implement.
@@ -39,20 +45,39 @@ var (
 	_ reconcilerv1alpha1.Interface         = (*Reconciler)(nil)
 	_ reconcilerv1alpha1.ReadOnlyInterface = (*Reconciler)(nil)
 	_ reconcilerv1alpha1.ReadOnlyFinalizer = (*Reconciler)(nil)
+	_ reconciler.OnDeletionInterface       = (*Reconciler)(nil)
 )
 
 // ReconcileKind implements reconcilerv1alpha1.Interface.
 func (r *Reconciler) ReconcileKind(ctx context.Context, s *v1alpha1.Splitter) reconciler.Event {
-	return r.reconcile(ctx, s)
+	return r.reconcile(ctx, s, true)
 }
 
 // ObserveKind implements reconcilerv1alpha1.ReadOnlyInterface.
 func (r *Reconciler) ObserveKind(ctx context.Context, s *v1alpha1.Splitter) reconciler.Event {
-	return r.reconcile(ctx, s)
+	return r.reconcile(ctx, s, false)
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, s *v1alpha1.Splitter) error {
+// reconcile registers the given Splitter with the adapter. Only the leader
+// acknowledges the outcome of the registration, so that acknowledgement
+// reflects the state of the leader. Other replicas register the same
+// generation from their own informer, and may lag slightly behind.
+func (r *Reconciler) reconcile(ctx context.Context, s *v1alpha1.Splitter, acknowledge bool) error {
+	if !r.scope.Serves(s) {
+		// The Splitter is served by another adapter, which also
+		// acknowledges its registration.
+		if err := r.adapter.DeregisterHandlerFor(ctx, keyOf(s)); err != nil {
+			return fmt.Errorf("deregistering HTTP handler: %w", err)
+		}
+		return nil
+	}
+
 	if s.Status.SinkURI == nil {
+		// Stop serving a state which no longer matches the Splitter.
+		if err := r.adapter.DeregisterHandlerFor(ctx, keyOf(s)); err != nil {
+			return fmt.Errorf("deregistering HTTP handler: %w", err)
+		}
+
 		// Mark that error as permanent so we don't retry until the
 		// source's status has been updated, which automatically
 		// triggers a new reconciliation.
@@ -60,7 +85,23 @@ func (r *Reconciler) reconcile(ctx context.Context, s *v1alpha1.Splitter) error
 			"Event sink URL wasn't resolved yet. Skipping adapter configuration"))
 	}
 
-	if err := r.adapter.RegisterHandlerFor(ctx, s); err != nil {
+	if s.Status.ObservedGeneration != s.Generation {
+		// The sink URI is only paired with the spec of the generation
+		// it was resolved for. The previous generation remains served
+		// until the status catches up.
+		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeNormal, ReasonSourceNotReady,
+			"Event sink URL wasn't resolved for generation %d yet. Skipping adapter configuration", s.Generation))
+	}
+
+	err := r.adapter.RegisterHandlerFor(ctx, s)
+
+	if acknowledge {
+		if ackErr := adaptercontroller.AcknowledgeSync(ctx, s, err, r.patch); ackErr != nil {
+			return fmt.Errorf("acknowledging adapter sync: %w", ackErr)
+		}
+	}
+
+	if err != nil {
 		return fmt.Errorf("registering HTTP handler: %w", err)
 	}
 
@@ -73,10 +114,26 @@ func (r *Reconciler) ObserveFinalizeKind(ctx context.Context, s *v1alpha1.Splitt
 }
 
 func (r *Reconciler) finalize(ctx context.Context, s *v1alpha1.Splitter) error {
-	if err := r.adapter.DeregisterHandlerFor(ctx, s); err != nil {
+	if err := r.adapter.DeregisterHandlerFor(ctx, keyOf(s)); err != nil {
 		return fmt.Errorf("deregistering HTTP handler: %w", err)
 	}
 
 	return reconciler.NewEvent(corev1.EventTypeNormal, ReasonHandlerDeregistered,
 		"HTTP handler deregistered")
 }
+
+// ObserveDeletion implements reconciler.OnDeletionInterface.
+//
+// Splitters don't carry a finalizer, so their deletion is only observed as the
+// disappearance of the object from the informer's cache.
+func (r *Reconciler) ObserveDeletion(ctx context.Context, key types.NamespacedName) error {
+	if err := r.adapter.DeregisterHandlerFor(ctx, key); err != nil {
+		return fmt.Errorf("deregistering HTTP handler: %w", err)
+	}
+	return nil
+}
+
+// keyOf returns the key of the given Splitter.
+func keyOf(s *v1alpha1.Splitter) types.NamespacedName {
+	return types.NamespacedName{Namespace: s.Namespace, Name: s.Name}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package splitter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestReconcilerEviction(t *testing.T) {
+	var received int32
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	s := newTestSplitter(t, sink.URL)
+
+	h := newTestHandler(t)
+	r := &Reconciler{adapter: h}
+	ctx := context.Background()
+
+	require.NoError(t, r.ObserveKind(ctx, s))
+	assert.Equal(t, http.StatusOK, postEvent(h, "/test", `{"items":[1,2]}`))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&received))
+
+	t.Run("deleted object", func(t *testing.T) {
+		require.NoError(t, r.ObserveKind(ctx, s))
+		require.NoError(t, r.ObserveDeletion(ctx, keyOf(s)))
+
+		assert.Equal(t, http.StatusBadRequest, postEvent(h, "/test", `{"items":[1,2]}`))
+		assert.EqualValues(t, 2, atomic.LoadInt32(&received), "Events are no longer forwarded")
+	})
+
+	t.Run("unresolved sink", func(t *testing.T) {
+		require.NoError(t, r.ObserveKind(ctx, s))
+
+		s := s.DeepCopy()
+		s.Status.SinkURI = nil
+		assert.Error(t, r.ObserveKind(ctx, s))
+
+		assert.Equal(t, http.StatusBadRequest, postEvent(h, "/test", `{"items":[1,2]}`))
+		assert.EqualValues(t, 2, atomic.LoadInt32(&received), "Events are no longer forwarded")
+	})
+}
+
+func TestReconcilerOutdatedStatus(t *testing.T) {
+	var receivedOld, receivedNew int32
+	oldSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&receivedOld, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer oldSink.Close()
+	newSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&receivedNew, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer newSink.Close()
+
+	h := newTestHandler(t)
+	r := &Reconciler{adapter: h}
+	ctx := context.Background()
+
+	s := newTestSplitter(t, oldSink.URL)
+	require.NoError(t, r.ObserveKind(ctx, s))
+
+	// the spec changed, but the status still holds the sink resolved for
+	// the previous generation
+	s = s.DeepCopy()
+	s.Generation = 2
+	s.Spec.Path = "other"
+	assert.Error(t, r.ObserveKind(ctx, s))
+
+	assert.Equal(t, http.StatusOK, postEvent(h, "/test", `{"items":[1,2],"other":[3]}`))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&receivedOld), "The previous generation is still served")
+	assert.EqualValues(t, 0, atomic.LoadInt32(&receivedNew))
+
+	s = newTestSplitter(t, newSink.URL)
+	s.Generation = 2
+	s.Status.ObservedGeneration = 2
+	s.Spec.Path = "other"
+	require.NoError(t, r.ObserveKind(ctx, s))
+
+	assert.Equal(t, http.StatusOK, postEvent(h, "/test", `{"items":[1,2],"other":[3]}`))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&receivedOld))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&receivedNew), "The current generation is served")
+}
+
+// newTestSplitter returns a Splitter named "test" which splits the "items"
+// array of events and sends the resulting events to the given sink. Its
+// status reflects its current generation.
+func newTestSplitter(t *testing.T, sink string) *v1alpha1.Splitter {
+	s := &v1alpha1.Splitter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "test",
+			Generation: 1,
+		},
+		Spec: v1alpha1.SplitterSpec{
+			Path: "items",
+			CEContext: v1alpha1.CloudEventContext{
+				Type:   "io.triggermesh.item",
+				Source: "splitter",
+			},
+		},
+	}
+
+	sinkURI, err := apis.ParseURL(sink)
+	require.NoError(t, err)
+	s.Status.ObservedGeneration = s.Generation
+	s.Status.SinkURI = sinkURI
+
+	return s
+}
+
+// newTestHandler returns a Handler which serves no Splitter.
+func newTestHandler(t *testing.T) *Handler {
+	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	require.NoError(t, err)
+
+	return &Handler{
+		sender:    sender,
+		logger:    zap.NewNop().Sugar(),
+		splitters: newRoutingTableStore(),
+	}
+}
+
+// postEvent sends a structured CloudEvent with the given JSON payload to the
+// given path of the Handler, and returns the status code of the response.
+func postEvent(h *Handler, path, data string) int {
+	event := `{"specversion":"1.0","id":"0","type":"test","source":"test",` +
+		`"datacontenttype":"application/json","data":` + data + `}`
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(event))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec.Code
+}
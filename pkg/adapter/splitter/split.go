@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package splitter
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventsplitter"
+)
+
+// SplitEvent splits the given event into the child events described by the
+// given Splitter.
+func SplitEvent(s *v1alpha1.Splitter, e *event.Event) ([]*event.Event, error) {
+	items, err := eventsplitter.Split(e.Data(), s.Spec.Path, string(s.Spec.PathSyntax))
+	if err != nil {
+		return nil, err
+	}
+
+	if env := s.Spec.Envelope; env != nil {
+		envelope := eventsplitter.Envelope{
+			Wrap:        env.Wrap,
+			ParentPaths: env.ParentPaths,
+		}
+		if items, err = envelope.Apply(e.Data(), items); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*event.Event, 0, len(items))
+	for i, item := range items {
+		newCE := cloudevents.NewEvent()
+		if err := newCE.SetData(cloudevents.ApplicationJSON, []byte(item)); err != nil {
+			return nil, fmt.Errorf("setting data of split event: %w", err)
+		}
+
+		newCE.SetID(fmt.Sprintf("%s-%d", e.ID(), i))
+		newCE.SetType(s.Spec.CEContext.Type)
+		newCE.SetSource(s.Spec.CEContext.Source)
+		for key, value := range s.Spec.CEContext.Extensions {
+			newCE.SetExtension(key, value)
+		}
+		setCorrelation(&newCE, e.ID(), i, len(items))
+
+		result = append(result, &newCE)
+	}
+	return result, nil
+}
+
+// setCorrelation sets the extensions which correlate a child event with the
+// parent event it was split from.
+func setCorrelation(e *event.Event, parentID string, index, total int) {
+	e.SetExtension(v1alpha1.SplitterExtensionParentID, parentID)
+	e.SetExtension(v1alpha1.SplitterExtensionIndex, int32(index))
+	e.SetExtension(v1alpha1.SplitterExtensionTotal, int32(total))
+	e.SetExtension(v1alpha1.SplitterExtensionLast, index == total-1)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package splitter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestSplitEvent(t *testing.T) {
+	testCases := map[string]struct {
+		data       string
+		extensions map[string]string
+		expectIDs  []string
+	}{
+		"single item": {
+			data:      `{"items":[{"id":"a"}]}`,
+			expectIDs: []string{"parent-0"},
+		},
+		"several items": {
+			data:      `{"items":[{"id":"a"},{"id":"b"},{"id":"c"}]}`,
+			expectIDs: []string{"parent-0", "parent-1", "parent-2"},
+		},
+		"custom extensions": {
+			data:       `{"items":[{"id":"a"},{"id":"b"}]}`,
+			extensions: map[string]string{"team": "billing"},
+			expectIDs:  []string{"parent-0", "parent-1"},
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			s := newTestSplitter(t, "http://sink.ns.svc.cluster.local")
+			s.Spec.CEContext.Extensions = tc.extensions
+
+			parent := cloudevents.NewEvent()
+			parent.SetID("parent")
+			parent.SetType("test")
+			parent.SetSource("test")
+			require.NoError(t, parent.SetData(cloudevents.ApplicationJSON, []byte(tc.data)))
+
+			events, err := SplitEvent(s, &parent)
+			require.NoError(t, err)
+			require.Len(t, events, len(tc.expectIDs))
+
+			total := len(events)
+			for i, e := range events {
+				assert.Equal(t, tc.expectIDs[i], e.ID())
+				assert.Equal(t, s.Spec.CEContext.Type, e.Type())
+				assert.Equal(t, s.Spec.CEContext.Source, e.Source())
+
+				ext := e.Extensions()
+				assert.Equal(t, "parent", ext[v1alpha1.SplitterExtensionParentID])
+				assert.Equal(t, int32(i), ext[v1alpha1.SplitterExtensionIndex])
+				assert.Equal(t, int32(total), ext[v1alpha1.SplitterExtensionTotal])
+				assert.Equal(t, i == total-1, ext[v1alpha1.SplitterExtensionLast])
+
+				for k, v := range tc.extensions {
+					assert.Equal(t, v, ext[k])
+				}
+			}
+		})
+	}
+}
+
+func TestCorrelationExtensionsDelivered(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]map[string]string)
+
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.Header.Get("ce-id")] = map[string]string{
+			v1alpha1.SplitterExtensionParentID: r.Header.Get("ce-" + v1alpha1.SplitterExtensionParentID),
+			v1alpha1.SplitterExtensionIndex:    r.Header.Get("ce-" + v1alpha1.SplitterExtensionIndex),
+			v1alpha1.SplitterExtensionTotal:    r.Header.Get("ce-" + v1alpha1.SplitterExtensionTotal),
+			v1alpha1.SplitterExtensionLast:     r.Header.Get("ce-" + v1alpha1.SplitterExtensionLast),
+		}
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	h := newTestHandler(t)
+	r := &Reconciler{adapter: h}
+	require.NoError(t, r.ObserveKind(context.Background(), newTestSplitter(t, sink.URL)))
+
+	require.Equal(t, http.StatusOK, postEvent(h, "/test", `{"items":[1,2]}`))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// the ID of the event sent by postEvent is "0"
+	expect := map[string]map[string]string{
+		"0-0": {
+			v1alpha1.SplitterExtensionParentID: "0",
+			v1alpha1.SplitterExtensionIndex:    "0",
+			v1alpha1.SplitterExtensionTotal:    "2",
+			v1alpha1.SplitterExtensionLast:     "false",
+		},
+		"0-1": {
+			v1alpha1.SplitterExtensionParentID: "0",
+			v1alpha1.SplitterExtensionIndex:    "1",
+			v1alpha1.SplitterExtensionTotal:    "2",
+			v1alpha1.SplitterExtensionLast:     "true",
+		},
+	}
+	assert.Equal(t, expect, received)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package splitter
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/delivery"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+// splitterEntry is the resolved state of a Splitter, i.e. everything the
+// adapter needs to know about a Splitter to handle the events it receives.
+// Entries are immutable once they are part of a routingTable.
+type splitterEntry struct {
+	// splitter is a private copy of the Splitter, decoupled from the
+	// informer's cache.
+	splitter *v1alpha1.Splitter
+
+	sinkURI string
+
+	ordered     bool
+	parallelism int
+
+	delivery delivery.Config
+}
+
+// newSplitterEntry resolves the state of the given Splitter. The status of
+// the Splitter must reflect its current generation, so that the sink URI
+// matches the spec it was resolved for.
+func newSplitterEntry(s *v1alpha1.Splitter) (*splitterEntry, error) {
+	if s.Status.ObservedGeneration != s.Generation {
+		return nil, fmt.Errorf("status of generation %d doesn't reflect the current generation %d",
+			s.Status.ObservedGeneration, s.Generation)
+	}
+	if s.Status.SinkURI == nil {
+		return nil, fmt.Errorf("sink URI isn't resolved")
+	}
+
+	s = s.DeepCopy()
+
+	e := &splitterEntry{
+		splitter: s,
+		sinkURI:  s.Status.SinkURI.String(),
+		ordered:  s.Spec.Ordered,
+	}
+
+	// ordered dispatching implies a parallelism of 1
+	e.parallelism = 1
+	if p := s.Spec.Parallelism; p != nil && *p > 1 && !e.ordered {
+		e.parallelism = int(*p)
+	}
+
+	dc, err := delivery.NewConfig(s.Spec.Delivery, s.Status.DeadLetterSinkURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delivery options: %w", err)
+	}
+	e.delivery = dc
+
+	return e, nil
+}
+
+// routingTable maps the names of Splitters to their resolved state.
+type routingTable map[string]*splitterEntry
+
+// routingTableStore holds a routingTable which can be read without locking.
+// Writers replace the whole table with an updated copy.
+type routingTableStore struct {
+	// serializes writers
+	mu sync.Mutex
+	// always holds a routingTable
+	table atomic.Value
+}
+
+func newRoutingTableStore() *routingTableStore {
+	s := &routingTableStore{}
+	s.table.Store(make(routingTable))
+	return s
+}
+
+// get returns the resolved state of the Splitter with the given name.
+func (s *routingTableStore) get(name string) (*splitterEntry, bool) {
+	e, exists := s.table.Load().(routingTable)[name]
+	return e, exists
+}
+
+// set inserts or replaces the resolved state of a Splitter.
+func (s *routingTableStore) set(e *splitterEntry) {
+	s.update(func(t routingTable) {
+		t[e.splitter.Name] = e
+	})
+}
+
+// delete removes the resolved state of the Splitter with the given name.
+func (s *routingTableStore) delete(name string) {
+	s.update(func(t routingTable) {
+		delete(t, name)
+	})
+}
+
+// update applies the given function to a copy of the current table, and
+// atomically swaps the current table for that copy.
+func (s *routingTableStore) update(fn func(routingTable)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	curr := s.table.Load().(routingTable)
+
+	next := make(routingTable, len(curr)+1)
+	for k, v := range curr {
+		next[k] = v
+	}
+	fn(next)
+
+	s.table.Store(next)
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	pkgadapter "knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/utils"
+	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/routing/pkg/adapter/common/env"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+const serverPort int = 8080
+
+// responsePath is the path, relative to the address of a Synchronizer, on
+// which response events are accepted.
+const responsePath = "response"
+
+// Handler turns HTTP requests into events, sends them to a sink, and replies
+// to each request with the response event which carries its correlation key.
+type Handler struct {
+	// receiver receives incoming HTTP requests
+	receiver *kncloudevents.HTTPMessageReceiver
+	// sender sends requests to downstream services
+	sender *kncloudevents.HTTPMessageSender
+
+	logger *zap.SugaredLogger
+
+	// synchronizers is the routing table of the Synchronizers served by
+	// the adapter
+	synchronizers *routingTableStore
+	// sessions holds the requests which wait for a response event
+	sessions *sessionStorage
+}
+
+// NewEnvConfig satisfies env.ConfigConstructor.
+// Returns an accessor for the source's adapter envConfig.
+func NewEnvConfig() env.ConfigAccessor {
+	return &env.Config{}
+}
+
+// NewAdapter creates a new Handler and its associated MessageReceiver. The caller is responsible for
+// Start()ing the returned Handler.
+func NewAdapter(component string) pkgadapter.AdapterConstructor {
+	return func(ctx context.Context, _ pkgadapter.EnvConfigAccessor,
+		ceClient cloudevents.Client) pkgadapter.Adapter {
+		logger := logging.FromContext(ctx)
+
+		sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+		if err != nil {
+			logger.Panicf("failed to create message sender: %v", err)
+		}
+
+		return &Handler{
+			receiver: kncloudevents.NewHTTPMessageReceiver(serverPort),
+			sender:   sender,
+			logger:   logger,
+
+			synchronizers: newRoutingTableStore(),
+			sessions:      newSessionStorage(),
+		}
+	}
+}
+
+// RegisterHandlerFor implements MTAdapter.
+func (h *Handler) RegisterHandlerFor(ctx context.Context, s *v1alpha1.Synchronizer) error {
+	h.synchronizers.set(newSynchronizerEntry(s))
+	return nil
+}
+
+// DeregisterHandlerFor implements MTAdapter.
+func (h *Handler) DeregisterHandlerFor(ctx context.Context, key types.NamespacedName) error {
+	h.synchronizers.delete(key.Name)
+	return nil
+}
+
+// Start begins to receive messages for the handler.
+//
+// HTTP POST requests to the path of a Synchronizer (/<name>) are requests,
+// HTTP POST requests to its callback path (/<name>/response) are responses.
+//
+// This method will block until ctx is done.
+func (h *Handler) Start(ctx context.Context) error {
+	return h.receiver.StartListen(ctx, h)
+}
+
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	synchronizer, isResponse, err := parseRequestURI(request.RequestURI)
+	if err != nil {
+		h.logger.Info("Unable to parse path as synchronizer", zap.Error(err), zap.String("path", request.RequestURI))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s, exists := h.synchronizers.get(synchronizer)
+	if !exists {
+		h.logger.Info("Synchronizer not found or not ready", zap.Any("synchronizer", synchronizer))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := request.Context()
+
+	message := cehttp.NewMessageFromHttpRequest(request)
+	// cannot be err, but makes linter complain about missing err check
+	//nolint
+	defer message.Finish(nil)
+
+	// requests which don't contain a CloudEvent are converted to one
+	if message.ReadEncoding() == binding.EncodingUnknown {
+		if isResponse {
+			h.logger.Info("Response is not a CloudEvent", zap.String("synchronizer", s.name))
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		event, err := newRequestEvent(s, request)
+		if err != nil {
+			h.logger.Warn("failed to read request", zap.Error(err))
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.handleRequest(ctx, writer, request.Header, s, event)
+		return
+	}
+
+	event, err := binding.ToEvent(ctx, message)
+	if err != nil {
+		h.logger.Warn("failed to extract event from request", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	key, hasKey := correlationKey(event, s.correlationAttr)
+
+	if isResponse {
+		if !hasKey {
+			h.logger.Info("Response event carries no correlation key", zap.String("synchronizer", s.name),
+				zap.String("id", event.ID()))
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.handleResponse(writer, s, key, event)
+		return
+	}
+
+	// the correlation key of a request is always generated by the
+	// adapter, so that clients can't answer other pending requests
+	if _, isSet := event.Extensions()[s.correlationAttr]; isSet {
+		h.logger.Info("Request event already carries a correlation key", zap.String("synchronizer", s.name),
+			zap.String("id", event.ID()))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.handleRequest(ctx, writer, request.Header, s, event)
+}
+
+// handleRequest sends the given request event to the sink, then waits for a
+// response event with the same correlation key and writes it to the response.
+func (h *Handler) handleRequest(ctx context.Context, writer http.ResponseWriter, headers http.Header,
+	s *synchronizerEntry, event *cloudevents.Event) {
+
+	key := newCorrelationKey()
+	event.SetExtension(s.correlationAttr, key)
+
+	sk := sessionKey{synchronizer: s.name, correlationKey: key}
+
+	// the session is opened before sending the request, in case the
+	// response event arrives before the sink replied
+	respCh := h.sessions.add(sk)
+	defer h.sessions.delete(sk)
+
+	if err := h.sendAndCheck(ctx, headers, s.sinkURI, event); err != nil {
+		h.logger.Error("failed to send the request event", zap.Error(err), zap.String("target", s.sinkURI))
+		writer.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	h.logger.Debug("Waiting for a response event", zap.String("synchronizer", s.name), zap.String("key", key))
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-respCh:
+		if err := cehttp.WriteResponseWriter(ctx, binding.ToMessage(resp), http.StatusOK, writer); err != nil {
+			h.logger.Error("failed to write response event", zap.Error(err))
+		}
+
+	case <-timer.C:
+		h.logger.Info("Timed out waiting for a response event", zap.String("synchronizer", s.name),
+			zap.String("key", key), zap.Duration("timeout", s.timeout))
+		writer.WriteHeader(http.StatusGatewayTimeout)
+
+	case <-ctx.Done():
+		h.logger.Debug("Client closed the connection before a response event was received",
+			zap.String("synchronizer", s.name), zap.String("key", key))
+	}
+}
+
+// handleResponse hands the given response event over to the request which
+// waits for it.
+func (h *Handler) handleResponse(writer http.ResponseWriter, s *synchronizerEntry, key string, event *cloudevents.Event) {
+	if !h.sessions.deliver(sessionKey{synchronizer: s.name, correlationKey: key}, event) {
+		h.logger.Info("No request is waiting for the response event", zap.String("synchronizer", s.name),
+			zap.String("key", key), zap.String("id", event.ID()))
+		// not retryable, the request timed out or was already answered
+		writer.WriteHeader(http.StatusGone)
+		return
+	}
+
+	writer.WriteHeader(http.StatusAccepted)
+}
+
+// newRequestEvent returns an event which contains the payload of the given
+// HTTP request.
+func newRequestEvent(s *synchronizerEntry, request *http.Request) (*cloudevents.Event, error) {
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.New().String())
+	event.SetType(v1alpha1.SynchronizerGenericEventType)
+	event.SetSource(s.source)
+	event.SetTime(time.Now())
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading request body: %w", err)
+	}
+
+	if len(body) > 0 {
+		contentType := request.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		if err := event.SetData(contentType, body); err != nil {
+			return nil, fmt.Errorf("setting event data: %w", err)
+		}
+	}
+
+	return &event, nil
+}
+
+// correlationKey returns the value of the correlation extension of the given
+// event, if set.
+func correlationKey(event *cloudevents.Event, attr string) (string, bool) {
+	val, ok := event.Extensions()[attr]
+	if !ok {
+		return "", false
+	}
+
+	key, ok := val.(string)
+	return key, ok && key != ""
+}
+
+// sendAndCheck sends the given event to the target and returns an error if
+// the target doesn't acknowledge it.
+func (h *Handler) sendAndCheck(ctx context.Context, headers http.Header, target string, event *cloudevents.Event) error {
+	req, err := h.sender.NewCloudEventRequestWithTarget(ctx, target)
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	message := binding.ToMessage(event)
+	// cannot be err, but makes linter complain about missing err check
+	//nolint
+	defer message.Finish(nil)
+
+	additionalHeaders := utils.PassThroughHeaders(headers)
+	err = kncloudevents.WriteHTTPRequestWithAdditionalHeaders(ctx, message, req, additionalHeaders)
+	if err != nil {
+		return fmt.Errorf("failed to write request: %w", err)
+	}
+
+	resp, err := h.sender.Send(req)
+	if err != nil {
+		return fmt.Errorf("failed to dispatch message: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("target responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// parseRequestURI returns the name of the Synchronizer targeted by the given
+// path, and whether the path is its callback path for response events.
+func parseRequestURI(path string) (string, bool, error) {
+	parts := strings.Split(path, "/")
+	switch {
+	case len(parts) == 2:
+		return parts[1], false, nil
+	case len(parts) == 3 && parts[2] == responsePath:
+		return parts[1], true, nil
+	}
+	return "", false, fmt.Errorf("incorrect path, expected /<name> or /<name>/%s, actual '%s'", responsePath, path)
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synchronizer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestHandler(t *testing.T) {
+	h := newTestHandler(t)
+
+	// respond sends a response event with the given correlation key to
+	// the callback path of the Synchronizer, and returns the status code
+	// of the reply.
+	respond := func(key string) int {
+		req := structuredRequest(responseEvent(key))
+		req.RequestURI = "/test/response"
+		return serve(h, req).Code
+	}
+
+	testCases := []struct {
+		name string
+		// sink handles the request event sent by the Synchronizer
+		sink       func(w http.ResponseWriter, e *cloudevents.Event)
+		request    *http.Request
+		expectCode int
+		expectBody string
+	}{{
+		name: "plain request answered by a response event",
+		sink: func(w http.ResponseWriter, e *cloudevents.Event) {
+			assert.Equal(t, v1alpha1.SynchronizerGenericEventType, e.Type())
+			assert.Equal(t, "synchronizer/test", e.Source())
+			assert.Equal(t, "hello", string(e.Data()))
+
+			key, ok := correlationKey(e, v1alpha1.SynchronizerExtensionCorrelationID)
+			assert.True(t, ok, "Request events carry a correlation key")
+			assert.Equal(t, http.StatusAccepted, respond(key))
+
+			w.WriteHeader(http.StatusAccepted)
+		},
+		request:    plainRequest("hello"),
+		expectCode: http.StatusOK,
+		expectBody: `{"ok":true}`,
+	}, {
+		name: "event request answered by a response event",
+		sink: func(w http.ResponseWriter, e *cloudevents.Event) {
+			assert.Equal(t, "test.request", e.Type())
+
+			key, ok := correlationKey(e, v1alpha1.SynchronizerExtensionCorrelationID)
+			assert.True(t, ok, "Request events carry a correlation key")
+			assert.Equal(t, http.StatusAccepted, respond(key))
+
+			w.WriteHeader(http.StatusAccepted)
+		},
+		request: structuredRequest(`{"specversion":"1.0","id":"req","type":"test.request","source":"test",` +
+			`"datacontenttype":"application/json","data":{"q":1}}`),
+		expectCode: http.StatusOK,
+		expectBody: `{"ok":true}`,
+	}, {
+		name: "no response event",
+		sink: func(w http.ResponseWriter, e *cloudevents.Event) {
+			w.WriteHeader(http.StatusAccepted)
+		},
+		request:    plainRequest("hello"),
+		expectCode: http.StatusGatewayTimeout,
+	}, {
+		name: "response event sent as a request",
+		sink: func(w http.ResponseWriter, e *cloudevents.Event) {
+			assert.Fail(t, "Request events carrying a correlation key aren't sent to the sink")
+			w.WriteHeader(http.StatusAccepted)
+		},
+		request:    structuredRequest(responseEvent("some-key")),
+		expectCode: http.StatusBadRequest,
+	}, {
+		name: "request rejected by the sink",
+		sink: func(w http.ResponseWriter, e *cloudevents.Event) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+		request:    plainRequest("hello"),
+		expectCode: http.StatusBadGateway,
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+				if !assert.NoError(t, err) {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				tc.sink(w, e)
+			}))
+			defer sink.Close()
+
+			register(t, h, sink.URL)
+
+			rec := serve(h, tc.request)
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectBody != "" {
+				assert.JSONEq(t, tc.expectBody, rec.Body.String())
+				assert.Equal(t, "test.response", rec.Header().Get("Ce-Type"))
+			}
+		})
+	}
+
+	t.Run("response event with an unknown key", func(t *testing.T) {
+		assert.Equal(t, http.StatusGone, respond("unknown"))
+	})
+
+	t.Run("response event after timeout", func(t *testing.T) {
+		var key string
+
+		sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+			if !assert.NoError(t, err) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			key, _ = correlationKey(e, v1alpha1.SynchronizerExtensionCorrelationID)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer sink.Close()
+
+		register(t, h, sink.URL)
+
+		assert.Equal(t, http.StatusGatewayTimeout, serve(h, plainRequest("hello")).Code)
+		require.NotEmpty(t, key)
+		assert.Equal(t, http.StatusGone, respond(key))
+	})
+
+	t.Run("response without correlation key", func(t *testing.T) {
+		req := structuredRequest(`{"specversion":"1.0","id":"resp","type":"test.response","source":"test"}`)
+		req.RequestURI = "/test/response"
+		assert.Equal(t, http.StatusBadRequest, serve(h, req).Code)
+	})
+
+	t.Run("plain response", func(t *testing.T) {
+		req := plainRequest("hello")
+		req.RequestURI = "/test/response"
+		assert.Equal(t, http.StatusBadRequest, serve(h, req).Code)
+	})
+
+	t.Run("unknown synchronizer", func(t *testing.T) {
+		req := plainRequest("hello")
+		req.RequestURI = "/other"
+		assert.Equal(t, http.StatusBadRequest, serve(h, req).Code)
+	})
+}
+
+// newTestHandler returns a Handler which serves no Synchronizer.
+func newTestHandler(t *testing.T) *Handler {
+	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	require.NoError(t, err)
+
+	return &Handler{
+		sender: sender,
+		logger: zap.NewNop().Sugar(),
+
+		synchronizers: newRoutingTableStore(),
+		sessions:      newSessionStorage(),
+	}
+}
+
+// register registers a Synchronizer named "test" with the given Handler.
+// The Synchronizer sends request events to the given sink, and times out
+// quickly.
+func register(t *testing.T, h *Handler, sink string) {
+	timeout := "100ms"
+
+	s := &v1alpha1.Synchronizer{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "test",
+		},
+		Spec: v1alpha1.SynchronizerSpec{
+			Response: v1alpha1.SynchronizerResponse{
+				Timeout: &timeout,
+			},
+		},
+	}
+
+	sinkURI, err := apis.ParseURL(sink)
+	require.NoError(t, err)
+	s.Status.SinkURI = sinkURI
+
+	require.NoError(t, h.RegisterHandlerFor(context.Background(), s))
+}
+
+// plainRequest returns a HTTP request to the Synchronizer "test" which
+// doesn't contain a CloudEvent.
+func plainRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain")
+	return req
+}
+
+// structuredRequest returns a HTTP request to the Synchronizer "test" which
+// contains the given structured CloudEvent.
+func structuredRequest(event string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(event))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	return req
+}
+
+// responseEvent returns a structured response event which carries the given
+// correlation key.
+func responseEvent(key string) string {
+	return `{"specversion":"1.0","id":"resp","type":"test.response","source":"test",` +
+		`"correlationid":"` + key + `","datacontenttype":"application/json","data":{"ok":true}}`
+}
+
+// serve serves the given request with the given Handler.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestParseRequestURI(t *testing.T) {
+	testCases := map[string]struct {
+		path       string
+		expectName string
+		expectResp bool
+		expectErr  bool
+	}{
+		"request": {
+			path:       "/test",
+			expectName: "test",
+		},
+		"response": {
+			path:       "/test/response",
+			expectName: "test",
+			expectResp: true,
+		},
+		"unknown sub-path": {
+			path:      "/test/other",
+			expectErr: true,
+		},
+		"too many parts": {
+			path:      "/test/response/other",
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			name, isResponse, err := parseRequestURI(tc.path)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectName, name)
+			assert.Equal(t, tc.expectResp, isResponse)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/reconciler"
+
+	adaptercontroller "github.com/triggermesh/routing/pkg/adapter/common/controller"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	reconcilerv1alpha1 "github.com/triggermesh/routing/pkg/client/generated/injection/reconciler/flow/v1alpha1/synchronizer"
+)
+
+// Reconciler implements controller.Reconciler for the event source type.
+type Reconciler struct {
+	adapter MTAdapter
+	// scope determines which Synchronizers are served by the adapter
+	scope adaptercontroller.Scope
+	// patch acknowledges the configuration loaded by the adapter
+	patch adaptercontroller.PatchFunc
+}
+
+// Check the interfaces Reconciler should implement.
+var (
+	_ reconcilerv1alpha1.Interface         = (*Reconciler)(nil)
+	_ reconcilerv1alpha1.ReadOnlyInterface = (*Reconciler)(nil)
+	_ reconcilerv1alpha1.ReadOnlyFinalizer = (*Reconciler)(nil)
+	_ reconciler.OnDeletionInterface       = (*Reconciler)(nil)
+)
+
+// ReconcileKind implements reconcilerv1alpha1.Interface.
+func (r *Reconciler) ReconcileKind(ctx context.Context, s *v1alpha1.Synchronizer) reconciler.Event {
+	return r.reconcile(ctx, s, true)
+}
+
+// ObserveKind implements reconcilerv1alpha1.ReadOnlyInterface.
+func (r *Reconciler) ObserveKind(ctx context.Context, s *v1alpha1.Synchronizer) reconciler.Event {
+	return r.reconcile(ctx, s, false)
+}
+
+// reconcile registers the given Synchronizer with the adapter. Only the leader
+// acknowledges the outcome of the registration, so that acknowledgement
+// reflects the state of the leader. Other replicas register the same
+// generation from their own informer, and may lag slightly behind.
+func (r *Reconciler) reconcile(ctx context.Context, s *v1alpha1.Synchronizer, acknowledge bool) error {
+	if !r.scope.Serves(s) {
+		// The Synchronizer is served by another adapter, which also
+		// acknowledges its registration.
+		if err := r.adapter.DeregisterHandlerFor(ctx, keyOf(s)); err != nil {
+			return fmt.Errorf("deregistering HTTP handler: %w", err)
+		}
+		return nil
+	}
+
+	if s.Status.SinkURI == nil {
+		// Stop serving a state which no longer matches the Synchronizer.
+		if err := r.adapter.DeregisterHandlerFor(ctx, keyOf(s)); err != nil {
+			return fmt.Errorf("deregistering HTTP handler: %w", err)
+		}
+
+		// Mark that error as permanent so we don't retry until the
+		// source's status has been updated, which automatically
+		// triggers a new reconciliation.
+		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning, ReasonSourceNotReady,
+			"Sink URL wasn't resolved yet. Skipping adapter configuration"))
+	}
+
+	err := r.adapter.RegisterHandlerFor(ctx, s)
+
+	if acknowledge {
+		if ackErr := adaptercontroller.AcknowledgeSync(ctx, s, err, r.patch); ackErr != nil {
+			return fmt.Errorf("acknowledging adapter sync: %w", ackErr)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("registering HTTP handler: %w", err)
+	}
+
+	return nil
+}
+
+// ObserveFinalizeKind implements reconcilerv1alpha1.ReadOnlyFinalizer.
+func (r *Reconciler) ObserveFinalizeKind(ctx context.Context, s *v1alpha1.Synchronizer) reconciler.Event {
+	return r.finalize(ctx, s)
+}
+
+func (r *Reconciler) finalize(ctx context.Context, s *v1alpha1.Synchronizer) error {
+	if err := r.adapter.DeregisterHandlerFor(ctx, keyOf(s)); err != nil {
+		return fmt.Errorf("deregistering HTTP handler: %w", err)
+	}
+
+	return reconciler.NewEvent(corev1.EventTypeNormal, ReasonHandlerDeregistered,
+		"HTTP handler deregistered")
+}
+
+// ObserveDeletion implements reconciler.OnDeletionInterface.
+//
+// Synchronizers don't carry a finalizer, so their deletion is only observed as the
+// disappearance of the object from the informer's cache.
+func (r *Reconciler) ObserveDeletion(ctx context.Context, key types.NamespacedName) error {
+	if err := r.adapter.DeregisterHandlerFor(ctx, key); err != nil {
+		return fmt.Errorf("deregistering HTTP handler: %w", err)
+	}
+	return nil
+}
+
+// keyOf returns the key of the given Synchronizer.
+func keyOf(s *v1alpha1.Synchronizer) types.NamespacedName {
+	return types.NamespacedName{Namespace: s.Namespace, Name: s.Name}
+}
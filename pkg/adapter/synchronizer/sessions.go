@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synchronizer
+
+import (
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+)
+
+// sessionKey identifies a request which waits for a response event.
+type sessionKey struct {
+	// name of the Synchronizer which received the request
+	synchronizer string
+	// correlation key of the request
+	correlationKey string
+}
+
+// sessionStorage holds the requests which wait for a response event.
+type sessionStorage struct {
+	sync.Mutex
+	sessions map[sessionKey]chan *cloudevents.Event
+}
+
+func newSessionStorage() *sessionStorage {
+	return &sessionStorage{
+		sessions: make(map[sessionKey]chan *cloudevents.Event),
+	}
+}
+
+// add opens a session for the given key and returns the channel which
+// receives the response event of that session.
+func (s *sessionStorage) add(k sessionKey) <-chan *cloudevents.Event {
+	s.Lock()
+	defer s.Unlock()
+
+	// buffered to never block the delivery of a response
+	ch := make(chan *cloudevents.Event, 1)
+	s.sessions[k] = ch
+
+	return ch
+}
+
+// delete closes the session with the given key, if it is still open.
+func (s *sessionStorage) delete(k sessionKey) {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.sessions, k)
+}
+
+// deliver hands the given response event over to the session with the given
+// key, and closes that session. It returns false if no session with that key
+// is open, e.g. because the request timed out.
+func (s *sessionStorage) deliver(k sessionKey, e *cloudevents.Event) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	ch, exists := s.sessions[k]
+	if !exists {
+		return false
+	}
+	delete(s.sessions, k)
+
+	ch <- e
+	return true
+}
+
+// newCorrelationKey returns a random correlation key.
+func newCorrelationKey() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synchronizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+func TestSessionStorage(t *testing.T) {
+	s := newSessionStorage()
+	k := sessionKey{synchronizer: "test", correlationKey: "0123"}
+
+	response := cloudevents.NewEvent()
+	response.SetID("response")
+
+	t.Run("response delivered to open session", func(t *testing.T) {
+		ch := s.add(k)
+
+		require.True(t, s.deliver(k, &response))
+		assert.Equal(t, &response, <-ch)
+		assert.False(t, s.deliver(k, &response), "Sessions are closed after a delivery")
+	})
+
+	t.Run("response to unknown session", func(t *testing.T) {
+		other := sessionKey{synchronizer: "other", correlationKey: k.correlationKey}
+		s.add(k)
+		defer s.delete(k)
+
+		assert.False(t, s.deliver(other, &response))
+	})
+
+	t.Run("response after timeout", func(t *testing.T) {
+		s.add(k)
+		s.delete(k)
+
+		assert.False(t, s.deliver(k, &response))
+	})
+}
+
+func TestNewCorrelationKey(t *testing.T) {
+	assert.NotEqual(t, newCorrelationKey(), newCorrelationKey())
+}
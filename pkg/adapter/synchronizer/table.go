@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synchronizer
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+// synchronizerEntry is the resolved state of a Synchronizer, i.e. everything
+// the adapter needs to know about a Synchronizer to handle the requests it
+// receives. Entries are immutable once they are part of a routingTable.
+type synchronizerEntry struct {
+	namespace string
+	name      string
+	// source is the source attribute of the events created from plain
+	// HTTP requests.
+	source string
+
+	sinkURI string
+
+	// correlationAttr is the name of the CloudEvent extension which
+	// carries correlation keys.
+	correlationAttr string
+	timeout         time.Duration
+}
+
+// newSynchronizerEntry resolves the state of the given Synchronizer.
+func newSynchronizerEntry(s *v1alpha1.Synchronizer) *synchronizerEntry {
+	attr := s.Spec.CorrelationKey.Attribute
+	if attr == "" {
+		attr = v1alpha1.SynchronizerExtensionCorrelationID
+	}
+
+	return &synchronizerEntry{
+		namespace:       s.Namespace,
+		name:            s.Name,
+		source:          s.AsRouter(),
+		sinkURI:         s.Status.SinkURI.String(),
+		correlationAttr: attr,
+		timeout:         s.ResponseTimeout(),
+	}
+}
+
+// routingTable maps the names of Synchronizers to their resolved state.
+type routingTable map[string]*synchronizerEntry
+
+// routingTableStore holds a routingTable which can be read without locking.
+// Writers replace the whole table with an updated copy.
+type routingTableStore struct {
+	// serializes writers
+	mu sync.Mutex
+	// always holds a routingTable
+	table atomic.Value
+}
+
+func newRoutingTableStore() *routingTableStore {
+	s := &routingTableStore{}
+	s.table.Store(make(routingTable))
+	return s
+}
+
+// get returns the resolved state of the Synchronizer with the given name.
+func (s *routingTableStore) get(name string) (*synchronizerEntry, bool) {
+	e, exists := s.table.Load().(routingTable)[name]
+	return e, exists
+}
+
+// set inserts or replaces the resolved state of a Synchronizer.
+func (s *routingTableStore) set(e *synchronizerEntry) {
+	s.update(func(t routingTable) {
+		t[e.name] = e
+	})
+}
+
+// delete removes the resolved state of the Synchronizer with the given name.
+func (s *routingTableStore) delete(name string) {
+	s.update(func(t routingTable) {
+		delete(t, name)
+	})
+}
+
+// update applies the given function to a copy of the current table, and
+// atomically swaps the current table for that copy.
+func (s *routingTableStore) update(fn func(routingTable)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	curr := s.table.Load().(routingTable)
+
+	next := make(routingTable, len(curr)+1)
+	for k, v := range curr {
+		next[k] = v
+	}
+	fn(next)
+
+	s.table.Store(next)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flow
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations set on routers by their adapter to acknowledge the
+// configuration it loaded.
+const (
+	// AdapterGenerationAnnotation contains the generation of the router
+	// which was last processed by the adapter.
+	AdapterGenerationAnnotation = GroupName + "/adapter-generation"
+	// AdapterErrorAnnotation describes the error which prevented the
+	// adapter from loading the generation of the router referenced by the
+	// AdapterGenerationAnnotation. Absent if that generation was loaded
+	// successfully.
+	AdapterErrorAnnotation = GroupName + "/adapter-error"
+)
+
+// AdapterSync returns the generation of the given router which was last
+// processed by its adapter, along with the error which occurred while
+// loading it, if any. A negative generation indicates that the adapter
+// didn't acknowledge any generation of the router yet.
+func AdapterSync(obj metav1.Object) (generation int64, errMsg string) {
+	ann := obj.GetAnnotations()
+
+	generation, err := strconv.ParseInt(ann[AdapterGenerationAnnotation], 10, 64)
+	if err != nil {
+		return -1, ""
+	}
+
+	return generation, ann[AdapterErrorAnnotation]
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdapterSync(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expectGen   int64
+		expectErr   string
+	}{
+		"not acknowledged": {
+			annotations: nil,
+			expectGen:   -1,
+		},
+		"loaded": {
+			annotations: map[string]string{AdapterGenerationAnnotation: "3"},
+			expectGen:   3,
+		},
+		"failed to load": {
+			annotations: map[string]string{
+				AdapterGenerationAnnotation: "3",
+				AdapterErrorAnnotation:      "compiling expression: syntax error",
+			},
+			expectGen: 3,
+			expectErr: "compiling expression: syntax error",
+		},
+		"invalid generation": {
+			annotations: map[string]string{AdapterGenerationAnnotation: "three"},
+			expectGen:   -1,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tc.annotations}
+
+			gen, errMsg := AdapterSync(obj)
+			assert.Equal(t, tc.expectGen, gen)
+			assert.Equal(t, tc.expectErr, errMsg)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flow
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+)
+
+// AdapterIsolationAnnotation is an annotation which selects whether a router
+// shares the multi-tenant adapter of its type with the other routers of its
+// namespace, or has an adapter of its own.
+const AdapterIsolationAnnotation = GroupName + "/adapter-isolation"
+
+// Supported values of the AdapterIsolationAnnotation.
+const (
+	// AdapterIsolationShared makes a router use the multi-tenant adapter
+	// of its type. This is the default.
+	AdapterIsolationShared = "shared"
+	// AdapterIsolationDedicated makes a router use a dedicated adapter.
+	AdapterIsolationDedicated = "dedicated"
+)
+
+// HasDedicatedAdapter returns whether the given router opted into a dedicated
+// adapter.
+func HasDedicatedAdapter(obj metav1.Object) bool {
+	return obj.GetAnnotations()[AdapterIsolationAnnotation] == AdapterIsolationDedicated
+}
+
+// ValidateAdapterIsolation validates the value of the AdapterIsolationAnnotation
+// of the given object, if set.
+func ValidateAdapterIsolation(obj metav1.Object) *apis.FieldError {
+	val, ok := obj.GetAnnotations()[AdapterIsolationAnnotation]
+	if !ok {
+		return nil
+	}
+
+	switch val {
+	case AdapterIsolationShared, AdapterIsolationDedicated:
+		return nil
+	default:
+		return apis.ErrInvalidValue(val, apis.CurrentField).
+			ViaFieldKey("annotations", AdapterIsolationAnnotation).ViaField("metadata")
+	}
+}
+
+// ValidateAdapterOverrides verifies that adapter overrides are only set on
+// objects which opted into a dedicated adapter. The shared adapter serves every
+// object of the same kind in the namespace, so overrides can't be applied to it.
+func ValidateAdapterOverrides(obj metav1.Object, hasOverrides bool) *apis.FieldError {
+	if !hasOverrides || HasDedicatedAdapter(obj) {
+		return nil
+	}
+
+	return &apis.FieldError{
+		Message: "adapter overrides require a dedicated adapter",
+		Paths:   []string{"spec.adapterOverrides"},
+		Details: "Set the annotation " + AdapterIsolationAnnotation + " to " + AdapterIsolationDedicated,
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdapterIsolation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations        map[string]string
+		overrides          bool
+		expectDedicated    bool
+		expectErr          string
+		expectOverridesErr string
+	}{
+		"no annotation": {
+			annotations: nil,
+		},
+		"shared": {
+			annotations: map[string]string{AdapterIsolationAnnotation: AdapterIsolationShared},
+		},
+		"dedicated": {
+			annotations:     map[string]string{AdapterIsolationAnnotation: AdapterIsolationDedicated},
+			expectDedicated: true,
+		},
+		"invalid value": {
+			annotations: map[string]string{AdapterIsolationAnnotation: "isolated"},
+			expectErr:   "invalid value: isolated: metadata.annotations.[flow.triggermesh.io/adapter-isolation]",
+		},
+		"overrides without annotation": {
+			annotations:        nil,
+			overrides:          true,
+			expectOverridesErr: "adapter overrides require a dedicated adapter: spec.adapterOverrides",
+		},
+		"overrides with shared adapter": {
+			annotations:        map[string]string{AdapterIsolationAnnotation: AdapterIsolationShared},
+			overrides:          true,
+			expectOverridesErr: "adapter overrides require a dedicated adapter: spec.adapterOverrides",
+		},
+		"overrides with dedicated adapter": {
+			annotations:     map[string]string{AdapterIsolationAnnotation: AdapterIsolationDedicated},
+			overrides:       true,
+			expectDedicated: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Annotations: tc.annotations,
+			}
+
+			assert.Equal(t, tc.expectDedicated, HasDedicatedAdapter(obj))
+
+			if err := ValidateAdapterIsolation(obj); tc.expectErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectErr)
+			}
+
+			if err := ValidateAdapterOverrides(obj, tc.overrides); tc.expectOverridesErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.Contains(t, err.Error(), tc.expectOverridesErr)
+			}
+		})
+	}
+}
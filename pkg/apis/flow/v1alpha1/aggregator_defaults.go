@@ -0,0 +1,42 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// SetDefaults implements apis.Defaultable
+func (a *Aggregator) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, a.ObjectMeta)
+
+	// Without any explicit configuration, events are aggregated according to
+	// the correlation extensions set by the Splitter.
+	corr := &a.Spec.Correlation
+	if corr.Attribute == "" && corr.Path == "" {
+		corr.Attribute = SplitterExtensionParentID
+	}
+
+	compl := &a.Spec.Completion
+	if compl.Count == nil && compl.TotalExtension == "" && compl.Expression == "" && compl.Timeout == nil {
+		compl.TotalExtension = SplitterExtensionTotal
+	}
+
+	setDeliveryDefaults(ctx, a.Spec.Delivery)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+// GetGroupVersionKind implements kmeta.OwnerRefable
+func (*Aggregator) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Aggregator")
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (a *Aggregator) GetConditionSet() apis.ConditionSet {
+	return routerConditionSet
+}
+
+// GetAdapterOverrides implements adapterOverrider.
+func (a *Aggregator) GetAdapterOverrides() *AdapterOverrides {
+	return a.Spec.AdapterOverrides
+}
+
+// GetDelivery implements deliverer.
+func (a *Aggregator) GetDelivery() *eventingduckv1.DeliverySpec {
+	return a.Spec.Delivery
+}
+
+// IsMultiTenant implements MultiTenant.
+func (a *Aggregator) IsMultiTenant() bool {
+	return !flow.HasDedicatedAdapter(a)
+}
+
+// Supported event types
+const (
+	AggregatorGenericEventType = "io.triggermesh.routing.aggregator"
+)
+
+// GetEventTypes implements Reconcilable.
+func (*Aggregator) GetEventTypes() []string {
+	return []string{
+		AggregatorGenericEventType,
+	}
+}
+
+// GetCloudEventAttributes returns the context attributes of the batch events
+// produced by the Aggregator.
+func (a *Aggregator) GetCloudEventAttributes() []duckv1.CloudEventAttributes {
+	return []duckv1.CloudEventAttributes{{
+		Type:   a.OutputType(),
+		Source: a.OutputSource(),
+	}}
+}
+
+// OutputType returns the type of the batch events produced by the Aggregator.
+func (a *Aggregator) OutputType() string {
+	if t := a.Spec.Output.CEContext.Type; t != "" {
+		return t
+	}
+	return AggregatorGenericEventType
+}
+
+// OutputSource returns the source of the batch events produced by the
+// Aggregator.
+func (a *Aggregator) OutputSource() string {
+	if s := a.Spec.Output.CEContext.Source; s != "" {
+		return s
+	}
+	return a.AsRouter()
+}
+
+// GetSink implements Reconcilable.
+func (a *Aggregator) GetSink() *duckv1.Destination {
+	return a.Spec.Sink
+}
+
+// GetStatusManager implements Reconcilable.
+func (a *Aggregator) GetStatusManager() *RouterStatusManager {
+	return &RouterStatusManager{
+		ConditionSet: a.GetConditionSet(),
+		RouterStatus: &a.Status,
+	}
+}
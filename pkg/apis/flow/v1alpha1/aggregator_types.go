@@ -0,0 +1,152 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Aggregator is an addressable object that reassembles correlated events into
+// a single batch event
+type Aggregator struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the Aggregator (from the client).
+	// +optional
+	Spec AggregatorSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the Aggregator (from the controller).
+	// +optional
+	Status RouterStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that Aggregator can be validated and defaulted.
+	_ apis.Validatable   = (*Aggregator)(nil)
+	_ apis.Defaultable   = (*Aggregator)(nil)
+	_ kmeta.OwnerRefable = (*Aggregator)(nil)
+	// Check that the type conforms to the duck Knative Resource shape.
+	_ duckv1.KRShaped  = (*Aggregator)(nil)
+	_ multiTenant      = (*Aggregator)(nil)
+	_ adapterOverrider = (*Aggregator)(nil)
+	_ eventAttributer  = (*Aggregator)(nil)
+	_ deliverer        = (*Aggregator)(nil)
+
+	_ Reconcilable = (*Aggregator)(nil)
+)
+
+// AggregatorSpec holds the desired state of the Aggregator
+type AggregatorSpec struct {
+	// Correlation determines which events belong to the same batch.
+	// +optional
+	Correlation AggregatorCorrelation `json:"correlation,omitempty"`
+
+	// Completion determines when a batch is complete.
+	// +optional
+	Completion AggregatorCompletion `json:"completion,omitempty"`
+
+	// Output determines the shape of the produced batch events.
+	// +optional
+	Output AggregatorOutput `json:"output,omitempty"`
+
+	// Sink is a reference to an object that will resolve to a domain name to use as the sink.
+	Sink *duckv1.Destination `json:"sink"`
+
+	// Delivery contains the retry and dead letter sink configuration of
+	// the batch events sent by the Aggregator.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// AdapterOverrides are applied to the Pods of the Aggregator's adapter. They
+	// can only be set if the Aggregator uses a dedicated adapter.
+	// +optional
+	AdapterOverrides *AdapterOverrides `json:"adapterOverrides,omitempty"`
+}
+
+// AggregatorCorrelation determines the key of the batch an event belongs to.
+// Exactly one of its fields can be set.
+type AggregatorCorrelation struct {
+	// Attribute is the name of a CloudEvent context attribute or extension,
+	// e.g. "splitparentid".
+	// +optional
+	Attribute string `json:"attribute,omitempty"`
+	// Path is the GJSON path of a field inside the event payload.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// AggregatorCompletion determines when a batch is complete. A batch is complete
+// as soon as any of the configured conditions is met.
+type AggregatorCompletion struct {
+	// Count is the number of events which completes a batch.
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+	// TotalExtension is the name of a CloudEvent extension which contains
+	// the number of events which completes a batch, e.g. "splittotal".
+	// +optional
+	TotalExtension string `json:"totalExtension,omitempty"`
+	// Expression is a Filter expression which completes a batch when an
+	// event matches it, e.g. 'ce.extensions.splitlast == true'.
+	// +optional
+	Expression string `json:"expression,omitempty"`
+	// Timeout is the maximum duration of a batch, measured from its first
+	// event, after which the incomplete batch is sent, e.g. "30s".
+	// +optional
+	Timeout *string `json:"timeout,omitempty"`
+}
+
+// AggregatorOutput determines the context attributes and the payload of the
+// produced batch events.
+type AggregatorOutput struct {
+	// CEContext contains the context attributes of the batch events.
+	// +optional
+	CEContext CloudEventContext `json:"ceContext,omitempty"`
+	// Template is a Go template rendering the payload of the batch events.
+	// Defaults to a JSON array of the payloads of the aggregated events.
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// AggregatorList is a list of Aggregator resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type AggregatorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Aggregator `json:"items"`
+}
+
+// GetStatus retrieves the status of the resource. Implements the KRShaped interface.
+func (a *Aggregator) GetStatus() *duckv1.Status {
+	return &a.Status.Status
+}
+
+// AsRouter implements Reconcilable.
+func (a *Aggregator) AsRouter() string {
+	return "aggregator/" + a.Name
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/eventaggregator"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// Validate implements apis.Validatable
+func (a *Aggregator) Validate(ctx context.Context) *apis.FieldError {
+	return flow.ValidateAdapterIsolation(a).Also(
+		flow.ValidateAdapterOverrides(a, a.Spec.AdapterOverrides != nil),
+		a.Spec.Validate(ctx).ViaField("spec"))
+}
+
+// Validate implements apis.Validatable
+func (as *AggregatorSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if as.Sink == nil {
+		errs = errs.Also(apis.ErrMissingField("sink"))
+	}
+
+	errs = errs.Also(as.Correlation.Validate(ctx).ViaField("correlation"))
+	errs = errs.Also(as.Completion.Validate(ctx).ViaField("completion"))
+
+	if as.Output.Template != "" {
+		if _, err := eventaggregator.ParseTemplate("", as.Output.Template); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Cannot parse template: %v", err), "output.template"))
+		}
+	}
+
+	errs = errs.Also(as.Delivery.Validate(ctx).ViaField("delivery"))
+
+	// batches are kept in the memory of a single adapter instance
+	return errs.Also(as.AdapterOverrides.Validate(ctx).Also(
+		as.AdapterOverrides.validateSingleReplica()).ViaField("adapterOverrides"))
+}
+
+// Validate implements apis.Validatable
+func (ac *AggregatorCorrelation) Validate(ctx context.Context) *apis.FieldError {
+	// an empty correlation is defaulted
+	if ac.Attribute != "" && ac.Path != "" {
+		return apis.ErrMultipleOneOf("attribute", "path")
+	}
+	return nil
+}
+
+// Validate implements apis.Validatable
+func (ac *AggregatorCompletion) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if ac.Count != nil && *ac.Count < 1 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*ac.Count, 1, "∞", "count"))
+	}
+
+	if ac.Expression != "" {
+		if _, err := cel.CompileExpression(ac.Expression); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Cannot compile expression: %v", err), "expression"))
+		}
+	}
+
+	if ac.Timeout != nil {
+		if d, err := time.ParseDuration(*ac.Timeout); err != nil || d <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(*ac.Timeout, "timeout"))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/yaml"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+func TestAggregatorValidate(t *testing.T) {
+	validSpec := func() AggregatorSpec {
+		return AggregatorSpec{
+			Output: AggregatorOutput{
+				Template: `{"id": {{ json .Key }}, "items": {{ json .Items }}}`,
+			},
+			Sink: &duckv1.Destination{},
+		}
+	}
+
+	int32Ptr := func(i int32) *int32 { return &i }
+	strPtr := func(s string) *string { return &s }
+
+	testCases := []struct {
+		name      string
+		dedicated bool
+		mutate    func(*AggregatorSpec)
+		errs      []string
+	}{{
+		name:   "valid",
+		mutate: func(*AggregatorSpec) {},
+	}, {
+		name:   "missing sink",
+		mutate: func(s *AggregatorSpec) { s.Sink = nil },
+		errs:   []string{"spec.sink"},
+	}, {
+		name: "multiple correlations",
+		mutate: func(s *AggregatorSpec) {
+			s.Correlation.Attribute = "corrid"
+			s.Correlation.Path = "id"
+		},
+		errs: []string{"spec.correlation.attribute", "spec.correlation.path"},
+	}, {
+		name: "invalid completion",
+		mutate: func(s *AggregatorSpec) {
+			s.Completion.Count = int32Ptr(0)
+			s.Completion.Expression = "$foo.(string) =="
+			s.Completion.Timeout = strPtr("-1s")
+		},
+		errs: []string{"spec.completion.count", "spec.completion.expression", "spec.completion.timeout"},
+	}, {
+		name:   "unknown template function",
+		mutate: func(s *AggregatorSpec) { s.Output.Template = `{{ yaml .Items }}` },
+		errs:   []string{"spec.output.template"},
+	}, {
+		name: "invalid delivery",
+		mutate: func(s *AggregatorSpec) {
+			s.Delivery = &eventingduckv1.DeliverySpec{BackoffDelay: strPtr("1s")}
+		},
+		errs: []string{"spec.delivery.backoffDelay"},
+	}, {
+		name:      "single replica",
+		dedicated: true,
+		mutate: func(s *AggregatorSpec) {
+			s.AdapterOverrides = &AdapterOverrides{
+				Scaling: &AdapterScaling{MinScale: int32Ptr(1), MaxScale: int32Ptr(1)},
+			}
+		},
+	}, {
+		name:      "multiple replicas",
+		dedicated: true,
+		mutate: func(s *AggregatorSpec) {
+			s.AdapterOverrides = &AdapterOverrides{
+				Scaling: &AdapterScaling{MinScale: int32Ptr(0), MaxScale: int32Ptr(3)},
+			}
+		},
+		errs: []string{"spec.adapterOverrides.scaling.minScale", "spec.adapterOverrides.scaling.maxScale"},
+	}, {
+		name: "overrides on shared adapter",
+		mutate: func(s *AggregatorSpec) {
+			s.AdapterOverrides = &AdapterOverrides{PriorityClassName: "high"}
+		},
+		errs: []string{"adapter overrides require a dedicated adapter: spec.adapterOverrides"},
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			a := &Aggregator{Spec: validSpec()}
+			if tc.dedicated {
+				a.Annotations = map[string]string{flow.AdapterIsolationAnnotation: flow.AdapterIsolationDedicated}
+			}
+			tc.mutate(&a.Spec)
+
+			err := a.Validate(context.Background())
+			if len(tc.errs) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			if assert.NotNil(t, err) {
+				for _, p := range tc.errs {
+					assert.Contains(t, err.Error(), p)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregatorValidateSample(t *testing.T) {
+	manifests, err := ioutil.ReadFile("../../../../config/samples/aggregator.yaml")
+	require.NoError(t, err)
+
+	var found bool
+
+	for _, doc := range strings.Split(string(manifests), "\n---\n") {
+		a := &Aggregator{}
+		require.NoError(t, yaml.Unmarshal([]byte(doc), a))
+		if a.Kind != "Aggregator" {
+			continue
+		}
+		found = true
+
+		a.SetDefaults(context.Background())
+		assert.Nil(t, a.Validate(context.Background()))
+	}
+
+	assert.True(t, found, "The sample contains an Aggregator")
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1beta1"
+)
+
+// ConvertTo converts the RouterStatus to a higher version.
+func (rs *RouterStatus) ConvertTo(ctx context.Context, sink *v1beta1.RouterStatus) {
+	sink.SourceStatus = rs.SourceStatus
+	sink.AddressStatus = rs.AddressStatus
+	sink.ErrorSinkURI = rs.ErrorSinkURI
+	sink.DeadLetterSinkURI = rs.DeadLetterSinkURI
+}
+
+// ConvertFrom converts a higher version of RouterStatus to v1alpha1.
+func (rs *RouterStatus) ConvertFrom(ctx context.Context, source *v1beta1.RouterStatus) {
+	rs.SourceStatus = source.SourceStatus
+	rs.AddressStatus = source.AddressStatus
+	rs.ErrorSinkURI = source.ErrorSinkURI
+	rs.DeadLetterSinkURI = source.DeadLetterSinkURI
+}
+
+// convertAdapterOverridesTo converts AdapterOverrides to a higher version.
+func convertAdapterOverridesTo(o *AdapterOverrides) *v1beta1.AdapterOverrides {
+	if o == nil {
+		return nil
+	}
+
+	sink := &v1beta1.AdapterOverrides{
+		Resources:         o.Resources,
+		NodeSelector:      o.NodeSelector,
+		Tolerations:       o.Tolerations,
+		PriorityClassName: o.PriorityClassName,
+	}
+	if s := o.Scaling; s != nil {
+		sink.Scaling = &v1beta1.AdapterScaling{
+			MinScale: s.MinScale,
+			MaxScale: s.MaxScale,
+			Target:   s.Target,
+		}
+	}
+
+	return sink
+}
+
+// convertAdapterOverridesFrom converts a higher version of AdapterOverrides
+// to v1alpha1.
+func convertAdapterOverridesFrom(source *v1beta1.AdapterOverrides) *AdapterOverrides {
+	if source == nil {
+		return nil
+	}
+
+	o := &AdapterOverrides{
+		Resources:         source.Resources,
+		NodeSelector:      source.NodeSelector,
+		Tolerations:       source.Tolerations,
+		PriorityClassName: source.PriorityClassName,
+	}
+	if s := source.Scaling; s != nil {
+		o.Scaling = &AdapterScaling{
+			MinScale: s.MinScale,
+			MaxScale: s.MaxScale,
+			Target:   s.Target,
+		}
+	}
+
+	return o
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+// defaultDeliveryRetry is the default number of retries of events which
+// could not be delivered.
+const defaultDeliveryRetry int32 = 0
+
+// setDeliveryDefaults sets the default values of the given delivery spec,
+// if any. The context is expected to carry the metadata of the parent object.
+func setDeliveryDefaults(ctx context.Context, ds *eventingduckv1.DeliverySpec) {
+	if ds == nil {
+		return
+	}
+
+	if ds.Retry == nil {
+		ds.Retry = ptr.Int32(defaultDeliveryRetry)
+	}
+
+	// backoff delays are ignored without a backoff policy
+	if ds.BackoffDelay != nil && ds.BackoffPolicy == nil {
+		p := eventingduckv1.BackoffPolicyExponential
+		ds.BackoffPolicy = &p
+	}
+
+	if ds.DeadLetterSink != nil {
+		ds.DeadLetterSink.SetDefaults(ctx)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"go.uber.org/zap"
 
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	coreclientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	"knative.dev/eventing/pkg/apis/duck"
@@ -31,6 +32,7 @@ import (
 	"knative.dev/pkg/logging"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
+	"github.com/triggermesh/routing/pkg/apis/flow"
 	"github.com/triggermesh/routing/pkg/status"
 )
 
@@ -57,6 +59,7 @@ func NewRouterConditionSet(cts ...apis.ConditionType) apis.ConditionSet {
 var routerConditionTypes = []apis.ConditionType{
 	ConditionSinkProvided,
 	ConditionDeployed,
+	ConditionAdapterSynced,
 }
 
 // RouterStatusManager manages the status of routers.
@@ -78,9 +81,47 @@ func (m *RouterStatusManager) MarkSink(uri *apis.URL) {
 	m.Manage(m).MarkTrue(ConditionSinkProvided)
 }
 
+// MarkRouteSinks sets the SinkProvided condition to True using the given
+// default sink URI and the URIs of the sinks of each route.
+func (m *RouterStatusManager) MarkRouteSinks(defaultURI *apis.URL, routeURIs []*apis.URL) {
+	m.SinkURI = defaultURI
+
+	m.Routes = make([]RouteStatus, len(routeURIs))
+	for i, uri := range routeURIs {
+		m.Routes[i].SinkURI = uri
+	}
+
+	if len(routeURIs) == 0 {
+		m.Manage(m).MarkFalse(ConditionSinkProvided,
+			ReasonSinkEmpty, "The router has no route")
+		return
+	}
+	for i, uri := range routeURIs {
+		if uri == nil {
+			m.Manage(m).MarkFalse(ConditionSinkProvided,
+				ReasonSinkEmpty, "The sink of route %d has no URI", i)
+			return
+		}
+	}
+	m.Manage(m).MarkTrue(ConditionSinkProvided)
+}
+
+// MarkErrorSink sets the URI of the error sink.
+func (m *RouterStatusManager) MarkErrorSink(uri *apis.URL) {
+	m.ErrorSinkURI = uri
+}
+
+// MarkDeadLetterSink sets the URI of the dead letter sink.
+func (m *RouterStatusManager) MarkDeadLetterSink(uri *apis.URL) {
+	m.DeadLetterSinkURI = uri
+}
+
 // MarkNoSink sets the SinkProvided condition to False.
 func (m *RouterStatusManager) MarkNoSink() {
 	m.SinkURI = nil
+	m.Routes = nil
+	m.ErrorSinkURI = nil
+	m.DeadLetterSinkURI = nil
 	m.ConditionSet.Manage(m).MarkFalse(ConditionSinkProvided,
 		ReasonSinkNotFound, "The sink does not exist or its URI is not set")
 }
@@ -179,6 +220,49 @@ func (m *RouterStatusManager) PropagateServiceAvailability(ksvc *servingv1.Servi
 	m.ConditionSet.Manage(m).MarkFalse(ConditionDeployed, reason, msg)
 }
 
+// PropagateAdapterSync uses the generation of the given router acknowledged
+// by its adapter to determine whether the AdapterSynced condition should be
+// marked as True or False.
+func (m *RouterStatusManager) PropagateAdapterSync(obj metav1.Object) {
+	gen, errMsg := flow.AdapterSync(obj)
+
+	switch {
+	case gen != obj.GetGeneration():
+		m.ConditionSet.Manage(m).MarkUnknown(ConditionAdapterSynced, ReasonAdapterSyncPending,
+			"The adapter has not loaded generation %d of the router yet", obj.GetGeneration())
+	case errMsg != "":
+		m.ConditionSet.Manage(m).MarkFalse(ConditionAdapterSynced, ReasonAdapterSyncFailed,
+			"The adapter failed to load generation %d of the router: %s", gen, errMsg)
+	default:
+		m.ConditionSet.Manage(m).MarkTrue(ConditionAdapterSynced)
+	}
+}
+
+// MarkExpressionValid sets the ExpressionValid condition to True.
+func (m *RouterStatusManager) MarkExpressionValid() {
+	m.ConditionSet.Manage(m).MarkTrue(ConditionExpressionValid)
+}
+
+// MarkExpressionInvalid sets the ExpressionValid condition to False with the
+// given compilation error.
+func (m *RouterStatusManager) MarkExpressionInvalid(err error) {
+	m.ConditionSet.Manage(m).MarkFalse(ConditionExpressionValid,
+		ReasonInvalidExpression, "The expression does not compile: %s", err)
+}
+
+// SetAddress sets the URL of the router's address. Intended to be used for
+// adapters which are not addressable by themselves, such as Deployments.
+func (m *RouterStatusManager) SetAddress(url *apis.URL) {
+	if url == nil {
+		m.Address = nil
+		return
+	}
+
+	m.Address = &duckv1.Addressable{
+		URL: url,
+	}
+}
+
 // SetRoute appends the given URL path to the current source's URL.
 func (m *RouterStatusManager) SetRoute(urlPath string) {
 	if m.Address == nil || m.Address.URL == nil {
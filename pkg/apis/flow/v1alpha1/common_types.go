@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
@@ -25,6 +26,28 @@ import (
 type RouterStatus struct {
 	duckv1.SourceStatus  `json:",inline"`
 	duckv1.AddressStatus `json:",inline"`
+
+	// Routes contains the observed state of each route of routers which
+	// dispatch events to multiple sinks, in the order of their declaration.
+	// +optional
+	Routes []RouteStatus `json:"routes,omitempty"`
+
+	// ErrorSinkURI is the resolved URI of the router's error sink, for
+	// routers which have one.
+	// +optional
+	ErrorSinkURI *apis.URL `json:"errorSinkUri,omitempty"`
+
+	// DeadLetterSinkURI is the resolved URI of the router's dead letter
+	// sink, for routers which have one.
+	// +optional
+	DeadLetterSinkURI *apis.URL `json:"deadLetterSinkUri,omitempty"`
+}
+
+// RouteStatus defines the observed state of a single route.
+type RouteStatus struct {
+	// SinkURI is the resolved URI of the route's sink.
+	// +optional
+	SinkURI *apis.URL `json:"sinkUri,omitempty"`
 }
 
 // ValueFromField is a struct field that can have its value either defined
@@ -39,3 +62,49 @@ type ValueFromField struct {
 	// +optional
 	ValueFromSecret *corev1.SecretKeySelector `json:"valueFromSecret,omitempty"`
 }
+
+// AdapterOverrides are properties of the adapter Pods of a router which
+// override the defaults set by the controller. They only apply to dedicated
+// adapters.
+type AdapterOverrides struct {
+	// Resources are the compute resources of the adapter container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Scaling determines the number of adapter instances.
+	// +optional
+	Scaling *AdapterScaling `json:"scaling,omitempty"`
+
+	// NodeSelector constrains the adapter Pods to nodes with matching
+	// labels.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the adapter Pods.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the adapter
+	// Pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// AdapterScaling determines the number of instances of an adapter.
+type AdapterScaling struct {
+	// MinScale is the minimum number of adapter instances. Adapters backed
+	// by a Deployment run exactly that number of instances.
+	// +optional
+	MinScale *int32 `json:"minScale,omitempty"`
+
+	// MaxScale is the maximum number of adapter instances. Only applies to
+	// adapters backed by a Knative Service.
+	// +optional
+	MaxScale *int32 `json:"maxScale,omitempty"`
+
+	// Target is the number of concurrent requests per adapter instance
+	// targeted by the autoscaler. Only applies to adapters backed by a
+	// Knative Service.
+	// +optional
+	Target *int32 `json:"target,omitempty"`
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate implements apis.Validatable
+func (o *AdapterOverrides) Validate(ctx context.Context) *apis.FieldError {
+	if o == nil {
+		return nil
+	}
+
+	return o.Scaling.Validate(ctx).ViaField("scaling")
+}
+
+// Validate implements apis.Validatable
+func (s *AdapterScaling) Validate(ctx context.Context) *apis.FieldError {
+	if s == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+
+	if s.MinScale != nil && *s.MinScale < 0 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s.MinScale, 0, "∞", "minScale"))
+	}
+	if s.MaxScale != nil && *s.MaxScale < 1 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s.MaxScale, 1, "∞", "maxScale"))
+	}
+	if s.MinScale != nil && s.MaxScale != nil && *s.MinScale > *s.MaxScale {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s.MinScale, 0, *s.MaxScale, "minScale"))
+	}
+	if s.Target != nil && *s.Target < 1 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s.Target, 1, "∞", "target"))
+	}
+
+	return errs
+}
+
+// validateSingleReplica returns an error if the given overrides scale an
+// adapter which keeps its state in memory to more or less than one instance.
+func (o *AdapterOverrides) validateSingleReplica() *apis.FieldError {
+	if o == nil || o.Scaling == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+
+	if s := o.Scaling.MinScale; s != nil && *s != 1 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s, 1, 1, "minScale"))
+	}
+	if s := o.Scaling.MaxScale; s != nil && *s != 1 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s, 1, 1, "maxScale"))
+	}
+
+	return errs.ViaField("scaling")
+}
@@ -26,6 +26,10 @@ const (
 	ConditionSinkProvided apis.ConditionType = "SinkProvided"
 	// ConditionDeployed has status True when the router's adapter is up and running.
 	ConditionDeployed apis.ConditionType = "Deployed"
+	// ConditionAdapterSynced has status True when the router's adapter has loaded the router's current generation.
+	ConditionAdapterSynced apis.ConditionType = "AdapterSynced"
+	// ConditionExpressionValid has status True when the expressions of the router compile.
+	ConditionExpressionValid apis.ConditionType = "ExpressionValid"
 )
 
 // Reasons for status conditions
@@ -40,4 +44,15 @@ const (
 	ReasonRBACNotBound = "RBACNotBound"
 	// ReasonUnavailable is set on a Deployed condition when an adapter in unavailable.
 	ReasonUnavailable = "AdapterUnavailable"
+
+	// ReasonAdapterSyncPending is set on an AdapterSynced condition when
+	// the adapter hasn't processed the current generation of a router yet.
+	ReasonAdapterSyncPending = "AdapterSyncPending"
+	// ReasonAdapterSyncFailed is set on an AdapterSynced condition when
+	// the adapter failed to load the current generation of a router.
+	ReasonAdapterSyncFailed = "AdapterSyncFailed"
+
+	// ReasonInvalidExpression is set on an ExpressionValid condition when
+	// an expression of a router does not compile.
+	ReasonInvalidExpression = "InvalidExpression"
 )
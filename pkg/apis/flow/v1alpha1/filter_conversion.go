@@ -0,0 +1,294 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1beta1"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// v1beta1ExpressionsAnnotation is an annotation which preserves the expressions
+// and variables of a v1beta1 Filter when it is converted to v1alpha1. Variables
+// are inlined in v1alpha1 expressions, which loses their names and order, so
+// the original v1beta1 representation is restored from this annotation on the
+// way back as long as the v1alpha1 expressions weren't modified in between.
+const v1beta1ExpressionsAnnotation = flow.GroupName + "/v1beta1-expressions"
+
+// filterExpressions is the v1beta1 representation of the expressions of a
+// Filter, as stored inside the v1beta1ExpressionsAnnotation.
+type filterExpressions struct {
+	Expression string             `json:"expression"`
+	Variables  []v1beta1.Variable `json:"variables,omitempty"`
+	// Transform contains the expression of each transform operation, in order.
+	Transform []string `json:"transform,omitempty"`
+}
+
+// ConvertTo implements apis.Convertible.
+// Converts the v1alpha1 Filter to a higher version.
+func (f *Filter) ConvertTo(ctx context.Context, to apis.Convertible) error {
+	switch sink := to.(type) {
+	case *v1beta1.Filter:
+		sink.ObjectMeta = f.ObjectMeta
+		f.Spec.ConvertTo(ctx, &sink.Spec)
+		f.Status.ConvertTo(ctx, &sink.Status)
+
+		if val, ok := f.Annotations[v1beta1ExpressionsAnnotation]; ok {
+			sink.ObjectMeta = *f.ObjectMeta.DeepCopy()
+			removeExpressionsAnnotation(&sink.ObjectMeta)
+			restoreExpressions(val, &f.Spec, &sink.Spec)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown version, got: %T", sink)
+	}
+}
+
+// ConvertTo converts the FilterSpec to a higher version.
+func (fs *FilterSpec) ConvertTo(ctx context.Context, sink *v1beta1.FilterSpec) {
+	sink.Expression = fs.Expression
+	sink.Variables = nil
+
+	// expressions which can't be parsed are carried over verbatim, so that
+	// they are reported by the validation of the higher version instead of
+	// failing the conversion
+	if expr, vars, err := cel.ParseExpression(fs.Expression); err == nil {
+		sink.Expression = expr
+		for _, v := range vars {
+			sink.Variables = append(sink.Variables, v1beta1.Variable{
+				Name: v.Name,
+				Path: v.Path,
+				Type: v1beta1.VariableType(v.Type),
+			})
+		}
+	}
+
+	sink.Sink = fs.Sink
+	sink.OnError = v1beta1.ErrorPolicy(fs.OnError)
+	sink.ErrorSink = fs.ErrorSink
+	sink.Delivery = fs.Delivery
+	sink.AdapterOverrides = convertAdapterOverridesTo(fs.AdapterOverrides)
+
+	sink.Transform = nil
+	if t := fs.Transform; t != nil {
+		sink.Transform = &v1beta1.FilterTransform{
+			Operations: make([]v1beta1.TransformOperation, len(t.Operations)),
+		}
+		for i, op := range t.Operations {
+			sink.Transform.Operations[i] = v1beta1.TransformOperation{
+				Operation:  v1beta1.TransformOperationType(op.Operation),
+				Path:       op.Path,
+				To:         op.To,
+				Expression: convertTransformExpression(op.Expression, sink),
+				Template:   op.Template,
+			}
+		}
+	}
+}
+
+// convertTransformExpression returns the given transform expression with its
+// inline variables replaced with references to variables of the given spec,
+// which are declared as needed. Like the Filter expression, expressions
+// which can't be parsed, or which declare variables conflicting with existing
+// ones, are carried over verbatim.
+func convertTransformExpression(expression string, sink *v1beta1.FilterSpec) string {
+	expr, vars, err := cel.ParseExpression(expression)
+	if err != nil {
+		return expression
+	}
+
+	var newVars []v1beta1.Variable
+
+	for _, v := range vars {
+		declared := false
+		for _, dv := range sink.Variables {
+			if dv.Name != v.Name {
+				continue
+			}
+			if dv.Path != v.Path || string(dv.Type) != v.Type {
+				return expression
+			}
+			declared = true
+		}
+		if !declared {
+			newVars = append(newVars, v1beta1.Variable{
+				Name: v.Name,
+				Path: v.Path,
+				Type: v1beta1.VariableType(v.Type),
+			})
+		}
+	}
+
+	sink.Variables = append(sink.Variables, newVars...)
+	return expr
+}
+
+// restoreExpressions replaces the expressions and variables of the given
+// v1beta1 FilterSpec with the ones encoded in the value of a
+// v1beta1ExpressionsAnnotation, provided that they still inline to the
+// expressions of the given v1alpha1 FilterSpec.
+func restoreExpressions(val string, fs *FilterSpec, sink *v1beta1.FilterSpec) {
+	var exprs filterExpressions
+	if err := json.Unmarshal([]byte(val), &exprs); err != nil {
+		return
+	}
+
+	vars := (&v1beta1.FilterSpec{Variables: exprs.Variables}).CELVariables()
+
+	if cel.InlineVariables(exprs.Expression, vars) != fs.Expression {
+		return
+	}
+
+	var ops []TransformOperation
+	if fs.Transform != nil {
+		ops = fs.Transform.Operations
+	}
+	if len(exprs.Transform) != len(ops) {
+		return
+	}
+	for i, op := range ops {
+		if cel.InlineVariables(exprs.Transform[i], vars) != op.Expression {
+			return
+		}
+	}
+
+	sink.Expression = exprs.Expression
+	sink.Variables = exprs.Variables
+	for i, expr := range exprs.Transform {
+		sink.Transform.Operations[i].Expression = expr
+	}
+}
+
+// ConvertFrom implements apis.Convertible.
+// Converts a higher version of Filter to v1alpha1.
+func (f *Filter) ConvertFrom(ctx context.Context, from apis.Convertible) error {
+	switch source := from.(type) {
+	case *v1beta1.Filter:
+		f.ObjectMeta = *source.ObjectMeta.DeepCopy()
+		f.Spec.ConvertFrom(ctx, &source.Spec)
+		f.Status.ConvertFrom(ctx, &source.Status)
+		return preserveExpressions(ctx, &f.ObjectMeta, &f.Spec, &source.Spec)
+	default:
+		return fmt.Errorf("unknown version, got: %T", source)
+	}
+}
+
+// preserveExpressions records the expressions and variables of the given
+// v1beta1 FilterSpec inside a v1beta1ExpressionsAnnotation, unless converting
+// the given v1alpha1 FilterSpec back to v1beta1 already yields them.
+func preserveExpressions(ctx context.Context, meta *metav1.ObjectMeta, fs *FilterSpec, source *v1beta1.FilterSpec) error {
+	removeExpressionsAnnotation(meta)
+
+	exprs := filterExpressions{
+		Expression: source.Expression,
+		Variables:  source.Variables,
+	}
+	if t := source.Transform; t != nil {
+		exprs.Transform = make([]string, len(t.Operations))
+		for i, op := range t.Operations {
+			exprs.Transform[i] = op.Expression
+		}
+	}
+
+	converted := &v1beta1.FilterSpec{}
+	fs.ConvertTo(ctx, converted)
+
+	if converted.Expression == exprs.Expression && equalVariables(converted.Variables, exprs.Variables) {
+		lossless := true
+		for i, expr := range exprs.Transform {
+			if converted.Transform.Operations[i].Expression != expr {
+				lossless = false
+				break
+			}
+		}
+		if lossless {
+			return nil
+		}
+	}
+
+	val, err := json.Marshal(exprs)
+	if err != nil {
+		return fmt.Errorf("serializing expressions of v1beta1 Filter: %w", err)
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string, 1)
+	}
+	meta.Annotations[v1beta1ExpressionsAnnotation] = string(val)
+
+	return nil
+}
+
+// removeExpressionsAnnotation removes the v1beta1ExpressionsAnnotation from
+// the given object metadata, if set.
+func removeExpressionsAnnotation(meta *metav1.ObjectMeta) {
+	if _, ok := meta.Annotations[v1beta1ExpressionsAnnotation]; !ok {
+		return
+	}
+	delete(meta.Annotations, v1beta1ExpressionsAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+}
+
+// equalVariables returns whether the two given lists of variables are equal.
+func equalVariables(a, b []v1beta1.Variable) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ConvertFrom converts a higher version of FilterSpec to v1alpha1.
+func (fs *FilterSpec) ConvertFrom(ctx context.Context, source *v1beta1.FilterSpec) {
+	fs.Expression = cel.InlineVariables(source.Expression, source.CELVariables())
+	fs.Sink = source.Sink
+	fs.OnError = ErrorPolicy(source.OnError)
+	fs.ErrorSink = source.ErrorSink
+	fs.Delivery = source.Delivery
+	fs.AdapterOverrides = convertAdapterOverridesFrom(source.AdapterOverrides)
+
+	fs.Transform = nil
+	if t := source.Transform; t != nil {
+		fs.Transform = &FilterTransform{
+			Operations: make([]TransformOperation, len(t.Operations)),
+		}
+		for i, op := range t.Operations {
+			fs.Transform.Operations[i] = TransformOperation{
+				Operation:  TransformOperationType(op.Operation),
+				Path:       op.Path,
+				To:         op.To,
+				Expression: cel.InlineVariables(op.Expression, source.CELVariables()),
+				Template:   op.Template,
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1beta1"
+)
+
+func TestFilterConversion(t *testing.T) {
+	ctx := context.Background()
+
+	alpha := &Filter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "f"},
+		Spec: FilterSpec{
+			Expression: `$id.(int64) > 5 && ce.type == "var_id"`,
+			Sink:       &duckv1.Destination{Ref: &duckv1.KReference{Name: "sink"}},
+			OnError:    ErrorPolicyDrop,
+		},
+	}
+
+	beta := &v1beta1.Filter{}
+	require.NoError(t, alpha.ConvertTo(ctx, beta))
+
+	assert.Equal(t, alpha.ObjectMeta, beta.ObjectMeta)
+	assert.Equal(t, `var_id > 5 && ce.type == "var_id"`, beta.Spec.Expression)
+	assert.Equal(t, []v1beta1.Variable{{Name: "var_id", Path: "id", Type: v1beta1.VariableTypeInt64}}, beta.Spec.Variables)
+	assert.Equal(t, v1beta1.ErrorPolicyDrop, beta.Spec.OnError)
+	assert.Nil(t, beta.Validate(ctx))
+
+	got := &Filter{}
+	require.NoError(t, got.ConvertFrom(ctx, beta))
+	assert.Equal(t, alpha, got)
+
+	assert.Error(t, alpha.ConvertTo(ctx, &v1beta1.Splitter{}))
+	assert.Error(t, got.ConvertFrom(ctx, &v1beta1.Splitter{}))
+}
+
+func TestFilterConversionNamedVariables(t *testing.T) {
+	beta := &v1beta1.Filter{
+		Spec: v1beta1.FilterSpec{
+			Expression: `orderID == 5 && size(tags) > 0 && ce.orderID == "a"`,
+			Variables: []v1beta1.Variable{
+				{Name: "orderID", Path: "order.id", Type: v1beta1.VariableTypeInt64},
+				{Name: "tags", Path: "tags", Type: v1beta1.VariableTypeList},
+			},
+		},
+	}
+
+	alpha := &Filter{}
+	require.NoError(t, alpha.ConvertFrom(context.Background(), beta))
+	assert.Equal(t, `$order.id.(int64) == 5 && size($tags.(list)) > 0 && ce.orderID == "a"`, alpha.Spec.Expression)
+}
+
+func TestFilterConversionRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	newBeta := func() *v1beta1.Filter {
+		return &v1beta1.Filter{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        "f",
+				Annotations: map[string]string{"team": "a"},
+			},
+			Spec: v1beta1.FilterSpec{
+				Expression: `orderID == 5 && size(tags) > 0`,
+				Variables: []v1beta1.Variable{
+					{Name: "tags", Path: "tags", Type: v1beta1.VariableTypeList},
+					{Name: "orderID", Path: "order.id", Type: v1beta1.VariableTypeInt64},
+				},
+				Sink: &duckv1.Destination{Ref: &duckv1.KReference{Name: "sink"}},
+				Transform: &v1beta1.FilterTransform{
+					Operations: []v1beta1.TransformOperation{{
+						Operation:  v1beta1.TransformOperationSet,
+						Path:       "data.order",
+						Expression: `string(orderID)`,
+					}, {
+						Operation: v1beta1.TransformOperationDelete,
+						Path:      "data.tags",
+					}},
+				},
+			},
+		}
+	}
+
+	t.Run("unmodified", func(t *testing.T) {
+		beta := newBeta()
+
+		alpha := &Filter{}
+		require.NoError(t, alpha.ConvertFrom(ctx, beta))
+		assert.Contains(t, alpha.Annotations, v1beta1ExpressionsAnnotation)
+		assert.Equal(t, "a", alpha.Annotations["team"])
+		assert.NotContains(t, beta.Annotations, v1beta1ExpressionsAnnotation, "Source should not be mutated")
+
+		got := &v1beta1.Filter{}
+		require.NoError(t, alpha.ConvertTo(ctx, got))
+		assert.Equal(t, beta, got)
+		assert.Contains(t, alpha.Annotations, v1beta1ExpressionsAnnotation, "Source should not be mutated")
+	})
+
+	t.Run("modified in v1alpha1", func(t *testing.T) {
+		alpha := &Filter{}
+		require.NoError(t, alpha.ConvertFrom(ctx, newBeta()))
+
+		alpha.Spec.Expression = `$order.id.(int64) == 6`
+
+		got := &v1beta1.Filter{}
+		require.NoError(t, alpha.ConvertTo(ctx, got))
+		assert.NotContains(t, got.Annotations, v1beta1ExpressionsAnnotation)
+		assert.Equal(t, `var_order_id == 6`, got.Spec.Expression)
+		assert.Equal(t, []v1beta1.Variable{
+			{Name: "var_order_id", Path: "order.id", Type: v1beta1.VariableTypeInt64},
+		}, got.Spec.Variables)
+		assert.Equal(t, `string(var_order_id)`, got.Spec.Transform.Operations[0].Expression)
+	})
+
+	t.Run("canonical variables", func(t *testing.T) {
+		beta := newBeta()
+		beta.Spec.Expression = `var_order_id == 5`
+		beta.Spec.Variables = []v1beta1.Variable{
+			{Name: "var_order_id", Path: "order.id", Type: v1beta1.VariableTypeInt64},
+		}
+		beta.Spec.Transform = nil
+
+		alpha := &Filter{}
+		require.NoError(t, alpha.ConvertFrom(ctx, beta))
+		assert.NotContains(t, alpha.Annotations, v1beta1ExpressionsAnnotation,
+			"Annotation should only be set when the conversion is lossy")
+
+		got := &v1beta1.Filter{}
+		require.NoError(t, alpha.ConvertTo(ctx, got))
+		assert.Equal(t, beta, got)
+	})
+}
+
+func TestFilterConversionTransform(t *testing.T) {
+	ctx := context.Background()
+
+	alpha := &Filter{
+		Spec: FilterSpec{
+			Expression: `$id.(int64) > 5`,
+			Sink:       &duckv1.Destination{Ref: &duckv1.KReference{Name: "sink"}},
+			Transform: &FilterTransform{
+				Operations: []TransformOperation{{
+					Operation:  TransformOperationSet,
+					Path:       "data.label",
+					Expression: `$name.(string) + "-" + string($id.(int64))`,
+				}, {
+					Operation: TransformOperationRename,
+					Path:      "data.name",
+					To:        "ce.name",
+				}, {
+					Operation:  TransformOperationSet,
+					Path:       "data.conflict",
+					Expression: `$id.(string)`,
+				}},
+			},
+		},
+	}
+
+	beta := &v1beta1.Filter{}
+	require.NoError(t, alpha.ConvertTo(ctx, beta))
+
+	assert.Equal(t, []v1beta1.Variable{
+		{Name: "var_id", Path: "id", Type: v1beta1.VariableTypeInt64},
+		{Name: "var_name", Path: "name", Type: v1beta1.VariableTypeString},
+	}, beta.Spec.Variables)
+
+	ops := beta.Spec.Transform.Operations
+	require.Len(t, ops, 3)
+	assert.Equal(t, `var_name + "-" + string(var_id)`, ops[0].Expression)
+	assert.Equal(t, "ce.name", ops[1].To)
+	assert.Equal(t, `$id.(string)`, ops[2].Expression, "Conflicting expression should be carried over verbatim")
+
+	got := &Filter{}
+	require.NoError(t, got.ConvertFrom(ctx, beta))
+	assert.Equal(t, alpha, got)
+}
@@ -18,9 +18,33 @@ package v1alpha1
 
 import (
 	"context"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
 )
 
 // SetDefaults implements apis.Defaultable
 func (f *Filter) SetDefaults(ctx context.Context) {
-	// Nothing to default.
+	ctx = apis.WithinParent(ctx, f.ObjectMeta)
+
+	f.Spec.Expression = cel.NormalizeExpression(f.Spec.Expression)
+	if t := f.Spec.Transform; t != nil {
+		for i := range t.Operations {
+			t.Operations[i].Expression = cel.NormalizeExpression(t.Operations[i].Expression)
+		}
+	}
+
+	if f.Spec.OnError == "" {
+		f.Spec.OnError = ErrorPolicyPass
+	}
+
+	if f.Spec.Sink != nil {
+		f.Spec.Sink.SetDefaults(ctx)
+	}
+	if f.Spec.ErrorSink != nil {
+		f.Spec.ErrorSink.SetDefaults(ctx)
+	}
+
+	setDeliveryDefaults(ctx, f.Spec.Delivery)
 }
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestFilterSetDefaults(t *testing.T) {
+	f := &Filter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "f"},
+		Spec: FilterSpec{
+			Expression: "  $id.(int64)  ==\n  5\n",
+			Sink:       &duckv1.Destination{Ref: &duckv1.KReference{Name: "sink"}},
+			Delivery: &eventingduckv1.DeliverySpec{
+				BackoffDelay:   ptr.String("PT1S"),
+				DeadLetterSink: &duckv1.Destination{Ref: &duckv1.KReference{Name: "dls", Namespace: "other"}},
+			},
+			Transform: &FilterTransform{
+				Operations: []TransformOperation{{
+					Operation:  TransformOperationSet,
+					Path:       "data.id",
+					Expression: " $id.(int64)  +\n  1 ",
+				}},
+			},
+		},
+	}
+
+	f.SetDefaults(context.Background())
+
+	backoffPolicy := eventingduckv1.BackoffPolicyExponential
+	expect := FilterSpec{
+		Expression: "$id.(int64) == 5",
+		Sink:       &duckv1.Destination{Ref: &duckv1.KReference{Name: "sink", Namespace: "ns"}},
+		OnError:    ErrorPolicyPass,
+		Delivery: &eventingduckv1.DeliverySpec{
+			Retry:          ptr.Int32(0),
+			BackoffPolicy:  &backoffPolicy,
+			BackoffDelay:   ptr.String("PT1S"),
+			DeadLetterSink: &duckv1.Destination{Ref: &duckv1.KReference{Name: "dls", Namespace: "other"}},
+		},
+		Transform: &FilterTransform{
+			Operations: []TransformOperation{{
+				Operation:  TransformOperationSet,
+				Path:       "data.id",
+				Expression: "$id.(int64) + 1",
+			}},
+		},
+	}
+	assert.Equal(t, expect, f.Spec)
+}
@@ -18,8 +18,12 @@ package v1alpha1
 
 import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/eventtransform"
 )
 
 // GetGroupVersionKind implements kmeta.OwnerRefable
@@ -27,9 +31,13 @@ func (*Filter) GetGroupVersionKind() schema.GroupVersionKind {
 	return SchemeGroupVersion.WithKind("Filter")
 }
 
+// filterConditionSet is the set of status conditions of Filters, which
+// evaluate expressions.
+var filterConditionSet = NewRouterConditionSet(ConditionExpressionValid)
+
 // GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
 func (f *Filter) GetConditionSet() apis.ConditionSet {
-	return routerConditionSet
+	return filterConditionSet
 }
 
 // Supported event types
@@ -37,19 +45,29 @@ const (
 	FilterGenericEventType = "io.triggermesh.routing.filter"
 )
 
-// GetEventTypes implements Router.
+// GetEventTypes implements Reconcilable.
 func (*Filter) GetEventTypes() []string {
 	return []string{
 		FilterGenericEventType,
 	}
 }
 
-// GetSink implements Router.
+// GetSink implements Reconcilable.
 func (f *Filter) GetSink() *duckv1.Destination {
 	return f.Spec.Sink
 }
 
-// GetStatusManager implements Router.
+// GetDelivery implements deliverer.
+func (f *Filter) GetDelivery() *eventingduckv1.DeliverySpec {
+	return f.Spec.Delivery
+}
+
+// GetErrorSink implements errorRouter.
+func (f *Filter) GetErrorSink() *duckv1.Destination {
+	return f.Spec.ErrorSink
+}
+
+// GetStatusManager implements Reconcilable.
 func (f *Filter) GetStatusManager() *RouterStatusManager {
 	return &RouterStatusManager{
 		ConditionSet: f.GetConditionSet(),
@@ -57,7 +75,34 @@ func (f *Filter) GetStatusManager() *RouterStatusManager {
 	}
 }
 
+// GetAdapterOverrides implements adapterOverrider.
+func (f *Filter) GetAdapterOverrides() *AdapterOverrides {
+	return f.Spec.AdapterOverrides
+}
+
 // IsMultiTenant implements MultiTenant.
-func (*Filter) IsMultiTenant() bool {
-	return true
+func (f *Filter) IsMultiTenant() bool {
+	return !flow.HasDedicatedAdapter(f)
+}
+
+// TransformOperations returns the operations of the FilterTransform in the
+// format accepted by the eventtransform package.
+func (t *FilterTransform) TransformOperations() []eventtransform.Operation {
+	ops := make([]eventtransform.Operation, len(t.Operations))
+	for i, op := range t.Operations {
+		ops[i] = op.transformOperation()
+	}
+	return ops
+}
+
+// transformOperation returns the TransformOperation in the format accepted
+// by the eventtransform package.
+func (op *TransformOperation) transformOperation() eventtransform.Operation {
+	return eventtransform.Operation{
+		Op:         string(op.Operation),
+		Path:       op.Path,
+		To:         op.To,
+		Expression: op.Expression,
+		Template:   op.Template,
+	}
 }
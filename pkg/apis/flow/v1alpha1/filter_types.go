@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
@@ -44,15 +45,19 @@ type Filter struct {
 }
 
 var (
-	// Check that Filter can be validated and defaulted.
+	// Check that Filter can be validated, defaulted and converted.
 	_ apis.Validatable   = (*Filter)(nil)
 	_ apis.Defaultable   = (*Filter)(nil)
+	_ apis.Convertible   = (*Filter)(nil)
 	_ kmeta.OwnerRefable = (*Filter)(nil)
 	// Check that the type conforms to the duck Knative Resource shape.
-	_ duckv1.KRShaped = (*Filter)(nil)
-	_ multiTenant     = (*Filter)(nil)
+	_ duckv1.KRShaped  = (*Filter)(nil)
+	_ multiTenant      = (*Filter)(nil)
+	_ adapterOverrider = (*Filter)(nil)
+	_ errorRouter      = (*Filter)(nil)
+	_ deliverer        = (*Filter)(nil)
 
-	_ Router = (*Filter)(nil)
+	_ Reconcilable = (*Filter)(nil)
 )
 
 // FilterSpec contains CEL expression string and the destination sink
@@ -61,8 +66,95 @@ type FilterSpec struct {
 
 	// Sink is a reference to an object that will resolve to a domain name to use as the sink.
 	Sink *duckv1.Destination `json:"sink"`
+
+	// OnError determines how events are handled when the evaluation of the
+	// expression fails. Defaults to "pass".
+	// +optional
+	OnError ErrorPolicy `json:"onError,omitempty"`
+
+	// ErrorSink receives events for which the evaluation of the expression
+	// failed, when OnError is set to "route".
+	// +optional
+	ErrorSink *duckv1.Destination `json:"errorSink,omitempty"`
+
+	// Delivery contains the retry and dead letter sink configuration of
+	// the events dispatched by the Filter.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// Transform describes mutations applied to events which pass the
+	// Filter, before they are forwarded to the sink.
+	// +optional
+	Transform *FilterTransform `json:"transform,omitempty"`
+
+	// AdapterOverrides are applied to the Pods of the Filter's adapter. They
+	// can only be set if the Filter uses a dedicated adapter.
+	// +optional
+	AdapterOverrides *AdapterOverrides `json:"adapterOverrides,omitempty"`
 }
 
+// FilterTransform is a sequence of mutations applied to the context
+// attributes and the JSON payload of events.
+type FilterTransform struct {
+	// Operations are applied in order. Expressions and templates are
+	// evaluated against the event as it was received.
+	Operations []TransformOperation `json:"operations"`
+}
+
+// TransformOperation describes a single mutation of an event.
+type TransformOperation struct {
+	// Operation is the kind of mutation.
+	Operation TransformOperationType `json:"operation"`
+
+	// Path of the mutated field. Paths starting with "ce." refer to context
+	// attributes and extensions, e.g. "ce.type", and paths starting with
+	// "data" refer to dot-separated keys of the JSON payload, e.g.
+	// "data.user.name".
+	Path string `json:"path"`
+
+	// To is the destination path of a renamed field.
+	// +optional
+	To string `json:"to,omitempty"`
+
+	// Expression is a CEL expression, in the same syntax as the Filter
+	// expression, which computes the value of a set field.
+	// +optional
+	Expression string `json:"expression,omitempty"`
+
+	// Template is a Go template which renders the value of a set field as a
+	// string, e.g. "{{ .ce.type }}/{{ .data.user.name }}".
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// TransformOperationType is the kind of mutation performed by a
+// TransformOperation.
+type TransformOperationType string
+
+// Supported transform operations
+const (
+	// TransformOperationSet sets the value of a field.
+	TransformOperationSet TransformOperationType = "set"
+	// TransformOperationDelete removes a field.
+	TransformOperationDelete TransformOperationType = "delete"
+	// TransformOperationRename moves the value of a field to another field.
+	TransformOperationRename TransformOperationType = "rename"
+)
+
+// ErrorPolicy is the strategy used to handle events for which the evaluation
+// of an expression failed.
+type ErrorPolicy string
+
+// Supported error policies
+const (
+	// ErrorPolicyPass forwards events to the sink as if they passed the filter.
+	ErrorPolicyPass ErrorPolicy = "pass"
+	// ErrorPolicyDrop discards events.
+	ErrorPolicyDrop ErrorPolicy = "drop"
+	// ErrorPolicyRoute forwards events to the error sink.
+	ErrorPolicyRoute ErrorPolicy = "route"
+)
+
 // FilterList is a list of Filter resources
 //
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -78,7 +170,7 @@ func (f *Filter) GetStatus() *duckv1.Status {
 	return &f.Status.Status
 }
 
-// AsRouter implements Router.
+// AsRouter implements Reconcilable.
 func (f *Filter) AsRouter() string {
 	return "filter/" + f.Name
 }
@@ -22,12 +22,15 @@ import (
 
 	"knative.dev/pkg/apis"
 
+	"github.com/triggermesh/routing/pkg/apis/flow"
 	"github.com/triggermesh/routing/pkg/eventfilter/cel"
 )
 
 // Validate implements apis.Validatable
 func (f *Filter) Validate(ctx context.Context) *apis.FieldError {
-	return f.Spec.Validate(ctx).ViaField("spec")
+	return flow.ValidateAdapterIsolation(f).Also(
+		flow.ValidateAdapterOverrides(f, f.Spec.AdapterOverrides != nil),
+		f.Spec.Validate(ctx).ViaField("spec"))
 }
 
 // Validate implements apis.Validatable
@@ -41,5 +44,39 @@ func (fs *FilterSpec) Validate(ctx context.Context) *apis.FieldError {
 	if _, err := cel.CompileExpression(fs.Expression); err != nil {
 		return apis.ErrInvalidValue(fmt.Sprintf("Cannot compile expression: %v", err), "Expression")
 	}
-	return nil
+
+	switch fs.OnError {
+	case "", ErrorPolicyPass, ErrorPolicyDrop:
+	case ErrorPolicyRoute:
+		if fs.ErrorSink == nil {
+			return apis.ErrMissingField("ErrorSink")
+		}
+	default:
+		return apis.ErrInvalidValue(fs.OnError, "OnError")
+	}
+	if err := fs.Transform.Validate(ctx).ViaField("transform"); err != nil {
+		return err
+	}
+	if err := fs.AdapterOverrides.Validate(ctx).ViaField("adapterOverrides"); err != nil {
+		return err
+	}
+	return fs.Delivery.Validate(ctx).ViaField("delivery")
+}
+
+// Validate implements apis.Validatable
+func (t *FilterTransform) Validate(ctx context.Context) *apis.FieldError {
+	if t == nil {
+		return nil
+	}
+	if len(t.Operations) == 0 {
+		return apis.ErrMissingField("operations")
+	}
+
+	var errs *apis.FieldError
+	for i, op := range t.Operations {
+		if err := op.transformOperation().Validate(); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), apis.CurrentField).ViaFieldIndex("operations", i))
+		}
+	}
+	return errs
 }
@@ -22,12 +22,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
 )
 
-// Router is implemented by all Routing types.
-type Router interface {
+// Reconcilable is implemented by all Routing types.
+type Reconcilable interface {
 	metav1.Object
 	runtime.Object
 	// OwnerRefable is used to construct a generic reconciler for each
@@ -49,15 +50,15 @@ type Router interface {
 
 // WithRouter returns a copy of the parent context in which the value
 // associated with the router key is the given router.
-func WithRouter(ctx context.Context, r Router) context.Context {
+func WithRouter(ctx context.Context, r Reconcilable) context.Context {
 	return context.WithValue(ctx, routerKey{}, r)
 }
 
 type routerKey struct{}
 
 // RouterFromContext returns the router stored in the context.
-func RouterFromContext(ctx context.Context) Router {
-	if s, ok := ctx.Value(routerKey{}).(Router); ok {
+func RouterFromContext(ctx context.Context) Reconcilable {
+	if s, ok := ctx.Value(routerKey{}).(Reconcilable); ok {
 		return s
 	}
 	return nil
@@ -69,7 +70,92 @@ type multiTenant interface {
 }
 
 // IsMultiTenant returns whether the given router type is multi-tenant.
-func IsMultiTenant(r Router) bool {
+func IsMultiTenant(r Reconcilable) bool {
 	mt, ok := r.(multiTenant)
 	return ok && mt.IsMultiTenant()
 }
+
+// multiRoute is implemented by router types which dispatch events to more
+// than one sink.
+type multiRoute interface {
+	GetRouteSinks() []*duckv1.Destination
+}
+
+// errorRouter is implemented by router types which can dispatch events to a
+// dedicated sink when they fail to be processed.
+type errorRouter interface {
+	GetErrorSink() *duckv1.Destination
+}
+
+// deliverer is implemented by router types which support the configuration
+// of retries and dead letter sinks for the events they dispatch.
+type deliverer interface {
+	GetDelivery() *eventingduckv1.DeliverySpec
+}
+
+// Delivery returns the delivery spec of the given router, or nil if the
+// router type doesn't support delivery options or has none.
+func Delivery(r Reconcilable) *eventingduckv1.DeliverySpec {
+	if d, ok := r.(deliverer); ok {
+		return d.GetDelivery()
+	}
+	return nil
+}
+
+// DeadLetterSink returns the dead letter sink of the given router, or nil if
+// the router has none.
+func DeadLetterSink(r Reconcilable) *duckv1.Destination {
+	if d := Delivery(r); d != nil {
+		return d.DeadLetterSink
+	}
+	return nil
+}
+
+// ErrorSink returns the error sink of the given router, or nil if the router
+// type doesn't support error sinks or has none.
+func ErrorSink(r Reconcilable) *duckv1.Destination {
+	if er, ok := r.(errorRouter); ok {
+		return er.GetErrorSink()
+	}
+	return nil
+}
+
+// RouteSinks returns the sinks of all routes of the given router, or nil if
+// the router type dispatches events to a single sink.
+func RouteSinks(r Reconcilable) []*duckv1.Destination {
+	if mr, ok := r.(multiRoute); ok {
+		return mr.GetRouteSinks()
+	}
+	return nil
+}
+
+// adapterOverrider is implemented by router types which support overriding
+// properties of their adapter.
+type adapterOverrider interface {
+	GetAdapterOverrides() *AdapterOverrides
+}
+
+// AdapterOverridesOf returns the adapter overrides of the given router, or
+// nil if the router type doesn't support adapter overrides or has none.
+func AdapterOverridesOf(r Reconcilable) *AdapterOverrides {
+	if ao, ok := r.(adapterOverrider); ok {
+		return ao.GetAdapterOverrides()
+	}
+	return nil
+}
+
+// eventAttributer is implemented by router types which produce events with
+// context attributes of their own, rather than the generic ones returned by
+// GetEventTypes and AsRouter.
+type eventAttributer interface {
+	GetCloudEventAttributes() []duckv1.CloudEventAttributes
+}
+
+// CloudEventAttributes returns the context attributes of the events produced
+// by the given router, or nil if the router type doesn't define any.
+func CloudEventAttributes(r Reconcilable) []duckv1.CloudEventAttributes {
+	if ea, ok := r.(eventAttributer); ok {
+		return ea.GetCloudEventAttributes()
+	}
+	return nil
+}
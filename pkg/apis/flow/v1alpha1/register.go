@@ -47,6 +47,9 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Filter{}, &FilterList{},
 		&Splitter{}, &SplitterList{},
+		&Router{}, &RouterList{},
+		&Aggregator{}, &AggregatorList{},
+		&Synchronizer{}, &SynchronizerList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+// GetGroupVersionKind implements kmeta.OwnerRefable
+func (*Router) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Router")
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (r *Router) GetConditionSet() apis.ConditionSet {
+	return routerConditionSet
+}
+
+// GetAdapterOverrides implements adapterOverrider.
+func (r *Router) GetAdapterOverrides() *AdapterOverrides {
+	return r.Spec.AdapterOverrides
+}
+
+// IsMultiTenant implements MultiTenant.
+func (r *Router) IsMultiTenant() bool {
+	return !flow.HasDedicatedAdapter(r)
+}
+
+// Supported event types
+const (
+	RouterGenericEventType = "io.triggermesh.routing.router"
+)
+
+// GetEventTypes implements Reconcilable.
+func (*Router) GetEventTypes() []string {
+	return []string{
+		RouterGenericEventType,
+	}
+}
+
+// GetSink implements Reconcilable.
+//
+// The sink of a Router is its default sink, which is optional.
+func (r *Router) GetSink() *duckv1.Destination {
+	return r.Spec.DefaultSink
+}
+
+// GetRouteSinks implements multiRoute.
+func (r *Router) GetRouteSinks() []*duckv1.Destination {
+	sinks := make([]*duckv1.Destination, len(r.Spec.Routes))
+	for i := range r.Spec.Routes {
+		sinks[i] = r.Spec.Routes[i].Sink
+	}
+	return sinks
+}
+
+// GetStatusManager implements Reconcilable.
+func (r *Router) GetStatusManager() *RouterStatusManager {
+	return &RouterStatusManager{
+		ConditionSet: r.GetConditionSet(),
+		RouterStatus: &r.Status,
+	}
+}
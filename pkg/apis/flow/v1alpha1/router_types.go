@@ -0,0 +1,118 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Router is an addressable object that dispatches incoming events to
+// different sinks according to an ordered list of Common Language Expressions
+type Router struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the Router (from the client).
+	// +optional
+	Spec RouterSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the Router (from the controller).
+	// +optional
+	Status RouterStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that Router can be validated and defaulted.
+	_ apis.Validatable   = (*Router)(nil)
+	_ apis.Defaultable   = (*Router)(nil)
+	_ kmeta.OwnerRefable = (*Router)(nil)
+	// Check that the type conforms to the duck Knative Resource shape.
+	_ duckv1.KRShaped  = (*Router)(nil)
+	_ multiTenant      = (*Router)(nil)
+	_ adapterOverrider = (*Router)(nil)
+	_ multiRoute       = (*Router)(nil)
+
+	_ Reconcilable = (*Router)(nil)
+)
+
+// RouterSpec contains the ordered list of routes and the routing mode
+type RouterSpec struct {
+	// Routes is the ordered list of routes events are evaluated against.
+	Routes []Route `json:"routes"`
+
+	// Mode determines whether events are dispatched to the first matching
+	// route only, or to all matching routes.
+	// +optional
+	Mode RoutingMode `json:"mode,omitempty"`
+
+	// DefaultSink receives events which do not match any route.
+	// +optional
+	DefaultSink *duckv1.Destination `json:"defaultSink,omitempty"`
+
+	// AdapterOverrides are applied to the Pods of the Router's adapter. They
+	// can only be set if the Router uses a dedicated adapter.
+	// +optional
+	AdapterOverrides *AdapterOverrides `json:"adapterOverrides,omitempty"`
+}
+
+// Route contains a CEL expression string and the destination sink of the
+// events matching it
+type Route struct {
+	Expression string `json:"expression"`
+
+	// Sink is a reference to an object that will resolve to a domain name to use as the sink.
+	Sink *duckv1.Destination `json:"sink"`
+}
+
+// RoutingMode is the strategy used by a Router to select routes.
+type RoutingMode string
+
+// Supported routing modes
+const (
+	// RoutingModeFirstMatch dispatches events to the first matching route.
+	RoutingModeFirstMatch RoutingMode = "firstMatch"
+	// RoutingModeAllMatches dispatches events to all matching routes.
+	RoutingModeAllMatches RoutingMode = "allMatches"
+)
+
+// RouterList is a list of Router resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RouterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Router `json:"items"`
+}
+
+// GetStatus retrieves the status of the resource. Implements the KRShaped interface.
+func (r *Router) GetStatus() *duckv1.Status {
+	return &r.Status.Status
+}
+
+// AsRouter implements Reconcilable.
+func (r *Router) AsRouter() string {
+	return "router/" + r.Name
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// Validate implements apis.Validatable
+func (r *Router) Validate(ctx context.Context) *apis.FieldError {
+	return flow.ValidateAdapterIsolation(r).Also(
+		flow.ValidateAdapterOverrides(r, r.Spec.AdapterOverrides != nil),
+		r.Spec.Validate(ctx).ViaField("spec"))
+}
+
+// Validate implements apis.Validatable
+func (rs *RouterSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if len(rs.Routes) == 0 {
+		errs = errs.Also(apis.ErrMissingField("routes"))
+	}
+	for i := range rs.Routes {
+		errs = errs.Also(rs.Routes[i].Validate(ctx).ViaFieldIndex("routes", i))
+	}
+
+	switch rs.Mode {
+	case "", RoutingModeFirstMatch, RoutingModeAllMatches:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(rs.Mode, "mode"))
+	}
+
+	return errs.Also(rs.AdapterOverrides.Validate(ctx).ViaField("adapterOverrides"))
+}
+
+// Validate implements apis.Validatable
+func (r *Route) Validate(ctx context.Context) *apis.FieldError {
+	if r.Expression == "" {
+		return apis.ErrMissingField("expression")
+	}
+	if r.Sink == nil {
+		return apis.ErrMissingField("sink")
+	}
+	if _, err := cel.CompileExpression(r.Expression); err != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("Cannot compile expression: %v", err), "expression")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestRouterValidate(t *testing.T) {
+	validSpec := func() RouterSpec {
+		return RouterSpec{
+			Routes: []Route{{
+				Expression: `ce.type == "a"`,
+				Sink:       &duckv1.Destination{},
+			}, {
+				Expression: `$id.(int64) > 5`,
+				Sink:       &duckv1.Destination{},
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(*RouterSpec)
+		errs   []string
+	}{{
+		name:   "valid",
+		mutate: func(*RouterSpec) {},
+	}, {
+		name:   "all matches",
+		mutate: func(s *RouterSpec) { s.Mode = RoutingModeAllMatches },
+	}, {
+		name:   "no route",
+		mutate: func(s *RouterSpec) { s.Routes = nil },
+		errs:   []string{"spec.routes"},
+	}, {
+		name: "invalid routes",
+		mutate: func(s *RouterSpec) {
+			s.Routes[0].Expression = ""
+			s.Routes[1].Sink = nil
+		},
+		errs: []string{"spec.routes[0].expression", "spec.routes[1].sink"},
+	}, {
+		name:   "invalid expression",
+		mutate: func(s *RouterSpec) { s.Routes[1].Expression = `$id.(int64) >` },
+		errs:   []string{"spec.routes[1].expression"},
+	}, {
+		name:   "unknown mode",
+		mutate: func(s *RouterSpec) { s.Mode = "random" },
+		errs:   []string{"spec.mode"},
+	}, {
+		name:   "overrides on shared adapter",
+		mutate: func(s *RouterSpec) { s.AdapterOverrides = &AdapterOverrides{PriorityClassName: "high"} },
+		errs:   []string{"spec.adapterOverrides"},
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Router{Spec: validSpec()}
+			tc.mutate(&r.Spec)
+
+			err := r.Validate(context.Background())
+			if len(tc.errs) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			if assert.NotNil(t, err) {
+				for _, p := range tc.errs {
+					assert.Contains(t, err.Error(), p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1beta1"
+)
+
+// ConvertTo implements apis.Convertible.
+// Converts the v1alpha1 Splitter to a higher version.
+func (s *Splitter) ConvertTo(ctx context.Context, to apis.Convertible) error {
+	switch sink := to.(type) {
+	case *v1beta1.Splitter:
+		sink.ObjectMeta = s.ObjectMeta
+		s.Spec.ConvertTo(ctx, &sink.Spec)
+		s.Status.ConvertTo(ctx, &sink.Status)
+		return nil
+	default:
+		return fmt.Errorf("unknown version, got: %T", sink)
+	}
+}
+
+// ConvertTo converts the SplitterSpec to a higher version.
+func (ss *SplitterSpec) ConvertTo(ctx context.Context, sink *v1beta1.SplitterSpec) {
+	sink.Input = v1beta1.SplitterInput{
+		Path:       ss.Path,
+		PathSyntax: v1beta1.PathSyntax(ss.PathSyntax),
+	}
+
+	sink.Output = v1beta1.SplitterOutput{
+		CEContext: v1beta1.CloudEventContext{
+			Type:       ss.CEContext.Type,
+			Source:     ss.CEContext.Source,
+			Extensions: ss.CEContext.Extensions,
+		},
+	}
+	if ss.Envelope != nil {
+		sink.Output.Envelope = &v1beta1.SplitterEnvelope{
+			Wrap:        ss.Envelope.Wrap,
+			ParentPaths: ss.Envelope.ParentPaths,
+		}
+	}
+
+	sink.Dispatch = v1beta1.SplitterDispatch{
+		Parallelism: ss.Parallelism,
+		Ordered:     ss.Ordered,
+	}
+
+	sink.Sink = ss.Sink
+	sink.Delivery = ss.Delivery
+	sink.AdapterOverrides = convertAdapterOverridesTo(ss.AdapterOverrides)
+}
+
+// ConvertFrom implements apis.Convertible.
+// Converts a higher version of Splitter to v1alpha1.
+func (s *Splitter) ConvertFrom(ctx context.Context, from apis.Convertible) error {
+	switch source := from.(type) {
+	case *v1beta1.Splitter:
+		s.ObjectMeta = source.ObjectMeta
+		s.Spec.ConvertFrom(ctx, &source.Spec)
+		s.Status.ConvertFrom(ctx, &source.Status)
+		return nil
+	default:
+		return fmt.Errorf("unknown version, got: %T", source)
+	}
+}
+
+// ConvertFrom converts a higher version of SplitterSpec to v1alpha1.
+func (ss *SplitterSpec) ConvertFrom(ctx context.Context, source *v1beta1.SplitterSpec) {
+	ss.Path = source.Input.Path
+	ss.PathSyntax = PathSyntax(source.Input.PathSyntax)
+
+	ss.CEContext = CloudEventContext{
+		Type:       source.Output.CEContext.Type,
+		Source:     source.Output.CEContext.Source,
+		Extensions: source.Output.CEContext.Extensions,
+	}
+	ss.Envelope = nil
+	if env := source.Output.Envelope; env != nil {
+		ss.Envelope = &SplitterEnvelope{
+			Wrap:        env.Wrap,
+			ParentPaths: env.ParentPaths,
+		}
+	}
+
+	ss.Parallelism = source.Dispatch.Parallelism
+	ss.Ordered = source.Dispatch.Ordered
+
+	ss.Sink = source.Sink
+	ss.Delivery = source.Delivery
+	ss.AdapterOverrides = convertAdapterOverridesFrom(source.AdapterOverrides)
+}
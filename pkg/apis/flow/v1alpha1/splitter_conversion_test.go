@@ -0,0 +1,80 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1beta1"
+)
+
+func TestSplitterConversion(t *testing.T) {
+	ctx := context.Background()
+
+	alpha := &Splitter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "s",
+			Annotations: map[string]string{flow.AdapterIsolationAnnotation: flow.AdapterIsolationDedicated},
+		},
+		Spec: SplitterSpec{
+			Path:       "/items",
+			PathSyntax: PathSyntaxJSONPointer,
+			CEContext: CloudEventContext{
+				Type:       "io.triggermesh.item",
+				Source:     "items",
+				Extensions: map[string]string{"team": "a"},
+			},
+			Envelope:    &SplitterEnvelope{ParentPaths: []string{"id"}},
+			Parallelism: ptr.Int32(4),
+			Sink:        &duckv1.Destination{URI: apis.HTTP("sink")},
+			AdapterOverrides: &AdapterOverrides{
+				Scaling:           &AdapterScaling{MinScale: ptr.Int32(1), MaxScale: ptr.Int32(5)},
+				NodeSelector:      map[string]string{"pool": "routing"},
+				PriorityClassName: "high",
+			},
+		},
+		Status: RouterStatus{
+			ErrorSinkURI: apis.HTTP("errors"),
+		},
+	}
+
+	beta := &v1beta1.Splitter{}
+	require.NoError(t, alpha.ConvertTo(ctx, beta))
+
+	assert.Equal(t, v1beta1.SplitterInput{Path: "/items", PathSyntax: v1beta1.PathSyntaxJSONPointer}, beta.Spec.Input)
+	assert.Equal(t, "io.triggermesh.item", beta.Spec.Output.CEContext.Type)
+	assert.Equal(t, []string{"id"}, beta.Spec.Output.Envelope.ParentPaths)
+	assert.Equal(t, ptr.Int32(4), beta.Spec.Dispatch.Parallelism)
+	assert.Equal(t, ptr.Int32(5), beta.Spec.AdapterOverrides.Scaling.MaxScale)
+	assert.Equal(t, apis.HTTP("errors"), beta.Status.ErrorSinkURI)
+	assert.Nil(t, beta.Validate(ctx))
+
+	got := &Splitter{}
+	require.NoError(t, got.ConvertFrom(ctx, beta))
+	assert.Equal(t, alpha, got)
+}
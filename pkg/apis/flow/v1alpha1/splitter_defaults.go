@@ -18,9 +18,52 @@ package v1alpha1
 
 import (
 	"context"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
 )
 
+// defaultParallelism is the default number of child events dispatched
+// concurrently by a Splitter.
+const defaultParallelism int32 = 1
+
 // SetDefaults implements apis.Defaultable
 func (s *Splitter) SetDefaults(ctx context.Context) {
-	// Nothing to default.
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
+
+	if s.Spec.PathSyntax == "" {
+		s.Spec.PathSyntax = PathSyntaxGJSON
+	}
+	if s.Spec.Path == "" {
+		s.Spec.Path = rootPath(s.Spec.PathSyntax)
+	}
+	if s.Spec.Parallelism == nil {
+		s.Spec.Parallelism = ptr.Int32(defaultParallelism)
+	}
+
+	if s.Spec.CEContext.Type == "" {
+		s.Spec.CEContext.Type = SplitterGenericEventType
+	}
+	if s.Spec.CEContext.Source == "" {
+		s.Spec.CEContext.Source = s.AsRouter()
+	}
+
+	if s.Spec.Sink != nil {
+		s.Spec.Sink.SetDefaults(ctx)
+	}
+
+	setDeliveryDefaults(ctx, s.Spec.Delivery)
+}
+
+// rootPath returns the expression which refers to the root of a JSON
+// document in the given path syntax.
+func rootPath(syntax PathSyntax) string {
+	switch syntax {
+	case PathSyntaxGJSON:
+		return "@this"
+	case PathSyntaxJSONPath:
+		return "$"
+	}
+	// an empty JSON Pointer refers to the whole document
+	return ""
 }
@@ -18,8 +18,11 @@ package v1alpha1
 
 import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
 )
 
 // GetGroupVersionKind implements kmeta.OwnerRefable
@@ -32,9 +35,14 @@ func (s *Splitter) GetConditionSet() apis.ConditionSet {
 	return routerConditionSet
 }
 
+// GetAdapterOverrides implements adapterOverrider.
+func (s *Splitter) GetAdapterOverrides() *AdapterOverrides {
+	return s.Spec.AdapterOverrides
+}
+
 // IsMultiTenant implements MultiTenant.
-func (*Splitter) IsMultiTenant() bool {
-	return true
+func (s *Splitter) IsMultiTenant() bool {
+	return !flow.HasDedicatedAdapter(s)
 }
 
 // Supported event types
@@ -42,19 +50,48 @@ const (
 	SplitterGenericEventType = "io.triggermesh.routing.splitter"
 )
 
-// GetEventTypes implements Router.
+// GetEventTypes implements Reconcilable.
 func (*Splitter) GetEventTypes() []string {
 	return []string{
 		SplitterGenericEventType,
 	}
 }
 
-// GetSink implements Router.
+// Extensions set on the events produced by the Splitter, which correlate
+// them with the event they were split from.
+const (
+	// SplitterExtensionParentID is the ID of the event the child was split from.
+	SplitterExtensionParentID = "splitparentid"
+	// SplitterExtensionIndex is the 0-based position of the child.
+	SplitterExtensionIndex = "splitindex"
+	// SplitterExtensionTotal is the number of children split from the parent.
+	SplitterExtensionTotal = "splittotal"
+	// SplitterExtensionLast is true for the last child of the parent.
+	SplitterExtensionLast = "splitlast"
+)
+
+// GetCloudEventAttributes returns the context attributes of the events
+// produced by the Splitter. The duck type has no room for extensions, so the
+// correlation extensions set on every event are documented by the
+// SplitterExtension constants instead.
+func (s *Splitter) GetCloudEventAttributes() []duckv1.CloudEventAttributes {
+	return []duckv1.CloudEventAttributes{{
+		Type:   s.Spec.CEContext.Type,
+		Source: s.Spec.CEContext.Source,
+	}}
+}
+
+// GetSink implements Reconcilable.
 func (s *Splitter) GetSink() *duckv1.Destination {
 	return s.Spec.Sink
 }
 
-// GetStatusManager implements Router.
+// GetDelivery implements deliverer.
+func (s *Splitter) GetDelivery() *eventingduckv1.DeliverySpec {
+	return s.Spec.Delivery
+}
+
+// GetStatusManager implements Reconcilable.
 func (s *Splitter) GetStatusManager() *RouterStatusManager {
 	return &RouterStatusManager{
 		ConditionSet: s.GetConditionSet(),
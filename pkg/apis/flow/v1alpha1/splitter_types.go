@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
@@ -44,24 +45,79 @@ type Splitter struct {
 }
 
 var (
-	// Check that Splitter can be validated and defaulted.
+	// Check that Splitter can be validated, defaulted and converted.
 	_ apis.Validatable   = (*Splitter)(nil)
 	_ apis.Defaultable   = (*Splitter)(nil)
+	_ apis.Convertible   = (*Splitter)(nil)
 	_ kmeta.OwnerRefable = (*Splitter)(nil)
 	// Check that the type conforms to the duck Knative Resource shape.
-	_ duckv1.KRShaped = (*Splitter)(nil)
-	_ multiTenant     = (*Splitter)(nil)
+	_ duckv1.KRShaped  = (*Splitter)(nil)
+	_ multiTenant      = (*Splitter)(nil)
+	_ adapterOverrider = (*Splitter)(nil)
+	_ eventAttributer  = (*Splitter)(nil)
+	_ deliverer        = (*Splitter)(nil)
 
-	_ Router = (*Splitter)(nil)
+	_ Reconcilable = (*Splitter)(nil)
 )
 
 // SplitterSpec holds the desired state of the Splitter
 type SplitterSpec struct {
-	Path      string              `json:"path"`
-	CEContext CloudEventContext   `json:"ceContext"`
-	Sink      *duckv1.Destination `json:"sink"`
+	Path string `json:"path"`
+	// PathSyntax is the syntax used to interpret Path. Defaults to gjson.
+	// +optional
+	PathSyntax PathSyntax        `json:"pathSyntax,omitempty"`
+	CEContext  CloudEventContext `json:"ceContext"`
+	// Envelope carries data from the parent event over to each child event.
+	// +optional
+	Envelope *SplitterEnvelope `json:"envelope,omitempty"`
+	// Parallelism is the maximum number of child events dispatched
+	// concurrently. Defaults to 1, and must be 1 when Ordered is set.
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+	// Ordered dispatches child events one at a time, in their order inside
+	// the parent event, and stops at the first failed delivery.
+	// +optional
+	Ordered bool                `json:"ordered,omitempty"`
+	Sink    *duckv1.Destination `json:"sink"`
+
+	// Delivery contains the retry and dead letter sink configuration of
+	// the events dispatched by the Splitter.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// AdapterOverrides are applied to the Pods of the Splitter's adapter. They
+	// can only be set if the Splitter uses a dedicated adapter.
+	// +optional
+	AdapterOverrides *AdapterOverrides `json:"adapterOverrides,omitempty"`
 }
 
+// SplitterEnvelope describes how data from the parent event is carried over
+// to each child event.
+type SplitterEnvelope struct {
+	// Wrap wraps each split element as
+	// {"parent": <parent>, "item": <element>, "index": <i>, "total": <n>}.
+	// +optional
+	Wrap bool `json:"wrap,omitempty"`
+	// ParentPaths are GJSON paths of the parent fields to copy into each
+	// child event. When Wrap is enabled and no path is set, the whole parent
+	// payload is copied.
+	// +optional
+	ParentPaths []string `json:"parentPaths,omitempty"`
+}
+
+// PathSyntax is the syntax of the path of the element to split.
+type PathSyntax string
+
+// Supported path syntaxes.
+const (
+	// PathSyntaxGJSON interprets the path as a GJSON path.
+	PathSyntaxGJSON PathSyntax = "gjson"
+	// PathSyntaxJSONPointer interprets the path as a RFC 6901 JSON Pointer.
+	PathSyntaxJSONPointer PathSyntax = "jsonpointer"
+	// PathSyntaxJSONPath interprets the path as a JSONPath expression.
+	PathSyntaxJSONPath PathSyntax = "jsonpath"
+)
+
 type CloudEventContext struct {
 	Type       string            `json:"type"`
 	Source     string            `json:"source"`
@@ -83,7 +139,7 @@ func (s *Splitter) GetStatus() *duckv1.Status {
 	return &s.Status.Status
 }
 
-// AsRouter implements Router.
+// AsRouter implements Reconcilable.
 func (s *Splitter) AsRouter() string {
 	return "splitter/" + s.Name
 }
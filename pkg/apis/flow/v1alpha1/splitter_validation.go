@@ -18,16 +18,149 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
 
 	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/eventsplitter"
 )
 
+// maxExtensionNameLength is the maximum length of CloudEvent extension names
+// recommended by the CloudEvents specification.
+const maxExtensionNameLength = 20
+
+// reservedAttributeNames are the names of the CloudEvent context attributes
+// defined by the CloudEvents specification, which can not be used as
+// extension names.
+var reservedAttributeNames = map[string]struct{}{
+	"data":            {},
+	"data_base64":     {},
+	"datacontenttype": {},
+	"dataschema":      {},
+	"id":              {},
+	"source":          {},
+	"specversion":     {},
+	"subject":         {},
+	"time":            {},
+	"type":            {},
+}
+
 // Validate implements apis.Validatable
 func (s *Splitter) Validate(ctx context.Context) *apis.FieldError {
-	return s.Spec.Validate(ctx).ViaField("spec")
+	return flow.ValidateAdapterIsolation(s).Also(
+		flow.ValidateAdapterOverrides(s, s.Spec.AdapterOverrides != nil),
+		s.Spec.Validate(ctx).ViaField("spec"))
 }
 
 // Validate implements apis.Validatable
 func (ss *SplitterSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	switch ss.PathSyntax {
+	case "", PathSyntaxGJSON, PathSyntaxJSONPointer, PathSyntaxJSONPath:
+		// an empty JSON Pointer refers to the whole document, whereas
+		// other syntaxes have a dedicated expression for it
+		if ss.Path == "" && ss.PathSyntax != PathSyntaxJSONPointer {
+			errs = errs.Also(apis.ErrMissingField("path"))
+		} else if err := eventsplitter.ValidatePath(ss.Path, string(ss.PathSyntax)); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), "path"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(ss.PathSyntax, "pathSyntax"))
+	}
+
+	if ss.Sink == nil {
+		errs = errs.Also(apis.ErrMissingField("sink"))
+	}
+
+	errs = errs.Also(ss.CEContext.Validate(ctx).ViaField("ceContext"))
+
+	if ss.Envelope != nil {
+		for i, p := range ss.Envelope.ParentPaths {
+			if p == "" {
+				errs = errs.Also(apis.ErrMissingField(apis.CurrentField).ViaFieldIndex("parentPaths", i).ViaField("envelope"))
+			} else if err := eventsplitter.ValidateParentPath(p); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(err.Error(), apis.CurrentField).ViaFieldIndex("parentPaths", i).ViaField("envelope"))
+			}
+		}
+	}
+
+	if p := ss.Parallelism; p != nil {
+		switch {
+		case *p < 1:
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*p, 1, "∞", "parallelism"))
+		case ss.Ordered && *p > 1:
+			// events are dispatched one at a time when ordered
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*p, 1, 1, "parallelism"))
+		}
+	}
+
+	errs = errs.Also(ss.AdapterOverrides.Validate(ctx).ViaField("adapterOverrides"))
+
+	return errs.Also(ss.Delivery.Validate(ctx).ViaField("delivery"))
+}
+
+// Validate implements apis.Validatable
+func (c *CloudEventContext) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	switch {
+	case c.Type == "":
+		errs = errs.Also(apis.ErrMissingField("type"))
+	case strings.IndexFunc(c.Type, isSpaceOrControl) != -1:
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("invalid value: %s", c.Type),
+			Paths:   []string{"type"},
+			Details: "Type must not contain whitespaces or control characters",
+		})
+	}
+
+	if c.Source == "" {
+		errs = errs.Also(apis.ErrMissingField("source"))
+	} else if _, err := url.Parse(c.Source); err != nil {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("invalid value: %s", c.Source),
+			Paths:   []string{"source"},
+			Details: "Source must be a URI-reference",
+		})
+	}
+
+	for name := range c.Extensions {
+		if err := validateExtensionName(name); err != nil {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "extensions", err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// validateExtensionName verifies that the given name is a valid CloudEvent
+// extension name which doesn't collide with the extensions set by routers.
+func validateExtensionName(name string) error {
+	if name == "" || len(name) > maxExtensionNameLength {
+		return fmt.Errorf("extension names must contain between 1 and %d characters", maxExtensionNameLength)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return fmt.Errorf("extension names must only contain lowercase ASCII letters and digits")
+		}
+	}
+	if _, reserved := reservedAttributeNames[name]; reserved {
+		return fmt.Errorf("%q is a reserved CloudEvent attribute", name)
+	}
+	switch name {
+	case SplitterExtensionParentID, SplitterExtensionIndex, SplitterExtensionTotal, SplitterExtensionLast:
+		return fmt.Errorf("%q is set by the Splitter", name)
+	}
 	return nil
 }
+
+// isSpaceOrControl returns whether the given rune is a whitespace or a
+// control character.
+func isSpaceOrControl(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsControl(r)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestSplitterValidate(t *testing.T) {
+	validSpec := func() SplitterSpec {
+		return SplitterSpec{
+			Path: "items",
+			CEContext: CloudEventContext{
+				Type:   "io.triggermesh.item",
+				Source: "splitter/{.id}",
+			},
+			Sink: &duckv1.Destination{},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(*SplitterSpec)
+		errs   []string
+	}{{
+		name:   "valid",
+		mutate: func(*SplitterSpec) {},
+	}, {
+		name:   "empty gjson path",
+		mutate: func(s *SplitterSpec) { s.Path = "" },
+		errs:   []string{"spec.path"},
+	}, {
+		name: "empty json pointer",
+		mutate: func(s *SplitterSpec) {
+			s.Path = ""
+			s.PathSyntax = PathSyntaxJSONPointer
+		},
+	}, {
+		name: "invalid jsonpath",
+		mutate: func(s *SplitterSpec) {
+			s.Path = "{.items["
+			s.PathSyntax = PathSyntaxJSONPath
+		},
+		errs: []string{"spec.path"},
+	}, {
+		name:   "unknown path syntax",
+		mutate: func(s *SplitterSpec) { s.PathSyntax = "xpath" },
+		errs:   []string{"spec.pathSyntax"},
+	}, {
+		name:   "missing sink",
+		mutate: func(s *SplitterSpec) { s.Sink = nil },
+		errs:   []string{"spec.sink"},
+	}, {
+		name: "invalid ceContext",
+		mutate: func(s *SplitterSpec) {
+			s.CEContext.Type = "io.triggermesh item"
+			s.CEContext.Source = ""
+		},
+		errs: []string{"spec.ceContext.type", "spec.ceContext.source"},
+	}, {
+		name: "invalid extension names",
+		mutate: func(s *SplitterSpec) {
+			s.CEContext.Extensions = map[string]string{
+				"valid1":                "",
+				"Upper":                 "",
+				"subject":               "",
+				SplitterExtensionIndex:  "",
+				"waytoolongextension00": "",
+			}
+		},
+		errs: []string{
+			`invalid key name "Upper"`,
+			`invalid key name "subject"`,
+			`invalid key name "` + SplitterExtensionIndex + `"`,
+			`invalid key name "waytoolongextension00"`,
+		},
+	}, {
+		name: "invalid parent paths",
+		mutate: func(s *SplitterSpec) {
+			s.Envelope = &SplitterEnvelope{ParentPaths: []string{`labels.a\.b`, "", "items.#.id", "name|@reverse"}}
+		},
+		errs: []string{
+			"missing field(s): spec.envelope.parentPaths[1]",
+			"spec.envelope.parentPaths[2]",
+			"spec.envelope.parentPaths[3]",
+		},
+	}, {
+		name:   "invalid parallelism",
+		mutate: func(s *SplitterSpec) { s.Parallelism = new(int32) },
+		errs:   []string{"spec.parallelism"},
+	}, {
+		name: "ordered with default parallelism",
+		mutate: func(s *SplitterSpec) {
+			s.Ordered = true
+			s.Parallelism = ptr.Int32(1)
+		},
+	}, {
+		name: "ordered with parallelism",
+		mutate: func(s *SplitterSpec) {
+			s.Ordered = true
+			s.Parallelism = ptr.Int32(4)
+		},
+		errs: []string{"expected 1 <= 4 <= 1: spec.parallelism"},
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Splitter{Spec: validSpec()}
+			tc.mutate(&s.Spec)
+
+			err := s.Validate(context.Background())
+			if len(tc.errs) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			if assert.NotNil(t, err) {
+				for _, p := range tc.errs {
+					assert.Contains(t, err.Error(), p)
+				}
+			}
+		})
+	}
+}
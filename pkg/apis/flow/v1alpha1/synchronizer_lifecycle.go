@@ -0,0 +1,93 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+// GetGroupVersionKind implements kmeta.OwnerRefable
+func (*Synchronizer) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Synchronizer")
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (s *Synchronizer) GetConditionSet() apis.ConditionSet {
+	return routerConditionSet
+}
+
+// GetAdapterOverrides implements adapterOverrider.
+func (s *Synchronizer) GetAdapterOverrides() *AdapterOverrides {
+	return s.Spec.AdapterOverrides
+}
+
+// IsMultiTenant implements MultiTenant.
+func (s *Synchronizer) IsMultiTenant() bool {
+	return !flow.HasDedicatedAdapter(s)
+}
+
+// Supported event types
+const (
+	SynchronizerGenericEventType = "io.triggermesh.routing.synchronizer"
+)
+
+// GetEventTypes implements Reconcilable.
+func (*Synchronizer) GetEventTypes() []string {
+	return []string{
+		SynchronizerGenericEventType,
+	}
+}
+
+// GetSink implements Reconcilable.
+func (s *Synchronizer) GetSink() *duckv1.Destination {
+	return s.Spec.Sink
+}
+
+// GetStatusManager implements Reconcilable.
+func (s *Synchronizer) GetStatusManager() *RouterStatusManager {
+	return &RouterStatusManager{
+		ConditionSet: s.GetConditionSet(),
+		RouterStatus: &s.Status,
+	}
+}
+
+// Defaults of Synchronizers.
+const (
+	// SynchronizerExtensionCorrelationID is the default name of the
+	// CloudEvent extension which correlates requests and responses.
+	SynchronizerExtensionCorrelationID = "correlationid"
+	// SynchronizerDefaultTimeout is the default maximum duration a request
+	// waits for a response event.
+	SynchronizerDefaultTimeout = 30 * time.Second
+)
+
+// ResponseTimeout returns the maximum duration a request waits for a
+// response event.
+func (s *Synchronizer) ResponseTimeout() time.Duration {
+	if t := s.Spec.Response.Timeout; t != nil {
+		if d, err := time.ParseDuration(*t); err == nil && d > 0 {
+			return d
+		}
+	}
+	return SynchronizerDefaultTimeout
+}
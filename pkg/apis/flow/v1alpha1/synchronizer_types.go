@@ -0,0 +1,117 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Synchronizer is an addressable object that bridges synchronous HTTP
+// requests to asynchronous event flows. Requests are sent to the sink as
+// events, and answered with the first response event which carries their
+// correlation key.
+type Synchronizer struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the Synchronizer (from the client).
+	// +optional
+	Spec SynchronizerSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the Synchronizer (from the controller).
+	// +optional
+	Status RouterStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that Synchronizer can be validated and defaulted.
+	_ apis.Validatable   = (*Synchronizer)(nil)
+	_ apis.Defaultable   = (*Synchronizer)(nil)
+	_ kmeta.OwnerRefable = (*Synchronizer)(nil)
+	// Check that the type conforms to the duck Knative Resource shape.
+	_ duckv1.KRShaped  = (*Synchronizer)(nil)
+	_ multiTenant      = (*Synchronizer)(nil)
+	_ adapterOverrider = (*Synchronizer)(nil)
+
+	_ Reconcilable = (*Synchronizer)(nil)
+)
+
+// SynchronizerSpec holds the desired state of the Synchronizer
+type SynchronizerSpec struct {
+	// CorrelationKey determines how response events are correlated with
+	// the requests they answer.
+	// +optional
+	CorrelationKey SynchronizerCorrelationKey `json:"correlationKey,omitempty"`
+
+	// Response determines how long requests wait for a response event.
+	// +optional
+	Response SynchronizerResponse `json:"response,omitempty"`
+
+	// Sink is a reference to an object that will resolve to a domain name to use as the sink.
+	Sink *duckv1.Destination `json:"sink"`
+
+	// AdapterOverrides are applied to the Pods of the Synchronizer's adapter. They
+	// can only be set if the Synchronizer uses a dedicated adapter.
+	// +optional
+	AdapterOverrides *AdapterOverrides `json:"adapterOverrides,omitempty"`
+}
+
+// SynchronizerCorrelationKey determines the CloudEvent extension which
+// correlates requests and responses.
+type SynchronizerCorrelationKey struct {
+	// Attribute is the name of the CloudEvent extension which carries the
+	// correlation key, e.g. "correlationid".
+	// +optional
+	Attribute string `json:"attribute,omitempty"`
+}
+
+// SynchronizerResponse determines how long requests wait for a response
+// event.
+type SynchronizerResponse struct {
+	// Timeout is the maximum duration a request waits for a response event,
+	// after which it fails, e.g. "30s".
+	// +optional
+	Timeout *string `json:"timeout,omitempty"`
+}
+
+// SynchronizerList is a list of Synchronizer resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SynchronizerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Synchronizer `json:"items"`
+}
+
+// GetStatus retrieves the status of the resource. Implements the KRShaped interface.
+func (s *Synchronizer) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
+
+// AsRouter implements Reconcilable.
+func (s *Synchronizer) AsRouter() string {
+	return "synchronizer/" + s.Name
+}
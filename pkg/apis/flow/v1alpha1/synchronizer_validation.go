@@ -0,0 +1,60 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+)
+
+// extensionNameRegexp matches valid names of CloudEvent extensions.
+var extensionNameRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// Validate implements apis.Validatable
+func (s *Synchronizer) Validate(ctx context.Context) *apis.FieldError {
+	return flow.ValidateAdapterIsolation(s).Also(
+		flow.ValidateAdapterOverrides(s, s.Spec.AdapterOverrides != nil),
+		s.Spec.Validate(ctx).ViaField("spec"))
+}
+
+// Validate implements apis.Validatable
+func (ss *SynchronizerSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if ss.Sink == nil {
+		errs = errs.Also(apis.ErrMissingField("sink"))
+	}
+
+	if a := ss.CorrelationKey.Attribute; a != "" && !extensionNameRegexp.MatchString(a) {
+		errs = errs.Also(apis.ErrInvalidValue(a, "correlationKey.attribute"))
+	}
+
+	if t := ss.Response.Timeout; t != nil {
+		if d, err := time.ParseDuration(*t); err != nil || d <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(*t, "response.timeout"))
+		}
+	}
+
+	// pending requests are kept in the memory of a single adapter instance
+	return errs.Also(ss.AdapterOverrides.Validate(ctx).Also(
+		ss.AdapterOverrides.validateSingleReplica()).ViaField("adapterOverrides"))
+}
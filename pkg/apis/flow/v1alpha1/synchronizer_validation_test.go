@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestSynchronizerValidate(t *testing.T) {
+	validSpec := func() SynchronizerSpec {
+		return SynchronizerSpec{
+			CorrelationKey: SynchronizerCorrelationKey{
+				Attribute: SynchronizerExtensionCorrelationID,
+			},
+			Sink: &duckv1.Destination{},
+		}
+	}
+
+	strPtr := func(s string) *string { return &s }
+
+	testCases := []struct {
+		name   string
+		mutate func(*SynchronizerSpec)
+		errs   []string
+	}{{
+		name:   "valid",
+		mutate: func(*SynchronizerSpec) {},
+	}, {
+		name:   "valid timeout",
+		mutate: func(s *SynchronizerSpec) { s.Response.Timeout = strPtr("1m30s") },
+	}, {
+		name:   "missing sink",
+		mutate: func(s *SynchronizerSpec) { s.Sink = nil },
+		errs:   []string{"spec.sink"},
+	}, {
+		name:   "invalid attribute",
+		mutate: func(s *SynchronizerSpec) { s.CorrelationKey.Attribute = "correlation-id" },
+		errs:   []string{"spec.correlationKey.attribute"},
+	}, {
+		name:   "malformed timeout",
+		mutate: func(s *SynchronizerSpec) { s.Response.Timeout = strPtr("thirty") },
+		errs:   []string{"spec.response.timeout"},
+	}, {
+		name:   "non-positive timeout",
+		mutate: func(s *SynchronizerSpec) { s.Response.Timeout = strPtr("0s") },
+		errs:   []string{"spec.response.timeout"},
+	}, {
+		name: "multiple replicas",
+		mutate: func(s *SynchronizerSpec) {
+			maxScale := int32(2)
+			s.AdapterOverrides = &AdapterOverrides{
+				Scaling: &AdapterScaling{MaxScale: &maxScale},
+			}
+		},
+		errs: []string{"spec.adapterOverrides.scaling.maxScale"},
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Synchronizer{Spec: validSpec()}
+			tc.mutate(&s.Spec)
+
+			err := s.Validate(context.Background())
+			if len(tc.errs) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			if assert.NotNil(t, err) {
+				for _, p := range tc.errs {
+					assert.Contains(t, err.Error(), p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"knative.dev/pkg/apis"
+)
+
+// Status conditions
+const (
+	// ConditionSinkProvided has status True when the router has been configured with a sink target.
+	ConditionSinkProvided apis.ConditionType = "SinkProvided"
+	// ConditionDeployed has status True when the router's adapter is up and running.
+	ConditionDeployed apis.ConditionType = "Deployed"
+)
+
+// routerConditionSet is a generic set of status conditions used by
+// default in all routers.
+var routerConditionSet = apis.NewLivingConditionSet(
+	ConditionSinkProvided,
+	ConditionDeployed,
+)
+
+// errHighestVersion returns the error returned by conversion functions of
+// v1beta1 objects. Conversions are performed by the v1alpha1 hub version.
+func errHighestVersion(obj apis.Convertible) error {
+	return fmt.Errorf("v1beta1 is the highest known version, got: %T", obj)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// RouterStatus defines the observed state of a router.
+type RouterStatus struct {
+	duckv1.SourceStatus  `json:",inline"`
+	duckv1.AddressStatus `json:",inline"`
+
+	// ErrorSinkURI is the resolved URI of the router's error sink, for
+	// routers which have one.
+	// +optional
+	ErrorSinkURI *apis.URL `json:"errorSinkUri,omitempty"`
+
+	// DeadLetterSinkURI is the resolved URI of the router's dead letter
+	// sink, for routers which have one.
+	// +optional
+	DeadLetterSinkURI *apis.URL `json:"deadLetterSinkUri,omitempty"`
+}
+
+// AdapterOverrides are properties of the adapter Pods of a router which
+// override the defaults set by the controller. They only apply to dedicated
+// adapters.
+type AdapterOverrides struct {
+	// Resources are the compute resources of the adapter container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Scaling determines the number of adapter instances.
+	// +optional
+	Scaling *AdapterScaling `json:"scaling,omitempty"`
+
+	// NodeSelector constrains the adapter Pods to nodes with matching
+	// labels.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the adapter Pods.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the adapter
+	// Pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// AdapterScaling determines the number of instances of an adapter.
+type AdapterScaling struct {
+	// MinScale is the minimum number of adapter instances. Adapters backed
+	// by a Deployment run exactly that number of instances.
+	// +optional
+	MinScale *int32 `json:"minScale,omitempty"`
+
+	// MaxScale is the maximum number of adapter instances. Only applies to
+	// adapters backed by a Knative Service.
+	// +optional
+	MaxScale *int32 `json:"maxScale,omitempty"`
+
+	// Target is the number of concurrent requests per adapter instance
+	// targeted by the autoscaler. Only applies to adapters backed by a
+	// Knative Service.
+	// +optional
+	Target *int32 `json:"target,omitempty"`
+}
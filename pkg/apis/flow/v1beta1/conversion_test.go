@@ -0,0 +1,333 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Conversions are implemented by the v1alpha1 hub, which imports this
+// package, hence the external test package.
+package v1beta1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1beta1"
+)
+
+func TestHighestVersion(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Error(t, (&v1beta1.Filter{}).ConvertTo(ctx, &v1alpha1.Filter{}))
+	assert.Error(t, (&v1beta1.Filter{}).ConvertFrom(ctx, &v1alpha1.Filter{}))
+	assert.Error(t, (&v1beta1.Splitter{}).ConvertTo(ctx, &v1alpha1.Splitter{}))
+	assert.Error(t, (&v1beta1.Splitter{}).ConvertFrom(ctx, &v1alpha1.Splitter{}))
+}
+
+func TestFilterRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("v1alpha1 to v1beta1 to v1alpha1", func(t *testing.T) {
+		alpha := &v1alpha1.Filter{
+			ObjectMeta: newObjectMeta(),
+			Spec: v1alpha1.FilterSpec{
+				Expression: `$order.id.(int64) > 5 && $tags.(list).exists(t, t == "a")`,
+				Sink:       &duckv1.Destination{Ref: &duckv1.KReference{Kind: "Service", Name: "sink"}},
+				OnError:    v1alpha1.ErrorPolicyRoute,
+				ErrorSink:  &duckv1.Destination{URI: apis.HTTP("errors.ns.svc.cluster.local")},
+				Delivery:   newDeliverySpec(),
+				Transform: &v1alpha1.FilterTransform{
+					Operations: []v1alpha1.TransformOperation{{
+						Operation:  v1alpha1.TransformOperationSet,
+						Path:       "data.order",
+						Expression: `string($order.id.(int64))`,
+					}, {
+						Operation: v1alpha1.TransformOperationSet,
+						Path:      "ce.subject",
+						Template:  "{{ .ce.type }}",
+					}, {
+						Operation: v1alpha1.TransformOperationRename,
+						Path:      "data.tags",
+						To:        "data.labels",
+					}, {
+						Operation: v1alpha1.TransformOperationDelete,
+						Path:      "data.internal",
+					}},
+				},
+				AdapterOverrides: newAlphaOverrides(),
+			},
+			Status: newAlphaStatus(),
+		}
+		orig := alpha.DeepCopy()
+
+		beta := &v1beta1.Filter{}
+		require.NoError(t, alpha.ConvertTo(ctx, beta))
+		assert.Equal(t, orig, alpha, "Source should not be mutated")
+
+		// inline variables are declared separately in v1beta1
+		assert.Equal(t, `var_order_id > 5 && var_tags.exists(t, t == "a")`, beta.Spec.Expression)
+		assert.Equal(t, []v1beta1.Variable{
+			{Name: "var_order_id", Path: "order.id", Type: v1beta1.VariableTypeInt64},
+			{Name: "var_tags", Path: "tags", Type: v1beta1.VariableTypeList},
+		}, beta.Spec.Variables)
+		assert.Equal(t, `string(var_order_id)`, beta.Spec.Transform.Operations[0].Expression)
+		assert.Equal(t, v1beta1.ErrorPolicyRoute, beta.Spec.OnError)
+		assert.Equal(t, v1beta1.TransformOperationRename, beta.Spec.Transform.Operations[2].Operation)
+		assert.Equal(t, "data.labels", beta.Spec.Transform.Operations[2].To)
+		assert.Equal(t, ptr.Int32(3), beta.Spec.AdapterOverrides.Scaling.MaxScale)
+		assert.Equal(t, alpha.Status.DeadLetterSinkURI, beta.Status.DeadLetterSinkURI)
+		assert.Nil(t, beta.Validate(ctx))
+
+		got := &v1alpha1.Filter{}
+		require.NoError(t, got.ConvertFrom(ctx, beta))
+		assert.Equal(t, orig, got)
+	})
+
+	t.Run("v1beta1 to v1alpha1 to v1beta1", func(t *testing.T) {
+		beta := &v1beta1.Filter{
+			ObjectMeta: newObjectMeta(),
+			Spec: v1beta1.FilterSpec{
+				// names and order of variables aren't representable in
+				// v1alpha1
+				Expression: `size(tags) > 0 && orderID == 5`,
+				Variables: []v1beta1.Variable{
+					{Name: "tags", Path: "tags", Type: v1beta1.VariableTypeList},
+					{Name: "orderID", Path: "order.id", Type: v1beta1.VariableTypeInt64},
+				},
+				Sink:      &duckv1.Destination{Ref: &duckv1.KReference{Kind: "Service", Name: "sink"}},
+				OnError:   v1beta1.ErrorPolicyDrop,
+				ErrorSink: &duckv1.Destination{URI: apis.HTTP("errors.ns.svc.cluster.local")},
+				Delivery:  newDeliverySpec(),
+				Transform: &v1beta1.FilterTransform{
+					Operations: []v1beta1.TransformOperation{{
+						Operation:  v1beta1.TransformOperationSet,
+						Path:       "data.order",
+						Expression: `string(orderID)`,
+					}, {
+						Operation: v1beta1.TransformOperationRename,
+						Path:      "data.tags",
+						To:        "data.labels",
+					}},
+				},
+				AdapterOverrides: newBetaOverrides(),
+			},
+			Status: newBetaStatus(),
+		}
+		orig := beta.DeepCopy()
+
+		alpha := &v1alpha1.Filter{}
+		require.NoError(t, alpha.ConvertFrom(ctx, beta))
+		assert.Equal(t, orig, beta, "Source should not be mutated")
+
+		assert.Equal(t, `size($tags.(list)) > 0 && $order.id.(int64) == 5`, alpha.Spec.Expression)
+		assert.Equal(t, `string($order.id.(int64))`, alpha.Spec.Transform.Operations[0].Expression)
+		assert.Equal(t, v1alpha1.ErrorPolicyDrop, alpha.Spec.OnError)
+
+		got := &v1beta1.Filter{}
+		require.NoError(t, alpha.ConvertTo(ctx, got))
+		assert.Equal(t, orig, got)
+	})
+}
+
+func TestSplitterRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("v1alpha1 to v1beta1 to v1alpha1", func(t *testing.T) {
+		alpha := &v1alpha1.Splitter{
+			ObjectMeta: newObjectMeta(),
+			Spec: v1alpha1.SplitterSpec{
+				Path:       "{.orders[*]}",
+				PathSyntax: v1alpha1.PathSyntaxJSONPath,
+				CEContext: v1alpha1.CloudEventContext{
+					Type:       "io.triggermesh.order",
+					Source:     "orders",
+					Extensions: map[string]string{"team": "a"},
+				},
+				Envelope: &v1alpha1.SplitterEnvelope{
+					Wrap:        true,
+					ParentPaths: []string{"id", "customer.name"},
+				},
+				Parallelism:      ptr.Int32(1),
+				Ordered:          true,
+				Sink:             &duckv1.Destination{URI: apis.HTTP("sink.ns.svc.cluster.local")},
+				Delivery:         newDeliverySpec(),
+				AdapterOverrides: newAlphaOverrides(),
+			},
+			Status: newAlphaStatus(),
+		}
+		orig := alpha.DeepCopy()
+
+		beta := &v1beta1.Splitter{}
+		require.NoError(t, alpha.ConvertTo(ctx, beta))
+		assert.Equal(t, orig, alpha, "Source should not be mutated")
+
+		// top-level attributes are grouped by stage in v1beta1
+		assert.Equal(t, v1beta1.SplitterInput{
+			Path:       "{.orders[*]}",
+			PathSyntax: v1beta1.PathSyntaxJSONPath,
+		}, beta.Spec.Input)
+		assert.Equal(t, v1beta1.SplitterOutput{
+			CEContext: v1beta1.CloudEventContext{
+				Type:       "io.triggermesh.order",
+				Source:     "orders",
+				Extensions: map[string]string{"team": "a"},
+			},
+			Envelope: &v1beta1.SplitterEnvelope{
+				Wrap:        true,
+				ParentPaths: []string{"id", "customer.name"},
+			},
+		}, beta.Spec.Output)
+		assert.Equal(t, v1beta1.SplitterDispatch{
+			Parallelism: ptr.Int32(1),
+			Ordered:     true,
+		}, beta.Spec.Dispatch)
+		assert.Nil(t, beta.Validate(ctx))
+
+		got := &v1alpha1.Splitter{}
+		require.NoError(t, got.ConvertFrom(ctx, beta))
+		assert.Equal(t, orig, got)
+	})
+
+	t.Run("v1beta1 to v1alpha1 to v1beta1", func(t *testing.T) {
+		beta := &v1beta1.Splitter{
+			ObjectMeta: newObjectMeta(),
+			Spec: v1beta1.SplitterSpec{
+				Input: v1beta1.SplitterInput{
+					Path:       "/items",
+					PathSyntax: v1beta1.PathSyntaxJSONPointer,
+				},
+				Output: v1beta1.SplitterOutput{
+					CEContext: v1beta1.CloudEventContext{
+						Type:   "io.triggermesh.item",
+						Source: "items",
+					},
+					Envelope: &v1beta1.SplitterEnvelope{ParentPaths: []string{"id"}},
+				},
+				Dispatch: v1beta1.SplitterDispatch{
+					Parallelism: ptr.Int32(4),
+				},
+				Sink:             &duckv1.Destination{URI: apis.HTTP("sink.ns.svc.cluster.local")},
+				Delivery:         newDeliverySpec(),
+				AdapterOverrides: newBetaOverrides(),
+			},
+			Status: newBetaStatus(),
+		}
+		orig := beta.DeepCopy()
+
+		alpha := &v1alpha1.Splitter{}
+		require.NoError(t, alpha.ConvertFrom(ctx, beta))
+		assert.Equal(t, orig, beta, "Source should not be mutated")
+
+		assert.Equal(t, "/items", alpha.Spec.Path)
+		assert.Equal(t, v1alpha1.PathSyntaxJSONPointer, alpha.Spec.PathSyntax)
+		assert.Equal(t, ptr.Int32(4), alpha.Spec.Parallelism)
+
+		got := &v1beta1.Splitter{}
+		require.NoError(t, alpha.ConvertTo(ctx, got))
+		assert.Equal(t, orig, got)
+	})
+}
+
+// newObjectMeta returns the metadata of an object which uses a dedicated
+// adapter, so that its adapter overrides are valid.
+func newObjectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace:  "ns",
+		Name:       "test",
+		Generation: 2,
+		Labels:     map[string]string{"app": "test"},
+		Annotations: map[string]string{
+			flow.AdapterIsolationAnnotation: flow.AdapterIsolationDedicated,
+		},
+	}
+}
+
+func newDeliverySpec() *eventingduckv1.DeliverySpec {
+	linear := eventingduckv1.BackoffPolicyLinear
+	return &eventingduckv1.DeliverySpec{
+		DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dls.ns.svc.cluster.local")},
+		Retry:          ptr.Int32(3),
+		BackoffPolicy:  &linear,
+		BackoffDelay:   ptr.String("PT0.5S"),
+	}
+}
+
+func newAlphaOverrides() *v1alpha1.AdapterOverrides {
+	return &v1alpha1.AdapterOverrides{
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		},
+		Scaling: &v1alpha1.AdapterScaling{
+			MinScale: ptr.Int32(1),
+			MaxScale: ptr.Int32(3),
+			Target:   ptr.Int32(100),
+		},
+		NodeSelector:      map[string]string{"pool": "routing"},
+		Tolerations:       []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		PriorityClassName: "high",
+	}
+}
+
+func newBetaOverrides() *v1beta1.AdapterOverrides {
+	return &v1beta1.AdapterOverrides{
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		},
+		Scaling: &v1beta1.AdapterScaling{
+			MinScale: ptr.Int32(1),
+			MaxScale: ptr.Int32(3),
+			Target:   ptr.Int32(100),
+		},
+		NodeSelector:      map[string]string{"pool": "routing"},
+		Tolerations:       []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		PriorityClassName: "high",
+	}
+}
+
+func newAlphaStatus() v1alpha1.RouterStatus {
+	var st v1alpha1.RouterStatus
+	st.ObservedGeneration = 2
+	st.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+	st.SinkURI = apis.HTTP("sink.ns.svc.cluster.local")
+	st.Address = &duckv1.Addressable{URL: apis.HTTP("adapter.ns.svc.cluster.local")}
+	st.CloudEventAttributes = []duckv1.CloudEventAttributes{{Type: "io.triggermesh.item", Source: "items"}}
+	st.ErrorSinkURI = apis.HTTP("errors.ns.svc.cluster.local")
+	st.DeadLetterSinkURI = apis.HTTP("dls.ns.svc.cluster.local")
+	return st
+}
+
+func newBetaStatus() v1beta1.RouterStatus {
+	var st v1beta1.RouterStatus
+	st.ObservedGeneration = 2
+	st.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+	st.SinkURI = apis.HTTP("sink.ns.svc.cluster.local")
+	st.Address = &duckv1.Addressable{URL: apis.HTTP("adapter.ns.svc.cluster.local")}
+	st.CloudEventAttributes = []duckv1.CloudEventAttributes{{Type: "io.triggermesh.item", Source: "items"}}
+	st.ErrorSinkURI = apis.HTTP("errors.ns.svc.cluster.local")
+	st.DeadLetterSinkURI = apis.HTTP("dls.ns.svc.cluster.local")
+	return st
+}
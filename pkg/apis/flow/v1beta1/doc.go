@@ -0,0 +1,26 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 contains API Schema definitions for the flow v1beta1 API group.
+//
+// Compared to v1alpha1, expressions declare the payload variables they
+// reference separately from the CEL text, and the attributes of Splitters are
+// grouped by concern. The v1alpha1 version remains the storage version, and
+// objects are converted between both versions by the routing-webhook.
+//
+// +k8s:deepcopy-gen=package
+// +groupName=flow.triggermesh.io
+package v1beta1
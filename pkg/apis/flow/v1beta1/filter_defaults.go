@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// SetDefaults implements apis.Defaultable
+func (f *Filter) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, f.ObjectMeta)
+
+	f.Spec.Expression = cel.NormalizeExpression(f.Spec.Expression)
+	if t := f.Spec.Transform; t != nil {
+		for i := range t.Operations {
+			t.Operations[i].Expression = cel.NormalizeExpression(t.Operations[i].Expression)
+		}
+	}
+
+	if f.Spec.OnError == "" {
+		f.Spec.OnError = ErrorPolicyPass
+	}
+
+	if f.Spec.Sink != nil {
+		f.Spec.Sink.SetDefaults(ctx)
+	}
+	if f.Spec.ErrorSink != nil {
+		f.Spec.ErrorSink.SetDefaults(ctx)
+	}
+
+	setDeliveryDefaults(ctx, f.Spec.Delivery)
+}
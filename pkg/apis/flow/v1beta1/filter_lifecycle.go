@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+	"github.com/triggermesh/routing/pkg/eventtransform"
+)
+
+// GetGroupVersionKind implements kmeta.OwnerRefable
+func (*Filter) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Filter")
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*Filter) GetConditionSet() apis.ConditionSet {
+	return routerConditionSet
+}
+
+// CELVariables returns the variables declared by the Filter as CEL variable
+// definitions.
+func (fs *FilterSpec) CELVariables() []cel.Variable {
+	if fs.Variables == nil {
+		return nil
+	}
+
+	vars := make([]cel.Variable, len(fs.Variables))
+	for i, v := range fs.Variables {
+		vars[i] = cel.Variable{
+			Name: v.Name,
+			Path: v.Path,
+			Type: string(v.Type),
+		}
+	}
+	return vars
+}
+
+// TransformOperations returns the operations of the FilterTransform in the
+// format accepted by the eventtransform package.
+func (t *FilterTransform) TransformOperations() []eventtransform.Operation {
+	ops := make([]eventtransform.Operation, len(t.Operations))
+	for i, op := range t.Operations {
+		ops[i] = op.transformOperation()
+	}
+	return ops
+}
+
+// transformOperation returns the TransformOperation in the format accepted
+// by the eventtransform package.
+func (op *TransformOperation) transformOperation() eventtransform.Operation {
+	return eventtransform.Operation{
+		Op:         string(op.Operation),
+		Path:       op.Path,
+		To:         op.To,
+		Expression: op.Expression,
+		Template:   op.Template,
+	}
+}
+
+// ConvertTo implements apis.Convertible
+func (f *Filter) ConvertTo(ctx context.Context, to apis.Convertible) error {
+	return errHighestVersion(to)
+}
+
+// ConvertFrom implements apis.Convertible
+func (f *Filter) ConvertFrom(ctx context.Context, from apis.Convertible) error {
+	return errHighestVersion(from)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Filter is an addressable object that filters incoming events according
+// to provided Common Language Expression
+type Filter struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the Filter (from the client).
+	// +optional
+	Spec FilterSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the Filter (from the controller).
+	// +optional
+	Status RouterStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that Filter can be validated, defaulted and converted.
+	_ apis.Validatable   = (*Filter)(nil)
+	_ apis.Defaultable   = (*Filter)(nil)
+	_ apis.Convertible   = (*Filter)(nil)
+	_ kmeta.OwnerRefable = (*Filter)(nil)
+	// Check that the type conforms to the duck Knative Resource shape.
+	_ duckv1.KRShaped = (*Filter)(nil)
+)
+
+// FilterSpec contains the CEL expression and the destination sink
+type FilterSpec struct {
+	// Expression is a CEL expression which evaluates to a boolean. It can
+	// reference the context attributes of events through the "ce" variable,
+	// and the payload variables declared in Variables.
+	Expression string `json:"expression"`
+
+	// Variables declares the values from the event payload which are
+	// referenced by the expression.
+	// +optional
+	Variables []Variable `json:"variables,omitempty"`
+
+	// Sink is a reference to an object that will resolve to a domain name to use as the sink.
+	Sink *duckv1.Destination `json:"sink"`
+
+	// OnError determines how events are handled when the evaluation of the
+	// expression fails. Defaults to "pass".
+	// +optional
+	OnError ErrorPolicy `json:"onError,omitempty"`
+
+	// ErrorSink receives events for which the evaluation of the expression
+	// failed, when OnError is set to "route".
+	// +optional
+	ErrorSink *duckv1.Destination `json:"errorSink,omitempty"`
+
+	// Delivery contains the retry and dead letter sink configuration of
+	// the events dispatched by the Filter.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// Transform describes mutations applied to events which pass the
+	// Filter, before they are forwarded to the sink.
+	// +optional
+	Transform *FilterTransform `json:"transform,omitempty"`
+
+	// AdapterOverrides are applied to the Pods of the Filter's adapter. They
+	// can only be set if the Filter uses a dedicated adapter.
+	// +optional
+	AdapterOverrides *AdapterOverrides `json:"adapterOverrides,omitempty"`
+}
+
+// FilterTransform is a sequence of mutations applied to the context
+// attributes and the JSON payload of events.
+type FilterTransform struct {
+	// Operations are applied in order. Expressions and templates are
+	// evaluated against the event as it was received.
+	Operations []TransformOperation `json:"operations"`
+}
+
+// TransformOperation describes a single mutation of an event.
+type TransformOperation struct {
+	// Operation is the kind of mutation.
+	Operation TransformOperationType `json:"operation"`
+
+	// Path of the mutated field. Paths starting with "ce." refer to context
+	// attributes and extensions, e.g. "ce.type", and paths starting with
+	// "data" refer to dot-separated keys of the JSON payload, e.g.
+	// "data.user.name".
+	Path string `json:"path"`
+
+	// To is the destination path of a renamed field.
+	// +optional
+	To string `json:"to,omitempty"`
+
+	// Expression is a CEL expression which computes the value of a set
+	// field. It may reference the Filter's variables.
+	// +optional
+	Expression string `json:"expression,omitempty"`
+
+	// Template is a Go template which renders the value of a set field as a
+	// string, e.g. "{{ .ce.type }}/{{ .data.user.name }}".
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// TransformOperationType is the kind of mutation performed by a
+// TransformOperation.
+type TransformOperationType string
+
+// Supported transform operations
+const (
+	// TransformOperationSet sets the value of a field.
+	TransformOperationSet TransformOperationType = "set"
+	// TransformOperationDelete removes a field.
+	TransformOperationDelete TransformOperationType = "delete"
+	// TransformOperationRename moves the value of a field to another field.
+	TransformOperationRename TransformOperationType = "rename"
+)
+
+// Variable is a value from the event payload referenced by an expression.
+type Variable struct {
+	// Name is the CEL identifier of the variable inside the expression.
+	Name string `json:"name"`
+	// Path is the GJSON path of the value inside the event payload.
+	Path string `json:"path"`
+	// Type is the CEL type of the value.
+	Type VariableType `json:"type"`
+}
+
+// VariableType is the CEL type of a variable.
+type VariableType string
+
+// Supported variable types
+const (
+	VariableTypeBool      VariableType = "bool"
+	VariableTypeInt64     VariableType = "int64"
+	VariableTypeUint64    VariableType = "uint64"
+	VariableTypeDouble    VariableType = "double"
+	VariableTypeString    VariableType = "string"
+	VariableTypeBytes     VariableType = "bytes"
+	VariableTypeList      VariableType = "list"
+	VariableTypeMap       VariableType = "map"
+	VariableTypeTimestamp VariableType = "timestamp"
+	VariableTypeDuration  VariableType = "duration"
+)
+
+// ErrorPolicy is the strategy used to handle events for which the evaluation
+// of an expression failed.
+type ErrorPolicy string
+
+// Supported error policies
+const (
+	// ErrorPolicyPass forwards events to the sink as if they passed the filter.
+	ErrorPolicyPass ErrorPolicy = "pass"
+	// ErrorPolicyDrop discards events.
+	ErrorPolicyDrop ErrorPolicy = "drop"
+	// ErrorPolicyRoute forwards events to the error sink.
+	ErrorPolicyRoute ErrorPolicy = "route"
+)
+
+// FilterList is a list of Filter resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FilterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Filter `json:"items"`
+}
+
+// GetStatus retrieves the status of the resource. Implements the KRShaped interface.
+func (f *Filter) GetStatus() *duckv1.Status {
+	return &f.Status.Status
+}
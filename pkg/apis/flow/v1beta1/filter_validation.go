@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// ceVariableName is the name of the CEL variable which holds the context
+// attributes of events.
+const ceVariableName = "ce"
+
+// Validate implements apis.Validatable
+func (f *Filter) Validate(ctx context.Context) *apis.FieldError {
+	return flow.ValidateAdapterIsolation(f).Also(
+		flow.ValidateAdapterOverrides(f, f.Spec.AdapterOverrides != nil),
+		f.Spec.Validate(ctx).ViaField("spec"))
+}
+
+// Validate implements apis.Validatable
+func (fs *FilterSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if fs.Sink == nil {
+		errs = errs.Also(apis.ErrMissingField("sink"))
+	}
+
+	varErrs := validateVariables(fs.Variables).ViaField("variables")
+	errs = errs.Also(varErrs)
+
+	switch {
+	case fs.Expression == "":
+		errs = errs.Also(apis.ErrMissingField("expression"))
+	case varErrs == nil:
+		if _, err := cel.CompileExpressionWithVariables(fs.Expression, fs.CELVariables()); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Cannot compile expression: %v", err), "expression"))
+		}
+	}
+
+	switch fs.OnError {
+	case "", ErrorPolicyPass, ErrorPolicyDrop:
+	case ErrorPolicyRoute:
+		if fs.ErrorSink == nil {
+			errs = errs.Also(apis.ErrMissingField("errorSink"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fs.OnError, "onError"))
+	}
+
+	if varErrs == nil {
+		errs = errs.Also(fs.Transform.validate(fs.CELVariables()).ViaField("transform"))
+	}
+
+	errs = errs.Also(fs.AdapterOverrides.Validate(ctx).ViaField("adapterOverrides"))
+
+	return errs.Also(fs.Delivery.Validate(ctx).ViaField("delivery"))
+}
+
+// validate verifies that the operations of the FilterTransform can be
+// compiled with the given variables.
+func (t *FilterTransform) validate(vars []cel.Variable) *apis.FieldError {
+	if t == nil {
+		return nil
+	}
+	if len(t.Operations) == 0 {
+		return apis.ErrMissingField("operations")
+	}
+
+	var errs *apis.FieldError
+	for i, op := range t.Operations {
+		if err := op.transformOperation().ValidateWithVariables(vars); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), apis.CurrentField).ViaFieldIndex("operations", i))
+		}
+	}
+	return errs
+}
+
+// validateVariables verifies that variables have unique CEL identifiers, and
+// paths and types which can be represented inline in v1alpha1 expressions.
+func validateVariables(vars []Variable) *apis.FieldError {
+	var errs *apis.FieldError
+
+	names := make(map[string]struct{}, len(vars))
+
+	for i, v := range vars {
+		switch _, dup := names[v.Name]; {
+		case v.Name == "":
+			errs = errs.Also(apis.ErrMissingField("name").ViaIndex(i))
+		case !isIdentifier(v.Name) || v.Name == ceVariableName:
+			errs = errs.Also(apis.ErrInvalidValue(v.Name, "name").ViaIndex(i))
+		case dup:
+			errs = errs.Also(apis.ErrMultipleOneOf("name").ViaIndex(i))
+		}
+		names[v.Name] = struct{}{}
+
+		switch {
+		case v.Path == "":
+			errs = errs.Also(apis.ErrMissingField("path").ViaIndex(i))
+		case strings.Contains(v.Path, ".(") || strings.Contains(v.Path, ")"):
+			errs = errs.Also(apis.ErrInvalidValue(v.Path, "path").ViaIndex(i))
+		}
+
+		switch v.Type {
+		case VariableTypeBool, VariableTypeInt64, VariableTypeUint64, VariableTypeDouble,
+			VariableTypeString, VariableTypeBytes, VariableTypeList, VariableTypeMap,
+			VariableTypeTimestamp, VariableTypeDuration:
+		case "":
+			errs = errs.Also(apis.ErrMissingField("type").ViaIndex(i))
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(v.Type, "type").ViaIndex(i))
+		}
+	}
+
+	return errs
+}
+
+// isIdentifier returns whether s is a valid CEL identifier.
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
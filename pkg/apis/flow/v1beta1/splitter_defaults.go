@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+)
+
+// defaultParallelism is the default number of child events dispatched
+// concurrently by a Splitter.
+const defaultParallelism int32 = 1
+
+// SetDefaults implements apis.Defaultable
+func (s *Splitter) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
+
+	in := &s.Spec.Input
+	if in.PathSyntax == "" {
+		in.PathSyntax = PathSyntaxGJSON
+	}
+	if in.Path == "" {
+		in.Path = rootPath(in.PathSyntax)
+	}
+
+	ceCtx := &s.Spec.Output.CEContext
+	if ceCtx.Type == "" {
+		ceCtx.Type = SplitterGenericEventType
+	}
+	if ceCtx.Source == "" {
+		ceCtx.Source = "splitter/" + s.Name
+	}
+
+	if s.Spec.Dispatch.Parallelism == nil {
+		s.Spec.Dispatch.Parallelism = ptr.Int32(defaultParallelism)
+	}
+
+	if s.Spec.Sink != nil {
+		s.Spec.Sink.SetDefaults(ctx)
+	}
+
+	setDeliveryDefaults(ctx, s.Spec.Delivery)
+}
+
+// rootPath returns the expression which refers to the root of a JSON
+// document in the given path syntax.
+func rootPath(syntax PathSyntax) string {
+	switch syntax {
+	case PathSyntaxGJSON:
+		return "@this"
+	case PathSyntaxJSONPath:
+		return "$"
+	}
+	// an empty JSON Pointer refers to the whole document
+	return ""
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+// Splitter event types and extensions
+const (
+	SplitterGenericEventType = "io.triggermesh.routing.splitter"
+
+	// SplitterExtensionParentID is the ID of the event a child event was split from.
+	SplitterExtensionParentID = "splitparentid"
+	// SplitterExtensionIndex is the 0-based position of a child event.
+	SplitterExtensionIndex = "splitindex"
+	// SplitterExtensionTotal is the number of child events split from the same event.
+	SplitterExtensionTotal = "splittotal"
+	// SplitterExtensionLast is set on the last child event split from an event.
+	SplitterExtensionLast = "splitlast"
+)
+
+// GetGroupVersionKind implements kmeta.OwnerRefable
+func (*Splitter) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Splitter")
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*Splitter) GetConditionSet() apis.ConditionSet {
+	return routerConditionSet
+}
+
+// ConvertTo implements apis.Convertible
+func (s *Splitter) ConvertTo(ctx context.Context, to apis.Convertible) error {
+	return errHighestVersion(to)
+}
+
+// ConvertFrom implements apis.Convertible
+func (s *Splitter) ConvertFrom(ctx context.Context, from apis.Convertible) error {
+	return errHighestVersion(from)
+}
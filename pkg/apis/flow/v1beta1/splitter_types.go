@@ -0,0 +1,169 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Splitter is an addressable object that splits incoming events according
+// to provided specification
+type Splitter struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the Splitter (from the client).
+	// +optional
+	Spec SplitterSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the Splitter (from the controller).
+	// +optional
+	Status RouterStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that Splitter can be validated, defaulted and converted.
+	_ apis.Validatable   = (*Splitter)(nil)
+	_ apis.Defaultable   = (*Splitter)(nil)
+	_ apis.Convertible   = (*Splitter)(nil)
+	_ kmeta.OwnerRefable = (*Splitter)(nil)
+	// Check that the type conforms to the duck Knative Resource shape.
+	_ duckv1.KRShaped = (*Splitter)(nil)
+)
+
+// SplitterSpec holds the desired state of the Splitter
+type SplitterSpec struct {
+	// Input describes the element of the event payload to split.
+	// +optional
+	Input SplitterInput `json:"input,omitempty"`
+
+	// Output describes the events produced for each split element.
+	// +optional
+	Output SplitterOutput `json:"output,omitempty"`
+
+	// Dispatch describes how produced events are sent to the sink.
+	// +optional
+	Dispatch SplitterDispatch `json:"dispatch,omitempty"`
+
+	// Sink is a reference to an object that will resolve to a domain name to use as the sink.
+	Sink *duckv1.Destination `json:"sink"`
+
+	// Delivery contains the retry and dead letter sink configuration of
+	// the events dispatched by the Splitter.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// AdapterOverrides are applied to the Pods of the Splitter's adapter. They
+	// can only be set if the Splitter uses a dedicated adapter.
+	// +optional
+	AdapterOverrides *AdapterOverrides `json:"adapterOverrides,omitempty"`
+}
+
+// SplitterInput describes the element of the event payload to split.
+type SplitterInput struct {
+	// Path of the array or object to split. Defaults to the root of the
+	// payload.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// PathSyntax is the syntax used to interpret Path. Defaults to gjson.
+	// +optional
+	PathSyntax PathSyntax `json:"pathSyntax,omitempty"`
+}
+
+// SplitterOutput describes the events produced for each split element.
+type SplitterOutput struct {
+	// CEContext contains the context attributes of produced events.
+	// +optional
+	CEContext CloudEventContext `json:"ceContext,omitempty"`
+	// Envelope carries data from the parent event over to each child event.
+	// +optional
+	Envelope *SplitterEnvelope `json:"envelope,omitempty"`
+}
+
+// SplitterDispatch describes how produced events are sent to the sink.
+type SplitterDispatch struct {
+	// Parallelism is the maximum number of child events dispatched
+	// concurrently. Defaults to 1, and must be 1 when Ordered is set.
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+	// Ordered dispatches child events one at a time, in their order inside
+	// the parent event, and stops at the first failed delivery.
+	// +optional
+	Ordered bool `json:"ordered,omitempty"`
+}
+
+// SplitterEnvelope describes how data from the parent event is carried over
+// to each child event.
+type SplitterEnvelope struct {
+	// Wrap wraps each split element as
+	// {"parent": <parent>, "item": <element>, "index": <i>, "total": <n>}.
+	// +optional
+	Wrap bool `json:"wrap,omitempty"`
+	// ParentPaths are GJSON paths of the parent fields to copy into each
+	// child event. When Wrap is enabled and no path is set, the whole parent
+	// payload is copied.
+	// +optional
+	ParentPaths []string `json:"parentPaths,omitempty"`
+}
+
+// PathSyntax is the syntax of the path of the element to split.
+type PathSyntax string
+
+// Supported path syntaxes.
+const (
+	// PathSyntaxGJSON interprets the path as a GJSON path.
+	PathSyntaxGJSON PathSyntax = "gjson"
+	// PathSyntaxJSONPointer interprets the path as a RFC 6901 JSON Pointer.
+	PathSyntaxJSONPointer PathSyntax = "jsonpointer"
+	// PathSyntaxJSONPath interprets the path as a JSONPath expression.
+	PathSyntaxJSONPath PathSyntax = "jsonpath"
+)
+
+// CloudEventContext contains context attributes of CloudEvents.
+type CloudEventContext struct {
+	// Type is the "type" context attribute.
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Source is the "source" context attribute.
+	// +optional
+	Source string `json:"source,omitempty"`
+	// Extensions are additional context extensions.
+	// +optional
+	Extensions map[string]string `json:"extensions,omitempty"`
+}
+
+// SplitterList is a list of Splitter resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SplitterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Splitter `json:"items"`
+}
+
+// GetStatus retrieves the status of the resource. Implements the KRShaped interface.
+func (s *Splitter) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
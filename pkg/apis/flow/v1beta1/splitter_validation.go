@@ -0,0 +1,180 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/eventsplitter"
+)
+
+// maxExtensionNameLength is the maximum length of CloudEvent extension names
+// recommended by the CloudEvents specification.
+const maxExtensionNameLength = 20
+
+// reservedAttributeNames are the names of the CloudEvent context attributes
+// defined by the CloudEvents specification, which can not be used as
+// extension names.
+var reservedAttributeNames = map[string]struct{}{
+	"data":            {},
+	"data_base64":     {},
+	"datacontenttype": {},
+	"dataschema":      {},
+	"id":              {},
+	"source":          {},
+	"specversion":     {},
+	"subject":         {},
+	"time":            {},
+	"type":            {},
+}
+
+// Validate implements apis.Validatable
+func (s *Splitter) Validate(ctx context.Context) *apis.FieldError {
+	return flow.ValidateAdapterIsolation(s).Also(
+		flow.ValidateAdapterOverrides(s, s.Spec.AdapterOverrides != nil),
+		s.Spec.Validate(ctx).ViaField("spec"))
+}
+
+// Validate implements apis.Validatable
+func (ss *SplitterSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	errs = errs.Also(ss.Input.Validate(ctx).ViaField("input"))
+	errs = errs.Also(ss.Output.Validate(ctx).ViaField("output"))
+
+	if p := ss.Dispatch.Parallelism; p != nil {
+		switch {
+		case *p < 1:
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*p, 1, "∞", "parallelism").ViaField("dispatch"))
+		case ss.Dispatch.Ordered && *p > 1:
+			// events are dispatched one at a time when ordered
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*p, 1, 1, "parallelism").ViaField("dispatch"))
+		}
+	}
+
+	if ss.Sink == nil {
+		errs = errs.Also(apis.ErrMissingField("sink"))
+	}
+
+	errs = errs.Also(ss.AdapterOverrides.Validate(ctx).ViaField("adapterOverrides"))
+
+	return errs.Also(ss.Delivery.Validate(ctx).ViaField("delivery"))
+}
+
+// Validate implements apis.Validatable
+func (in *SplitterInput) Validate(ctx context.Context) *apis.FieldError {
+	switch in.PathSyntax {
+	case "", PathSyntaxGJSON, PathSyntaxJSONPointer, PathSyntaxJSONPath:
+	default:
+		return apis.ErrInvalidValue(in.PathSyntax, "pathSyntax")
+	}
+
+	// an empty JSON Pointer refers to the whole document, whereas
+	// other syntaxes have a dedicated expression for it
+	if in.Path == "" && in.PathSyntax != PathSyntaxJSONPointer {
+		return apis.ErrMissingField("path")
+	}
+	if err := eventsplitter.ValidatePath(in.Path, string(in.PathSyntax)); err != nil {
+		return apis.ErrInvalidValue(err.Error(), "path")
+	}
+	return nil
+}
+
+// Validate implements apis.Validatable
+func (out *SplitterOutput) Validate(ctx context.Context) *apis.FieldError {
+	errs := out.CEContext.Validate(ctx).ViaField("ceContext")
+
+	if out.Envelope != nil {
+		for i, p := range out.Envelope.ParentPaths {
+			if p == "" {
+				errs = errs.Also(apis.ErrMissingField(apis.CurrentField).ViaFieldIndex("parentPaths", i).ViaField("envelope"))
+			} else if err := eventsplitter.ValidateParentPath(p); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(err.Error(), apis.CurrentField).ViaFieldIndex("parentPaths", i).ViaField("envelope"))
+			}
+		}
+	}
+
+	return errs
+}
+
+// Validate implements apis.Validatable
+func (c *CloudEventContext) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	switch {
+	case c.Type == "":
+		errs = errs.Also(apis.ErrMissingField("type"))
+	case strings.IndexFunc(c.Type, isSpaceOrControl) != -1:
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("invalid value: %s", c.Type),
+			Paths:   []string{"type"},
+			Details: "Type must not contain whitespaces or control characters",
+		})
+	}
+
+	if c.Source == "" {
+		errs = errs.Also(apis.ErrMissingField("source"))
+	} else if _, err := url.Parse(c.Source); err != nil {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("invalid value: %s", c.Source),
+			Paths:   []string{"source"},
+			Details: "Source must be a URI-reference",
+		})
+	}
+
+	for name := range c.Extensions {
+		if err := validateExtensionName(name); err != nil {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "extensions", err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// validateExtensionName verifies that the given name is a valid CloudEvent
+// extension name which doesn't collide with the extensions set by routers.
+func validateExtensionName(name string) error {
+	if name == "" || len(name) > maxExtensionNameLength {
+		return fmt.Errorf("extension names must contain between 1 and %d characters", maxExtensionNameLength)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return fmt.Errorf("extension names must only contain lowercase ASCII letters and digits")
+		}
+	}
+	if _, reserved := reservedAttributeNames[name]; reserved {
+		return fmt.Errorf("%q is a reserved CloudEvent attribute", name)
+	}
+	switch name {
+	case SplitterExtensionParentID, SplitterExtensionIndex, SplitterExtensionTotal, SplitterExtensionLast:
+		return fmt.Errorf("%q is set by the Splitter", name)
+	}
+	return nil
+}
+
+// isSpaceOrControl returns whether the given rune is a whitespace or a
+// control character.
+func isSpaceOrControl(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsControl(r)
+}
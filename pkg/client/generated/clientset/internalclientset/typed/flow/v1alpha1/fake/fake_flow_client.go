@@ -28,14 +28,26 @@ type FakeFlowV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeFlowV1alpha1) Aggregators(namespace string) v1alpha1.AggregatorInterface {
+	return &FakeAggregators{c, namespace}
+}
+
 func (c *FakeFlowV1alpha1) Filters(namespace string) v1alpha1.FilterInterface {
 	return &FakeFilters{c, namespace}
 }
 
+func (c *FakeFlowV1alpha1) Routers(namespace string) v1alpha1.RouterInterface {
+	return &FakeRouters{c, namespace}
+}
+
 func (c *FakeFlowV1alpha1) Splitters(namespace string) v1alpha1.SplitterInterface {
 	return &FakeSplitters{c, namespace}
 }
 
+func (c *FakeFlowV1alpha1) Synchronizers(namespace string) v1alpha1.SynchronizerInterface {
+	return &FakeSynchronizers{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeFlowV1alpha1) RESTClient() rest.Interface {
@@ -26,8 +26,11 @@ import (
 
 type FlowV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	AggregatorsGetter
 	FiltersGetter
+	RoutersGetter
 	SplittersGetter
+	SynchronizersGetter
 }
 
 // FlowV1alpha1Client is used to interact with features provided by the flow.triggermesh.io group.
@@ -35,14 +38,26 @@ type FlowV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *FlowV1alpha1Client) Aggregators(namespace string) AggregatorInterface {
+	return newAggregators(c, namespace)
+}
+
 func (c *FlowV1alpha1Client) Filters(namespace string) FilterInterface {
 	return newFilters(c, namespace)
 }
 
+func (c *FlowV1alpha1Client) Routers(namespace string) RouterInterface {
+	return newRouters(c, namespace)
+}
+
 func (c *FlowV1alpha1Client) Splitters(namespace string) SplitterInterface {
 	return newSplitters(c, namespace)
 }
 
+func (c *FlowV1alpha1Client) Synchronizers(namespace string) SynchronizerInterface {
+	return newSynchronizers(c, namespace)
+}
+
 // NewForConfig creates a new FlowV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*FlowV1alpha1Client, error) {
 	config := *c
@@ -0,0 +1,195 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	scheme "github.com/triggermesh/routing/pkg/client/generated/clientset/internalclientset/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// RoutersGetter has a method to return a RouterInterface.
+// A group's client should implement this interface.
+type RoutersGetter interface {
+	Routers(namespace string) RouterInterface
+}
+
+// RouterInterface has methods to work with Router resources.
+type RouterInterface interface {
+	Create(ctx context.Context, router *v1alpha1.Router, opts v1.CreateOptions) (*v1alpha1.Router, error)
+	Update(ctx context.Context, router *v1alpha1.Router, opts v1.UpdateOptions) (*v1alpha1.Router, error)
+	UpdateStatus(ctx context.Context, router *v1alpha1.Router, opts v1.UpdateOptions) (*v1alpha1.Router, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.Router, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.RouterList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Router, err error)
+	RouterExpansion
+}
+
+// routers implements RouterInterface
+type routers struct {
+	client rest.Interface
+	ns     string
+}
+
+// newRouters returns a Routers
+func newRouters(c *FlowV1alpha1Client, namespace string) *routers {
+	return &routers{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the router, and returns the corresponding router object, and an error if there is any.
+func (c *routers) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Router, err error) {
+	result = &v1alpha1.Router{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("routers").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of Routers that match those selectors.
+func (c *routers) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RouterList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.RouterList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("routers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested routers.
+func (c *routers) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("routers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a router and creates it.  Returns the server's representation of the router, and an error, if there is any.
+func (c *routers) Create(ctx context.Context, router *v1alpha1.Router, opts v1.CreateOptions) (result *v1alpha1.Router, err error) {
+	result = &v1alpha1.Router{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("routers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(router).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a router and updates it. Returns the server's representation of the router, and an error, if there is any.
+func (c *routers) Update(ctx context.Context, router *v1alpha1.Router, opts v1.UpdateOptions) (result *v1alpha1.Router, err error) {
+	result = &v1alpha1.Router{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("routers").
+		Name(router.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(router).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *routers) UpdateStatus(ctx context.Context, router *v1alpha1.Router, opts v1.UpdateOptions) (result *v1alpha1.Router, err error) {
+	result = &v1alpha1.Router{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("routers").
+		Name(router.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(router).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the router and deletes it. Returns an error if one occurs.
+func (c *routers) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("routers").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *routers) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("routers").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched router.
+func (c *routers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Router, err error) {
+	result = &v1alpha1.Router{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("routers").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,195 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	scheme "github.com/triggermesh/routing/pkg/client/generated/clientset/internalclientset/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// SynchronizersGetter has a method to return a SynchronizerInterface.
+// A group's client should implement this interface.
+type SynchronizersGetter interface {
+	Synchronizers(namespace string) SynchronizerInterface
+}
+
+// SynchronizerInterface has methods to work with Synchronizer resources.
+type SynchronizerInterface interface {
+	Create(ctx context.Context, synchronizer *v1alpha1.Synchronizer, opts v1.CreateOptions) (*v1alpha1.Synchronizer, error)
+	Update(ctx context.Context, synchronizer *v1alpha1.Synchronizer, opts v1.UpdateOptions) (*v1alpha1.Synchronizer, error)
+	UpdateStatus(ctx context.Context, synchronizer *v1alpha1.Synchronizer, opts v1.UpdateOptions) (*v1alpha1.Synchronizer, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.Synchronizer, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.SynchronizerList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Synchronizer, err error)
+	SynchronizerExpansion
+}
+
+// synchronizers implements SynchronizerInterface
+type synchronizers struct {
+	client rest.Interface
+	ns     string
+}
+
+// newSynchronizers returns a Synchronizers
+func newSynchronizers(c *FlowV1alpha1Client, namespace string) *synchronizers {
+	return &synchronizers{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the synchronizer, and returns the corresponding synchronizer object, and an error if there is any.
+func (c *synchronizers) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Synchronizer, err error) {
+	result = &v1alpha1.Synchronizer{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("synchronizers").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of Synchronizers that match those selectors.
+func (c *synchronizers) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.SynchronizerList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.SynchronizerList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("synchronizers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested synchronizers.
+func (c *synchronizers) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("synchronizers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a synchronizer and creates it.  Returns the server's representation of the synchronizer, and an error, if there is any.
+func (c *synchronizers) Create(ctx context.Context, synchronizer *v1alpha1.Synchronizer, opts v1.CreateOptions) (result *v1alpha1.Synchronizer, err error) {
+	result = &v1alpha1.Synchronizer{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("synchronizers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(synchronizer).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a synchronizer and updates it. Returns the server's representation of the synchronizer, and an error, if there is any.
+func (c *synchronizers) Update(ctx context.Context, synchronizer *v1alpha1.Synchronizer, opts v1.UpdateOptions) (result *v1alpha1.Synchronizer, err error) {
+	result = &v1alpha1.Synchronizer{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("synchronizers").
+		Name(synchronizer.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(synchronizer).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *synchronizers) UpdateStatus(ctx context.Context, synchronizer *v1alpha1.Synchronizer, opts v1.UpdateOptions) (result *v1alpha1.Synchronizer, err error) {
+	result = &v1alpha1.Synchronizer{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("synchronizers").
+		Name(synchronizer.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(synchronizer).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the synchronizer and deletes it. Returns an error if one occurs.
+func (c *synchronizers) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("synchronizers").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *synchronizers) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("synchronizers").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched synchronizer.
+func (c *synchronizers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Synchronizer, err error) {
+	result = &v1alpha1.Synchronizer{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("synchronizers").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
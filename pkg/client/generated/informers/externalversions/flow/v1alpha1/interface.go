@@ -24,10 +24,16 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Aggregators returns a AggregatorInformer.
+	Aggregators() AggregatorInformer
 	// Filters returns a FilterInformer.
 	Filters() FilterInformer
+	// Routers returns a RouterInformer.
+	Routers() RouterInformer
 	// Splitters returns a SplitterInformer.
 	Splitters() SplitterInformer
+	// Synchronizers returns a SynchronizerInformer.
+	Synchronizers() SynchronizerInformer
 }
 
 type version struct {
@@ -41,12 +47,27 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Aggregators returns a AggregatorInformer.
+func (v *version) Aggregators() AggregatorInformer {
+	return &aggregatorInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Filters returns a FilterInformer.
 func (v *version) Filters() FilterInformer {
 	return &filterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// Routers returns a RouterInformer.
+func (v *version) Routers() RouterInformer {
+	return &routerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Splitters returns a SplitterInformer.
 func (v *version) Splitters() SplitterInformer {
 	return &splitterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// Synchronizers returns a SynchronizerInformer.
+func (v *version) Synchronizers() SynchronizerInformer {
+	return &synchronizerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -53,10 +53,16 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=flow.triggermesh.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("aggregators"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Flow().V1alpha1().Aggregators().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("filters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Flow().V1alpha1().Filters().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("routers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Flow().V1alpha1().Routers().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("splitters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Flow().V1alpha1().Splitters().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("synchronizers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Flow().V1alpha1().Synchronizers().Informer()}, nil
 
 	}
 
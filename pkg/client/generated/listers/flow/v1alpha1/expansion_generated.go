@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// AggregatorListerExpansion allows custom methods to be added to
+// AggregatorLister.
+type AggregatorListerExpansion interface{}
+
+// AggregatorNamespaceListerExpansion allows custom methods to be added to
+// AggregatorNamespaceLister.
+type AggregatorNamespaceListerExpansion interface{}
+
 // FilterListerExpansion allows custom methods to be added to
 // FilterLister.
 type FilterListerExpansion interface{}
@@ -26,6 +34,14 @@ type FilterListerExpansion interface{}
 // FilterNamespaceLister.
 type FilterNamespaceListerExpansion interface{}
 
+// RouterListerExpansion allows custom methods to be added to
+// RouterLister.
+type RouterListerExpansion interface{}
+
+// RouterNamespaceListerExpansion allows custom methods to be added to
+// RouterNamespaceLister.
+type RouterNamespaceListerExpansion interface{}
+
 // SplitterListerExpansion allows custom methods to be added to
 // SplitterLister.
 type SplitterListerExpansion interface{}
@@ -33,3 +49,11 @@ type SplitterListerExpansion interface{}
 // SplitterNamespaceListerExpansion allows custom methods to be added to
 // SplitterNamespaceLister.
 type SplitterNamespaceListerExpansion interface{}
+
+// SynchronizerListerExpansion allows custom methods to be added to
+// SynchronizerLister.
+type SynchronizerListerExpansion interface{}
+
+// SynchronizerNamespaceListerExpansion allows custom methods to be added to
+// SynchronizerNamespaceLister.
+type SynchronizerNamespaceListerExpansion interface{}
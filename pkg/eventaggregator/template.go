@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventaggregator renders the events which aggregate batches of
+// correlated events.
+package eventaggregator
+
+import (
+	"encoding/json"
+	"text/template"
+)
+
+// FuncMap contains the functions available to output templates, in addition
+// to the predefined functions of the text/template package.
+var FuncMap = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ParseTemplate parses the given output template.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(FuncMap).Parse(text)
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
@@ -29,14 +30,25 @@ import (
 
 var errVarType = errors.New("variable type definition doesn't match expected format: \"$foo.(string)\"")
 
+// ceVariable is the name of the CEL variable which holds the context
+// attributes of the evaluated CloudEvent, e.g. 'ce.type == "foo"'.
+const ceVariable = "ce"
+
 // CompileExpression accepts the expression string from the Filter spec,
 // parses variables and their types, compiles expression into CEL Program
 func CompileExpression(expression string) (ConditionalFilter, error) {
-	expr, vars, err := parseExpressionString(expression)
+	expr, vars, err := ParseExpression(expression)
 	if err != nil {
 		return ConditionalFilter{}, err
 	}
-	prog, err := newCEL(expr, vars)
+	return CompileExpressionWithVariables(expr, vars)
+}
+
+// CompileExpressionWithVariables compiles a CEL expression which references
+// the given variables, declared separately from the expression instead of
+// inline.
+func CompileExpressionWithVariables(expression string, vars []Variable) (ConditionalFilter, error) {
+	prog, err := newCEL(expression, vars)
 	if err != nil {
 		return ConditionalFilter{}, err
 	}
@@ -46,11 +58,52 @@ func CompileExpression(expression string) (ConditionalFilter, error) {
 	}, nil
 }
 
-// parseExpressionString breaks inline expression string into Google CEL expression
+// NormalizeExpression returns the given expression without leading and
+// trailing whitespaces, and with sequences of whitespaces outside of string
+// literals (e.g. line breaks of YAML block scalars) collapsed into a single
+// space.
+func NormalizeExpression(expression string) string {
+	var b strings.Builder
+	b.Grow(len(expression))
+
+	var quote rune
+	var escaped, space bool
+
+	for _, r := range strings.TrimSpace(expression) {
+		switch {
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+
+		case unicode.IsSpace(r):
+			space = true
+			continue
+
+		case r == '"' || r == '\'':
+			quote = r
+		}
+
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// ParseExpression breaks inline expression string into Google CEL expression
 // and a set of variable definitions, e.g.:
 // '$foo.(string) == "bar"' becomes
-// expr: foo == "bar", vars: ["foo": string]
-func parseExpressionString(expression string) (string, []Variable, error) {
+// expr: var_foo == "bar", vars: ["var_foo": string]
+func ParseExpression(expression string) (string, []Variable, error) {
 	var vars []Variable
 	var cleanExpr string
 
@@ -79,45 +132,191 @@ func parseExpressionString(expression string) (string, []Variable, error) {
 		i = end
 		cleanExpr += expression[:variable]
 
-		safeCELName := strings.ReplaceAll(expression[variable+1:typ], ".", "_")
+		// characters which are not allowed in CEL identifiers, such as
+		// the separators and wildcards of GJSON paths, are replaced.
+		safeCELName := strings.Map(func(r rune) rune {
+			if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '_'
+		}, expression[variable+1:typ])
 		// integer as the variable name first symbol causes issue with matching
 		// var types. String prefix ensures that we don't have first integer symbol.
 		safeCELName = "var_" + safeCELName
 
-		vars = append(vars, Variable{
+		v := Variable{
 			Name: safeCELName,
 			Path: expression[variable+1 : typ],
 			Type: expression[typ+2 : end],
-		})
+		}
+
+		// variables may be referenced multiple times, but each of them
+		// can only be declared once
+		declared := false
+		for _, dv := range vars {
+			if dv.Name != v.Name {
+				continue
+			}
+			if dv.Path != v.Path || dv.Type != v.Type {
+				return "", []Variable{}, fmt.Errorf("conflicting definitions of variable %q", "$"+v.Path)
+			}
+			declared = true
+		}
+		if !declared {
+			vars = append(vars, v)
+		}
 		cleanExpr += safeCELName
 	}
 	return cleanExpr, vars, nil
 }
 
+// InlineVariables is the inverse of ParseExpression. It replaces the
+// references to the given variables inside a CEL expression with their
+// inline definition, e.g.:
+// expr: var_foo == "bar", vars: ["var_foo": string] becomes
+// '$foo.(string) == "bar"'
+func InlineVariables(expression string, vars []Variable) string {
+	if len(vars) == 0 {
+		return expression
+	}
+
+	byName := make(map[string]Variable, len(vars))
+	for _, v := range vars {
+		byName[v.Name] = v
+	}
+
+	var b strings.Builder
+	b.Grow(len(expression))
+
+	for i := 0; i < len(expression); {
+		c := expression[i]
+
+		switch {
+		case c == '"' || c == '\'':
+			end := i + 1
+			for ; end < len(expression) && expression[end] != c; end++ {
+				if expression[end] == '\\' {
+					end++
+				}
+			}
+			if end < len(expression) {
+				end++
+			}
+			b.WriteString(expression[i:end])
+			i = end
+
+		case isIdentStart(c):
+			end := i + 1
+			for end < len(expression) && isIdentChar(expression[end]) {
+				end++
+			}
+			ident := expression[i:end]
+
+			// identifiers which follow a '.' are field selections
+			// rather than variable references, e.g. 'ce.type'
+			v, isVar := byName[ident]
+			if isVar && !isSelection(expression[:i]) {
+				b.WriteString("$" + v.Path + ".(" + v.Type + ")")
+			} else {
+				b.WriteString(ident)
+			}
+			i = end
+
+		case c >= '0' && c <= '9':
+			// numeric literals may contain letters, e.g. '0x1F' or '1u'
+			end := i + 1
+			for end < len(expression) && isIdentChar(expression[end]) {
+				end++
+			}
+			b.WriteString(expression[i:end])
+			i = end
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// isIdentStart returns whether c can start a CEL identifier.
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isIdentChar returns whether c can be part of a CEL identifier.
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+// isSelection returns whether an identifier preceded by the given
+// expression is a field selection.
+func isSelection(preceding string) bool {
+	preceding = strings.TrimRightFunc(preceding, unicode.IsSpace)
+	return strings.HasSuffix(preceding, ".")
+}
+
 // newCEL creates CEL env, sets its variables, compiles expression string
 // and validates expression result type
 func newCEL(expr string, vars []Variable) (cel.Program, error) {
-	declVars := []*exprpb.Decl{}
+	env, ast, err := compile(expr, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if !proto.Equal(ast.ResultType(), decls.Bool) {
+		return nil, fmt.Errorf("expression %q must return bool type, got %s", expr, ast.ResultType().String())
+	}
+
+	return env.Program(ast)
+}
+
+// compile creates a CEL env which declares the given variables, and compiles
+// the given expression string in this env.
+func compile(expr string, vars []Variable) (*cel.Env, *cel.Ast, error) {
+	declVars := []*exprpb.Decl{
+		decls.NewVar(ceVariable, decls.NewMapType(decls.String, decls.Dyn)),
+	}
 	for _, variable := range vars {
-		primitiveType := exprpb.Type_PrimitiveType(exprpb.Type_PrimitiveType_value[strings.ToUpper(variable.Type)])
-		declVars = append(declVars, decls.NewVar(variable.Name, decls.NewPrimitiveType(primitiveType)))
+		typ, err := declType(variable.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		declVars = append(declVars, decls.NewVar(variable.Name, typ))
 	}
 
 	env, err := cel.NewEnv(
 		cel.Declarations(declVars...),
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	ast, iss := env.Compile(expr)
 	if iss.Err() != nil {
-		return nil, iss.Err()
+		return nil, nil, iss.Err()
 	}
 
-	if !proto.Equal(ast.ResultType(), decls.Bool) {
-		return nil, fmt.Errorf("expression %q must return bool type, got %s", expr, ast.ResultType().String())
+	return env, ast, nil
+}
+
+// declType returns the CEL type matching the given variable type.
+func declType(typ string) (*exprpb.Type, error) {
+	switch typ {
+	case "list":
+		return decls.NewListType(decls.Dyn), nil
+	case "map":
+		return decls.NewMapType(decls.String, decls.Dyn), nil
+	case "timestamp":
+		return decls.Timestamp, nil
+	case "duration":
+		return decls.Duration, nil
 	}
 
-	return env.Program(ast)
+	primitiveType, ok := exprpb.Type_PrimitiveType_value[strings.ToUpper(typ)]
+	if !ok || primitiveType == int32(exprpb.Type_PRIMITIVE_TYPE_UNSPECIFIED) {
+		return nil, fmt.Errorf("unsupported variable type %q", typ)
+	}
+	return decls.NewPrimitiveType(exprpb.Type_PrimitiveType(primitiveType)), nil
 }
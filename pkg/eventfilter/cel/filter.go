@@ -18,10 +18,16 @@ package cel
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
 	"github.com/google/cel-go/cel"
 	"github.com/tidwall/gjson"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/triggermesh/routing/pkg/eventfilter"
 )
@@ -43,34 +49,217 @@ type Variable struct {
 
 // Filter parses Event payload values defined as the expression variables, asserts their types,
 // and executes CEL Program. If expression result is true, Event passes the filter.
+// Events for which the evaluation of the expression fails, including events
+// with payload values which can't be converted to the type of their variable,
+// do not pass the filter. Router adapters use Evaluate instead, in order to
+// report such errors.
 func (c *ConditionalFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
-	vars := make(map[string]interface{})
+	res, _ := c.Evaluate(ctx, event)
+	return res
+}
+
+// Evaluate behaves like Filter, but additionally returns the error which
+// occurred during the evaluation of the expression, if any.
+func (c *ConditionalFilter) Evaluate(ctx context.Context, event cloudevents.Event) (eventfilter.FilterResult, error) {
+	vals, err := variableValues(c.Variables, event)
+	if err != nil {
+		return eventfilter.FailFilter, err
+	}
+
+	pass, err := eval(*c.Expression, vals)
+	if err != nil {
+		return eventfilter.FailFilter, err
+	}
+	if pass {
+		return eventfilter.PassFilter, nil
+	}
+
+	return eventfilter.FailFilter, nil
+}
+
+// variableValues returns the values of the given variables, parsed from the
+// payload of the given Event, along with the Event's context attributes.
+//
+// Variables which are absent from the payload take the zero value of their
+// type. An error is returned if a value which is present can't be converted
+// to the type of its variable.
+func variableValues(vars []Variable, event cloudevents.Event) (map[string]interface{}, error) {
+	values := map[string]interface{}{
+		ceVariable: contextAttributes(event),
+	}
+
+	for _, v := range vars {
+		res := gjson.GetBytes(event.Data(), v.Path)
+
+		var err error
 
-	for _, v := range c.Variables {
 		switch v.Type {
 		case "bool":
-			vars[v.Name] = gjson.GetBytes(event.Data(), v.Path).Bool()
+			values[v.Name] = res.Bool()
 		case "int64":
-			vars[v.Name] = gjson.GetBytes(event.Data(), v.Path).Int()
+			values[v.Name] = res.Int()
 		case "uint64":
-			vars[v.Name] = gjson.GetBytes(event.Data(), v.Path).Uint()
+			values[v.Name] = res.Uint()
 		case "double":
-			vars[v.Name] = gjson.GetBytes(event.Data(), v.Path).Float()
+			values[v.Name] = res.Float()
 		case "string":
-			vars[v.Name] = gjson.GetBytes(event.Data(), v.Path).String()
+			values[v.Name] = res.String()
+		case "bytes":
+			values[v.Name], err = bytesValue(res)
+		case "timestamp":
+			values[v.Name], err = timestampValue(res)
+		case "duration":
+			values[v.Name], err = durationValue(res)
+		case "list":
+			values[v.Name], err = listValue(res)
+		case "map":
+			values[v.Name], err = mapValue(res)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("converting the value at path %q to %s: %w", v.Path, v.Type, err)
+		}
+	}
+
+	return values, nil
+}
+
+// jsonValue converts the given GJSON result to a value which can be used in
+// CEL expressions. Integral numbers are converted to int64 so that they can
+// be compared with integer literals.
+func jsonValue(res gjson.Result) interface{} {
+	switch {
+	case res.IsArray():
+		elems := res.Array()
+		list := make([]interface{}, len(elems))
+		for i := range elems {
+			list[i] = jsonValue(elems[i])
+		}
+		return list
+
+	case res.IsObject():
+		m := make(map[string]interface{})
+		res.ForEach(func(key, value gjson.Result) bool {
+			m[key.String()] = jsonValue(value)
+			return true
+		})
+		return m
+	}
+
+	switch res.Type {
+	case gjson.Number:
+		if !strings.ContainsAny(res.Raw, ".eE") {
+			return res.Int()
+		}
+		return res.Float()
+	case gjson.String:
+		return res.String()
+	case gjson.True, gjson.False:
+		return res.Bool()
+	default:
+		return structpb.NullValue_NULL_VALUE
+	}
+}
+
+// bytesValue returns the bytes of a base64 encoded string.
+func bytesValue(res gjson.Result) ([]byte, error) {
+	if !res.Exists() {
+		return []byte{}, nil
+	}
+	if res.Type != gjson.String {
+		return nil, fmt.Errorf("expected a base64 encoded string, got %s", res.Type)
+	}
+	return base64.StdEncoding.DecodeString(res.String())
+}
+
+// timestampValue parses a RFC 3339 timestamp string.
+func timestampValue(res gjson.Result) (time.Time, error) {
+	if !res.Exists() {
+		return time.Time{}, nil
+	}
+	if res.Type != gjson.String {
+		return time.Time{}, fmt.Errorf("expected a RFC 3339 timestamp string, got %s", res.Type)
+	}
+	return time.Parse(time.RFC3339Nano, res.String())
+}
+
+// durationValue parses a duration string, e.g. "1h30m" or "90s".
+func durationValue(res gjson.Result) (time.Duration, error) {
+	if !res.Exists() {
+		return 0, nil
+	}
+	if res.Type != gjson.String {
+		return 0, fmt.Errorf("expected a duration string, got %s", res.Type)
+	}
+	return time.ParseDuration(res.String())
+}
+
+// listValue converts a JSON array to a list.
+func listValue(res gjson.Result) ([]interface{}, error) {
+	if !res.Exists() {
+		return []interface{}{}, nil
+	}
+	if !res.IsArray() {
+		return nil, fmt.Errorf("expected an array, got %s", res.Type)
+	}
+	return jsonValue(res).([]interface{}), nil
+}
+
+// mapValue converts a JSON object to a map.
+func mapValue(res gjson.Result) (map[string]interface{}, error) {
+	if !res.Exists() {
+		return map[string]interface{}{}, nil
+	}
+	if !res.IsObject() {
+		return nil, fmt.Errorf("expected an object, got %s", res.Type)
+	}
+	return jsonValue(res).(map[string]interface{}), nil
+}
+
+// contextAttributes returns the context attributes of the given Event in a
+// format suitable for usage as a CEL map. Optional string attributes are always
+// set, so that they can be compared without checking their presence first.
+func contextAttributes(event cloudevents.Event) map[string]interface{} {
+	extensions := make(map[string]interface{}, len(event.Extensions()))
+	for name, value := range event.Extensions() {
+		switch v := value.(type) {
+		case string, bool, int32:
+			extensions[name] = v
+		default:
+			if str, err := cetypes.Format(v); err == nil {
+				extensions[name] = str
+			}
 		}
 	}
 
-	pass, err := eval(*c.Expression, vars)
-	if err != nil || pass {
-		return eventfilter.PassFilter
+	attrs := map[string]interface{}{
+		"specversion":     event.SpecVersion(),
+		"id":              event.ID(),
+		"type":            event.Type(),
+		"source":          event.Source(),
+		"subject":         event.Subject(),
+		"datacontenttype": event.DataContentType(),
+		"dataschema":      event.DataSchema(),
+		"extensions":      extensions,
+	}
+
+	if t := event.Time(); !t.IsZero() {
+		attrs["time"] = t
 	}
 
-	return eventfilter.FailFilter
+	return attrs
 }
 
 // eval evaluates precompiled Expression with passed variables
 func eval(program cel.Program, vars map[string]interface{}) (bool, error) {
 	out, _, err := program.Eval(vars)
-	return out.Value().(bool), err
+	if err != nil {
+		return false, err
+	}
+
+	pass, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned a non-bool value of type %s", out.Type().TypeName())
+	}
+	return pass, nil
 }
@@ -0,0 +1,274 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/triggermesh/routing/pkg/eventfilter"
+)
+
+func TestFilter(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("0001")
+	event.SetType("io.triggermesh.test")
+	event.SetSource("test/source")
+	event.SetSubject("orders")
+	event.SetTime(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	event.SetExtension("tenant", "acme")
+	event.SetExtension("priority", 3)
+	err := event.SetData(cloudevents.ApplicationJSON, []byte(`{"id":5,"name":"foo",`+
+		`"tags":["dev","prod"],"items":[{"qty":1},{"qty":4}],"labels":{"team":"a"},`+
+		`"created":"2021-05-01T10:00:00Z","ttl":"90s","sig":"aGVsbG8="}`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		expression string
+		expect     eventfilter.FilterResult
+	}{
+		{
+			name:       "Payload variable",
+			expression: `$id.(int64) == 5`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "Context attribute",
+			expression: `ce.type == "io.triggermesh.test" && ce.subject == "orders"`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "Context attribute mismatch",
+			expression: `ce.source == "other/source"`,
+			expect:     eventfilter.FailFilter,
+		},
+		{
+			name:       "Unset optional attribute",
+			expression: `ce.dataschema == ""`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "Time attribute",
+			expression: `ce.time > timestamp("2021-01-01T00:00:00Z")`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "Extensions",
+			expression: `ce.extensions["tenant"] == "acme" && ce.extensions.priority > 2`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "Payload and context attributes",
+			expression: `$name.(string) == "foo" && ce.extensions["tenant"] == "other"`,
+			expect:     eventfilter.FailFilter,
+		},
+		{
+			name:       "List macro",
+			expression: `$tags.(list).exists(t, t == "prod")`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "List size",
+			expression: `size($items.(list)) > 3`,
+			expect:     eventfilter.FailFilter,
+		},
+		{
+			name:       "List of objects",
+			expression: `$items.(list).all(i, i.qty > 0) && $items.(list).filter(i, i.qty > 2).size() == 1`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "GJSON query",
+			expression: `$items.#.qty.(list).map(q, q * 2) == [2, 8]`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "Map",
+			expression: `$labels.(map).team == "a" && !("owner" in $labels.(map))`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "Timestamp and duration",
+			expression: `$created.(timestamp) + $ttl.(duration) == timestamp("2021-05-01T10:01:30Z")`,
+			expect:     eventfilter.PassFilter,
+		},
+		{
+			name:       "Bytes",
+			expression: `$sig.(bytes) == b"hello"`,
+			expect:     eventfilter.PassFilter,
+		},
+	}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			cond, err := CompileExpression(tc.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expect, cond.Filter(context.Background(), event))
+		})
+	}
+}
+
+func TestCompileExpressionUnsupportedType(t *testing.T) {
+	_, err := CompileExpression(`$id.(integer) == 5`)
+	assert.EqualError(t, err, `unsupported variable type "integer"`)
+}
+
+func TestEvaluateError(t *testing.T) {
+	event := cloudevents.NewEvent()
+	err := event.SetData(cloudevents.ApplicationJSON, []byte(`{"tags":["dev"]}`))
+	require.NoError(t, err)
+
+	cond, err := CompileExpression(`$tags.(list)[3] == "prod"`)
+	require.NoError(t, err)
+
+	res, err := cond.Evaluate(context.Background(), event)
+	assert.Error(t, err)
+	assert.Equal(t, eventfilter.FailFilter, res)
+
+	assert.Equal(t, eventfilter.FailFilter, cond.Filter(context.Background(), event))
+}
+
+func TestVariableConversionError(t *testing.T) {
+	event := cloudevents.NewEvent()
+	err := event.SetData(cloudevents.ApplicationJSON, []byte(`{"raw":"not base64!","word":"test",`+
+		`"created":"yesterday","ttl":"soon","count":3,"tags":"dev","labels":["a"]}`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		expression string
+		expectErr  string
+	}{
+		{
+			name:       "Invalid base64",
+			expression: `size($raw.(bytes)) > 0`,
+			expectErr:  `converting the value at path "raw" to bytes`,
+		},
+		{
+			name:       "Bytes from a non-string value",
+			expression: `size($count.(bytes)) > 0`,
+			expectErr:  `converting the value at path "count" to bytes: expected a base64 encoded string`,
+		},
+		{
+			name:       "Invalid timestamp",
+			expression: `$created.(timestamp) > timestamp("2021-01-01T00:00:00Z")`,
+			expectErr:  `converting the value at path "created" to timestamp`,
+		},
+		{
+			name:       "Invalid duration",
+			expression: `$ttl.(duration) > duration("1s")`,
+			expectErr:  `converting the value at path "ttl" to duration`,
+		},
+		{
+			name:       "List type mismatch",
+			expression: `size($tags.(list)) == 0`,
+			expectErr:  `converting the value at path "tags" to list: expected an array, got String`,
+		},
+		{
+			name:       "Map type mismatch",
+			expression: `size($labels.(map)) == 0`,
+			expectErr:  `converting the value at path "labels" to map: expected an object`,
+		},
+	}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			cond, err := CompileExpression(tc.expression)
+			require.NoError(t, err)
+
+			res, err := cond.Evaluate(context.Background(), event)
+			assert.Equal(t, eventfilter.FailFilter, res)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expectErr)
+			}
+
+			val, err := CompileValueExpression(tc.expression)
+			require.NoError(t, err)
+
+			_, err = val.Evaluate(context.Background(), event)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVariableValues(t *testing.T) {
+	event := cloudevents.NewEvent()
+	err := event.SetData(cloudevents.ApplicationJSON, []byte(`{"word":"test"}`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		expression string
+	}{
+		{
+			name:       "Base64 string is decoded",
+			expression: `$word.(bytes) == b"\xb5\xeb\x2d"`,
+		},
+		{
+			name: "Absent values take the zero value of their type",
+			expression: `size($b.(bytes)) == 0 && size($l.(list)) == 0 && size($m.(map)) == 0 && ` +
+				`$d.(duration) == duration("0s") && $t.(timestamp) == timestamp("0001-01-01T00:00:00Z")`,
+		},
+	}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			cond, err := CompileExpression(tc.expression)
+			require.NoError(t, err)
+
+			res, err := cond.Evaluate(context.Background(), event)
+			assert.NoError(t, err)
+			assert.Equal(t, eventfilter.PassFilter, res)
+		})
+	}
+}
+
+func TestNormalizeExpression(t *testing.T) {
+	testCases := map[string]string{
+		`$id.(int64) == 5`:                          `$id.(int64) == 5`,
+		"  $id.(int64)  ==\n\t5 \n":                 `$id.(int64) == 5`,
+		"ce.type ==  \"a  b\" &&\n ce.id == 'c\td'": "ce.type == \"a  b\" && ce.id == 'c\td'",
+		`ce.subject == "say \"a  b\""  `:            `ce.subject == "say \"a  b\""`,
+	}
+
+	for in, expect := range testCases {
+		assert.Equal(t, expect, NormalizeExpression(in))
+	}
+}
+
+func TestInlineVariables(t *testing.T) {
+	const inline = `$id.(int64) == 0x1F && ce.id != "var_id" && $items.0.qty.(int64) > 1`
+
+	expr, vars, err := ParseExpression(inline)
+	require.NoError(t, err)
+	assert.Equal(t, `var_id == 0x1F && ce.id != "var_id" && var_items_0_qty > 1`, expr)
+
+	assert.Equal(t, inline, InlineVariables(expr, vars))
+
+	_, err = CompileExpressionWithVariables(expr, vars)
+	assert.NoError(t, err)
+}
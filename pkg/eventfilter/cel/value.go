@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/cel-go/cel"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// jsonValueType is the type CEL values are converted to in order to be
+// represented in JSON.
+var jsonValueType = reflect.TypeOf(&structpb.Value{})
+
+// ValueExpression holds a CEL Program which computes a value from the
+// attributes and payload of an Event, along with its variable definitions.
+type ValueExpression struct {
+	Expression *cel.Program
+	Variables  []Variable
+}
+
+// CompileValueExpression accepts an expression string with the same syntax as
+// Filter expressions, and compiles it into a CEL Program which may return a
+// value of any type.
+func CompileValueExpression(expression string) (ValueExpression, error) {
+	expr, vars, err := ParseExpression(expression)
+	if err != nil {
+		return ValueExpression{}, err
+	}
+	return CompileValueExpressionWithVariables(expr, vars)
+}
+
+// CompileValueExpressionWithVariables compiles a CEL expression which may
+// return a value of any type, and references the given variables, declared
+// separately from the expression instead of inline.
+func CompileValueExpressionWithVariables(expression string, vars []Variable) (ValueExpression, error) {
+	env, ast, err := compile(expression, vars)
+	if err != nil {
+		return ValueExpression{}, err
+	}
+
+	prog, err := env.Program(ast)
+	if err != nil {
+		return ValueExpression{}, err
+	}
+
+	return ValueExpression{
+		Expression: &prog,
+		Variables:  vars,
+	}, nil
+}
+
+// Evaluate executes the CEL Program against the given Event and returns its
+// result as a value which can be encoded to JSON, i.e. nil, a bool, a float64,
+// a string, a []interface{} or a map[string]interface{}.
+func (v *ValueExpression) Evaluate(ctx context.Context, event cloudevents.Event) (interface{}, error) {
+	vals, err := variableValues(v.Variables, event)
+	if err != nil {
+		return nil, err
+	}
+
+	out, _, err := (*v.Expression).Eval(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := out.ConvertToNative(jsonValueType)
+	if err != nil {
+		return nil, fmt.Errorf("expression returned a value of type %s which can not be represented in JSON: %w",
+			out.Type().TypeName(), err)
+	}
+
+	return val.(*structpb.Value).AsInterface(), nil
+}
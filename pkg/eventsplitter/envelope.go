@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsplitter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Envelope describes how parent data is carried over to split items.
+type Envelope struct {
+	// Wrap wraps each item as
+	// {"parent": <parent>, "item": <item>, "index": <i>, "total": <n>}.
+	Wrap bool
+	// ParentPaths are the GJSON paths of the parent fields to copy.
+	// When Wrap is true and ParentPaths is empty, the whole parent is
+	// copied. Paths must describe a single location (see
+	// ValidateParentPath).
+	ParentPaths []string
+}
+
+// IsEmpty returns whether the envelope leaves items unchanged.
+func (e Envelope) IsEmpty() bool {
+	return !e.Wrap && len(e.ParentPaths) == 0
+}
+
+// Apply returns the given items enveloped with data from the parent.
+//
+// Without Wrap, the parent fields are merged into object items, at the
+// location described by their path (e.g. "order.id" is copied to
+// {"order": {"id": ...}}). Items which aren't objects are set to the "item"
+// field of a new object along with the parent fields.
+func (e Envelope) Apply(parent []byte, items []json.RawMessage) ([]json.RawMessage, error) {
+	if e.IsEmpty() {
+		return items, nil
+	}
+
+	parentFields := make(map[string]interface{})
+	for _, p := range e.ParentPaths {
+		keys, err := parentPathKeys(p)
+		if err != nil {
+			return nil, err
+		}
+		val := gjson.GetBytes(parent, p)
+		if !val.Exists() {
+			continue
+		}
+		setNested(parentFields, keys, json.RawMessage(val.Raw))
+	}
+
+	res := make([]json.RawMessage, len(items))
+
+	for i, item := range items {
+		var out interface{}
+
+		switch {
+		case e.Wrap:
+			var p interface{} = parentFields
+			if len(e.ParentPaths) == 0 {
+				p = json.RawMessage(parent)
+			}
+			out = map[string]interface{}{
+				"parent": p,
+				"item":   item,
+				"index":  i,
+				"total":  len(items),
+			}
+
+		default:
+			obj := make(map[string]interface{})
+			if gjson.ParseBytes(item).IsObject() {
+				// numbers are decoded as json.Number to be encoded back
+				// verbatim, without a loss of precision
+				dec := json.NewDecoder(bytes.NewReader(item))
+				dec.UseNumber()
+				if err := dec.Decode(&obj); err != nil {
+					return nil, fmt.Errorf("decoding item %d: %w", i, err)
+				}
+			} else {
+				obj["item"] = item
+			}
+			mergeFields(obj, parentFields)
+			out = obj
+		}
+
+		b, err := json.Marshal(out)
+		if err != nil {
+			return nil, fmt.Errorf("encoding item %d: %w", i, err)
+		}
+		res[i] = b
+	}
+
+	return res, nil
+}
+
+// gjsonSpecialChars are the characters which have a special meaning in GJSON
+// paths, besides the "." separator and the backslash escape character.
+const gjsonSpecialChars = "*?#@|!{}[]"
+
+// ValidateParentPath verifies that the given GJSON path of a parent field
+// describes a single location. Wildcards, queries, modifiers and multipaths
+// are rejected, unless their characters are escaped with a backslash.
+func ValidateParentPath(path string) error {
+	_, err := parentPathKeys(path)
+	return err
+}
+
+// parentPathKeys returns the unescaped keys of the given GJSON path of a
+// parent field.
+func parentPathKeys(path string) ([]string, error) {
+	var keys []string
+	var key strings.Builder
+	escaped := false
+
+	for _, r := range path {
+		switch {
+		case escaped:
+			key.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '.':
+			if key.Len() == 0 {
+				return nil, fmt.Errorf("empty key in parent path %q", path)
+			}
+			keys = append(keys, key.String())
+			key.Reset()
+		case strings.ContainsRune(gjsonSpecialChars, r):
+			return nil, fmt.Errorf("parent path %q contains the unescaped character %q, "+
+				"which doesn't describe a single location", path, r)
+		default:
+			key.WriteRune(r)
+		}
+	}
+
+	switch {
+	case escaped:
+		return nil, fmt.Errorf("parent path %q ends with an escape character", path)
+	case key.Len() == 0:
+		return nil, fmt.Errorf("empty key in parent path %q", path)
+	}
+
+	return append(keys, key.String()), nil
+}
+
+// setNested sets the value at the location described by keys inside m,
+// creating intermediate objects as needed.
+func setNested(m map[string]interface{}, keys []string, val interface{}) {
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[k] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = val
+}
+
+// mergeFields recursively merges the given fields into obj. Fields of obj
+// which are not objects are overwritten.
+func mergeFields(obj, fields map[string]interface{}) {
+	for k, v := range fields {
+		nested, ok := v.(map[string]interface{})
+		if !ok {
+			obj[k] = v
+			continue
+		}
+
+		existing, ok := obj[k].(map[string]interface{})
+		if !ok {
+			existing = make(map[string]interface{})
+			obj[k] = existing
+		}
+		mergeFields(existing, nested)
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventsplitter splits JSON payloads into multiple items.
+package eventsplitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Supported path syntaxes
+const (
+	// SyntaxGJSON is the GJSON path syntax (https://github.com/tidwall/gjson).
+	SyntaxGJSON = "gjson"
+	// SyntaxJSONPointer is the RFC 6901 JSON Pointer syntax.
+	SyntaxJSONPointer = "jsonpointer"
+	// SyntaxJSONPath is the Kubernetes flavour of the JSONPath syntax.
+	SyntaxJSONPath = "jsonpath"
+)
+
+// Split returns the items of the JSON value found at the given path inside
+// data. Arrays are split into their elements, and objects are split into
+// {"key": <key>, "value": <value>} pairs. An empty path refers to the root of
+// the JSON document, and an empty syntax defaults to GJSON.
+//
+// Each GJSON query ('#') inside a path nests the matched arrays one level
+// deeper, so elements are flattened accordingly, e.g. the path
+// "orders.#.lines" returns the lines of all orders.
+func Split(data []byte, path, syntax string) ([]json.RawMessage, error) {
+	switch syntax {
+	case "", SyntaxGJSON:
+		return splitGJSON(data, path), nil
+
+	case SyntaxJSONPointer:
+		gjsonPath, err := jsonPointerToGJSON(path)
+		if err != nil {
+			return nil, err
+		}
+		return splitGJSON(data, gjsonPath), nil
+
+	case SyntaxJSONPath:
+		return splitJSONPath(data, path)
+	}
+
+	return nil, fmt.Errorf("unsupported path syntax %q", syntax)
+}
+
+// ValidatePath returns an error if the given path isn't valid in the given
+// syntax.
+func ValidatePath(path, syntax string) error {
+	switch syntax {
+	case "", SyntaxGJSON:
+		if strings.Count(path, "[")+strings.Count(path, "{") != strings.Count(path, "]")+strings.Count(path, "}") {
+			return fmt.Errorf("unbalanced brackets in GJSON path %q", path)
+		}
+		return nil
+
+	case SyntaxJSONPointer:
+		_, err := jsonPointerToGJSON(path)
+		return err
+
+	case SyntaxJSONPath:
+		_, err := parseJSONPath(path)
+		return err
+	}
+
+	return fmt.Errorf("unsupported path syntax %q", syntax)
+}
+
+// splitGJSON splits the value found at the given GJSON path.
+func splitGJSON(data []byte, path string) []json.RawMessage {
+	val := gjson.ParseBytes(data)
+	if path != "" {
+		val = val.Get(path)
+	}
+
+	depth := strings.Count(path, "#")
+
+	var items []json.RawMessage
+	if val.IsObject() && depth == 0 {
+		val.ForEach(func(key, value gjson.Result) bool {
+			items = append(items, keyValue(key.Raw, value.Raw))
+			return true
+		})
+		return items
+	}
+
+	return flatten(val, depth, items)
+}
+
+// flatten appends the elements of the given array to items, recursing into
+// nested arrays up to the given depth.
+func flatten(val gjson.Result, depth int, items []json.RawMessage) []json.RawMessage {
+	if !val.IsArray() {
+		return items
+	}
+
+	for _, elem := range val.Array() {
+		if depth > 0 && elem.IsArray() {
+			items = flatten(elem, depth-1, items)
+			continue
+		}
+		items = append(items, json.RawMessage(elem.Raw))
+	}
+
+	return items
+}
+
+// jsonPointerToGJSON converts a RFC 6901 JSON Pointer to a GJSON path.
+func jsonPointerToGJSON(pointer string) (string, error) {
+	if pointer == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return "", fmt.Errorf("JSON Pointer %q must start with a '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		t = strings.ReplaceAll(t, "~1", "/")
+		t = strings.ReplaceAll(t, "~0", "~")
+		tokens[i] = escapeGJSON(t)
+	}
+
+	return strings.Join(tokens, "."), nil
+}
+
+// escapeGJSON escapes the characters which have a special meaning in GJSON
+// paths.
+func escapeGJSON(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '.', '*', '?', '#', '|', '@', '\\', '!', '=', '<', '>', '%', '[', ']', '{', '}', '(', ')', ',', ':', '"':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// splitJSONPath splits the value(s) found at the given JSONPath.
+func splitJSONPath(data []byte, path string) ([]json.RawMessage, error) {
+	jp, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decoding JSON data: %w", err)
+	}
+
+	results, err := jp.FindResults(doc)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating JSONPath %q: %w", path, err)
+	}
+
+	var vals []interface{}
+	for _, res := range results {
+		for _, v := range res {
+			if v.IsValid() && v.CanInterface() {
+				vals = append(vals, v.Interface())
+			}
+		}
+	}
+
+	// a single matching value is split like GJSON values, whereas multiple
+	// matching values (e.g. wildcards) are items themselves, unless they
+	// are arrays
+	var items []json.RawMessage
+	for _, v := range vals {
+		switch rv := reflect.ValueOf(v); {
+		case rv.Kind() == reflect.Slice:
+			for i := 0; i < rv.Len(); i++ {
+				if items, err = appendJSON(items, rv.Index(i).Interface()); err != nil {
+					return nil, err
+				}
+			}
+
+		case rv.Kind() == reflect.Map && len(vals) == 1:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, splitGJSON(b, "")...)
+
+		case len(vals) > 1:
+			if items, err = appendJSON(items, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// parseJSONPath parses a JSONPath expression, with or without the enclosing
+// curly braces, e.g. "{.items[*]}" or ".items[*]".
+func parseJSONPath(path string) (*jsonpath.JSONPath, error) {
+	if path == "" || path == "$" {
+		path = "{$}"
+	}
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + strings.TrimPrefix(path, "$") + "}"
+	}
+
+	jp := jsonpath.New("splitter").AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return nil, fmt.Errorf("parsing JSONPath %q: %w", path, err)
+	}
+	return jp, nil
+}
+
+// appendJSON appends the JSON representation of v to items.
+func appendJSON(items []json.RawMessage, v interface{}) ([]json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding JSON item: %w", err)
+	}
+	return append(items, b), nil
+}
+
+// keyValue returns a JSON object representing a key/value pair.
+func keyValue(rawKey, rawValue string) json.RawMessage {
+	return json.RawMessage(`{"key":` + rawKey + `,"value":` + rawValue + `}`)
+}
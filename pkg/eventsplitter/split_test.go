@@ -0,0 +1,235 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsplitter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testData = `{
+	"items": [{"id":1},{"id":2}],
+	"labels": {"a":"x","b":"y"},
+	"orders": [
+		{"lines": [{"sku":"a"},{"sku":"b"}]},
+		{"lines": [{"sku":"c"}]}
+	],
+	"a/b": {"c~d": [1,2,3]},
+	"name": "foo"
+}`
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		name   string
+		path   string
+		syntax string
+		expect []string
+		err    bool
+	}{{
+		name:   "gjson array",
+		path:   "items",
+		expect: []string{`{"id":1}`, `{"id":2}`},
+	}, {
+		name:   "gjson object",
+		path:   "labels",
+		syntax: SyntaxGJSON,
+		expect: []string{`{"key":"a","value":"x"}`, `{"key":"b","value":"y"}`},
+	}, {
+		name:   "gjson nested arrays",
+		path:   "orders.#.lines",
+		expect: []string{`{"sku":"a"}`, `{"sku":"b"}`, `{"sku":"c"}`},
+	}, {
+		name:   "gjson scalar",
+		path:   "name",
+		expect: nil,
+	}, {
+		name:   "gjson root",
+		path:   "@this.items",
+		expect: []string{`{"id":1}`, `{"id":2}`},
+	}, {
+		name:   "gjson missing path",
+		path:   "missing",
+		expect: nil,
+	}, {
+		name:   "json pointer array",
+		path:   "/items",
+		syntax: SyntaxJSONPointer,
+		expect: []string{`{"id":1}`, `{"id":2}`},
+	}, {
+		name:   "json pointer escaped tokens",
+		path:   "/a~1b/c~0d",
+		syntax: SyntaxJSONPointer,
+		expect: []string{`1`, `2`, `3`},
+	}, {
+		name:   "json pointer without leading slash",
+		path:   "items",
+		syntax: SyntaxJSONPointer,
+		err:    true,
+	}, {
+		name:   "jsonpath array",
+		path:   "$.items",
+		syntax: SyntaxJSONPath,
+		expect: []string{`{"id":1}`, `{"id":2}`},
+	}, {
+		name:   "jsonpath object",
+		path:   "{.labels}",
+		syntax: SyntaxJSONPath,
+		expect: []string{`{"key":"a","value":"x"}`, `{"key":"b","value":"y"}`},
+	}, {
+		name:   "jsonpath multiple matches",
+		path:   ".items[*].id",
+		syntax: SyntaxJSONPath,
+		expect: []string{`1`, `2`},
+	}, {
+		name:   "jsonpath nested arrays",
+		path:   ".orders[*].lines",
+		syntax: SyntaxJSONPath,
+		expect: []string{`{"sku":"a"}`, `{"sku":"b"}`, `{"sku":"c"}`},
+	}, {
+		name:   "unsupported syntax",
+		path:   "items",
+		syntax: "xpath",
+		err:    true,
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := Split([]byte(testData), tc.path, tc.syntax)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, i := range items {
+				got = append(got, string(i))
+			}
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	const parent = `{"message":"hi","order":{"id":42,"lines":[{"sku":"a"},"b"]},"order.ref":"r-1"}`
+
+	items, err := Split([]byte(parent), "order.lines", SyntaxGJSON)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		envelope Envelope
+		expect   []string
+	}{{
+		name:     "empty envelope",
+		envelope: Envelope{},
+		expect:   []string{`{"sku":"a"}`, `"b"`},
+	}, {
+		name:     "parent paths",
+		envelope: Envelope{ParentPaths: []string{"message", "order.id", "missing"}},
+		expect: []string{
+			`{"message":"hi","order":{"id":42},"sku":"a"}`,
+			`{"item":"b","message":"hi","order":{"id":42}}`,
+		},
+	}, {
+		name:     "wrap whole parent",
+		envelope: Envelope{Wrap: true},
+		expect: []string{
+			`{"index":0,"item":{"sku":"a"},"parent":` + parent + `,"total":2}`,
+			`{"index":1,"item":"b","parent":` + parent + `,"total":2}`,
+		},
+	}, {
+		name:     "wrap parent paths",
+		envelope: Envelope{Wrap: true, ParentPaths: []string{"order.id"}},
+		expect: []string{
+			`{"index":0,"item":{"sku":"a"},"parent":{"order":{"id":42}},"total":2}`,
+			`{"index":1,"item":"b","parent":{"order":{"id":42}},"total":2}`,
+		},
+	}, {
+		name:     "escaped parent path",
+		envelope: Envelope{ParentPaths: []string{`order\.ref`}},
+		expect: []string{
+			`{"order.ref":"r-1","sku":"a"}`,
+			`{"item":"b","order.ref":"r-1"}`,
+		},
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.envelope.Apply([]byte(parent), items)
+			require.NoError(t, err)
+
+			require.Len(t, res, len(tc.expect))
+			for i := range res {
+				assert.JSONEq(t, tc.expect[i], string(res[i]))
+			}
+		})
+	}
+}
+
+func TestEnvelopeLargeNumbers(t *testing.T) {
+	const parent = `{"id":9007199254740993}`
+	items := []json.RawMessage{
+		json.RawMessage(`{"amount":12345678901234567890,"price":0.10000000000000001}`),
+	}
+
+	res, err := Envelope{ParentPaths: []string{"id"}}.Apply([]byte(parent), items)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.JSONEq(t, `{"amount":12345678901234567890,"price":0.10000000000000001,"id":9007199254740993}`, string(res[0]))
+	assert.Contains(t, string(res[0]), `12345678901234567890`)
+	assert.Contains(t, string(res[0]), `9007199254740993`)
+}
+
+func TestValidateParentPath(t *testing.T) {
+	testCases := map[string]bool{
+		"id":            true,
+		"order.id":      true,
+		`order\.id`:     true,
+		`a\#b`:          true,
+		"":              false,
+		"order.":        false,
+		".id":           false,
+		`id\`:           false,
+		"items.#":       false,
+		"items.#.id":    false,
+		"items.*":       false,
+		"na?e":          false,
+		"id|@reverse":   false,
+		"@this":         false,
+		"[id,name]":     false,
+		"{id,name}":     false,
+		"items.#(id>1)": false,
+	}
+
+	for path, valid := range testCases {
+		//nolint:scopelint
+		t.Run(path, func(t *testing.T) {
+			err := ValidateParentPath(path)
+			if valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
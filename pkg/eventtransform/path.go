@@ -0,0 +1,333 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventtransform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+)
+
+// Scopes of a Path.
+const (
+	// ScopeAttributes refers to the context attributes of an event.
+	ScopeAttributes = "ce"
+	// ScopeData refers to the JSON payload of an event.
+	ScopeData = "data"
+)
+
+// Names of the context attributes of an event.
+const (
+	attrSpecVersion     = "specversion"
+	attrID              = "id"
+	attrType            = "type"
+	attrSource          = "source"
+	attrSubject         = "subject"
+	attrDataSchema      = "dataschema"
+	attrDataContentType = "datacontenttype"
+	attrTime            = "time"
+)
+
+// Path refers to a context attribute or to a field of the payload of an event.
+type Path struct {
+	// Scope is either ScopeAttributes or ScopeData.
+	Scope string
+	// Keys are the successive object keys or array indexes which lead to
+	// the field. A ScopeAttributes Path has exactly one key, the name of the
+	// attribute. A ScopeData Path without keys refers to the whole payload.
+	Keys []string
+}
+
+// ParsePath parses a dot-separated path, e.g. "ce.type" or "data.user.name".
+func ParsePath(path string) (Path, error) {
+	elems := strings.Split(path, ".")
+
+	p := Path{
+		Scope: elems[0],
+		Keys:  elems[1:],
+	}
+
+	for _, k := range p.Keys {
+		if k == "" {
+			return Path{}, fmt.Errorf("path %q contains an empty key", path)
+		}
+	}
+
+	switch p.Scope {
+	case ScopeAttributes:
+		if len(p.Keys) != 1 {
+			return Path{}, fmt.Errorf("attribute path %q must be of the form \"ce.<attribute>\"", path)
+		}
+		if p.Keys[0] == attrSpecVersion {
+			return Path{}, fmt.Errorf("attribute %q can not be modified", attrSpecVersion)
+		}
+		if err := validateAttributeName(p.Keys[0]); err != nil {
+			return Path{}, fmt.Errorf("invalid attribute path %q: %w", path, err)
+		}
+	case ScopeData:
+	default:
+		return Path{}, fmt.Errorf("path %q must start with %q or %q", path, ScopeAttributes+".", ScopeData)
+	}
+
+	return p, nil
+}
+
+// String implements fmt.Stringer.
+func (p Path) String() string {
+	return strings.Join(append([]string{p.Scope}, p.Keys...), ".")
+}
+
+// IsRequiredAttribute returns whether the Path refers to a context attribute
+// which can not be removed from an event.
+func (p Path) IsRequiredAttribute() bool {
+	if p.Scope != ScopeAttributes {
+		return false
+	}
+	switch p.Keys[0] {
+	case attrID, attrType, attrSource, attrSpecVersion:
+		return true
+	}
+	return false
+}
+
+// validateAttributeName returns an error if the given name isn't a valid
+// CloudEvents attribute name.
+func validateAttributeName(name string) error {
+	if len(name) > 20 {
+		return fmt.Errorf("attribute names must not exceed 20 characters")
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9') {
+			return fmt.Errorf("attribute names must only contain lowercase letters and digits")
+		}
+	}
+	return nil
+}
+
+// getAttribute returns the value of the context attribute with the given
+// name, and whether it is set.
+func getAttribute(e *event.Event, name string) (interface{}, bool) {
+	var val string
+
+	switch name {
+	case attrID:
+		val = e.ID()
+	case attrType:
+		val = e.Type()
+	case attrSource:
+		val = e.Source()
+	case attrSubject:
+		val = e.Subject()
+	case attrDataSchema:
+		val = e.DataSchema()
+	case attrDataContentType:
+		val = e.DataContentType()
+	case attrTime:
+		if t := e.Time(); !t.IsZero() {
+			val = t.Format(time.RFC3339Nano)
+		}
+	default:
+		ext, ok := e.Extensions()[name]
+		return ext, ok
+	}
+
+	return val, val != ""
+}
+
+// setAttribute sets the value of the context attribute with the given name.
+func setAttribute(e *event.Event, name string, value interface{}) error {
+	if name != attrTime && !isCoreAttribute(name) {
+		// extensions only support a subset of types, other values
+		// are represented as strings
+		switch value.(type) {
+		case string, bool:
+		default:
+			value = stringValue(value)
+		}
+		return e.Context.SetExtension(name, value)
+	}
+
+	str := stringValue(value)
+
+	switch name {
+	case attrID:
+		e.SetID(str)
+	case attrType:
+		e.SetType(str)
+	case attrSource:
+		e.SetSource(str)
+	case attrSubject:
+		e.SetSubject(str)
+	case attrDataSchema:
+		e.SetDataSchema(str)
+	case attrDataContentType:
+		e.SetDataContentType(str)
+	case attrTime:
+		t, err := cetypes.ParseTime(str)
+		if err != nil {
+			return fmt.Errorf("invalid value of attribute %q: %w", attrTime, err)
+		}
+		e.SetTime(t)
+	}
+
+	return e.Validate()
+}
+
+// deleteAttribute removes the context attribute with the given name.
+func deleteAttribute(e *event.Event, name string) error {
+	switch name {
+	case attrID, attrType, attrSource, attrSpecVersion:
+		return fmt.Errorf("required attribute %q can not be deleted", name)
+	case attrSubject:
+		e.SetSubject("")
+	case attrDataSchema:
+		e.SetDataSchema("")
+	case attrDataContentType:
+		e.SetDataContentType("")
+	case attrTime:
+		e.SetTime(time.Time{})
+	default:
+		return e.Context.SetExtension(name, nil)
+	}
+	return nil
+}
+
+// isCoreAttribute returns whether the given name is the name of a context
+// attribute defined by the CloudEvents specification, as opposed to an
+// extension.
+func isCoreAttribute(name string) bool {
+	switch name {
+	case attrSpecVersion, attrID, attrType, attrSource, attrSubject,
+		attrDataSchema, attrDataContentType, attrTime:
+		return true
+	}
+	return false
+}
+
+// getField returns the value found at the given keys inside a decoded JSON
+// value, and whether it exists.
+func getField(root interface{}, keys []string) (interface{}, bool) {
+	cur := root
+	for _, k := range keys {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if cur, ok = v[k]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, ok := arrayIndex(k, len(v))
+			if !ok {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// setField sets the given value at the given keys inside a decoded JSON
+// value, creating intermediate objects as needed, and returns the resulting
+// root value.
+func setField(root interface{}, keys []string, value interface{}) (interface{}, error) {
+	if len(keys) == 0 {
+		return value, nil
+	}
+
+	k := keys[0]
+
+	switch v := root.(type) {
+	case nil:
+		child, err := setField(nil, keys[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{k: child}, nil
+
+	case map[string]interface{}:
+		child, err := setField(v[k], keys[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		v[k] = child
+		return v, nil
+
+	case []interface{}:
+		i, ok := arrayIndex(k, len(v))
+		if !ok {
+			return nil, fmt.Errorf("index %q is out of the bounds of an array of length %d", k, len(v))
+		}
+		child, err := setField(v[i], keys[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = child
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("can not set key %q inside a value of type %T", k, root)
+}
+
+// deleteField removes the value found at the given keys inside a decoded
+// JSON value, if it exists, and returns the resulting root value.
+func deleteField(root interface{}, keys []string) interface{} {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	parent, ok := getField(root, keys[:len(keys)-1])
+	if !ok {
+		return root
+	}
+
+	k := keys[len(keys)-1]
+
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		delete(v, k)
+	case []interface{}:
+		i, ok := arrayIndex(k, len(v))
+		if !ok {
+			return root
+		}
+		elems := append(v[:i:i], v[i+1:]...)
+		if len(keys) == 1 {
+			return elems
+		}
+		// arrays are values, the shortened array must replace the
+		// original one inside its own parent
+		root, _ = setField(root, keys[:len(keys)-1], elems)
+	}
+
+	return root
+}
+
+// arrayIndex parses the given key as an index inside an array of the given
+// length.
+func arrayIndex(key string, length int) (int, bool) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || i >= length {
+		return 0, false
+	}
+	return i, true
+}
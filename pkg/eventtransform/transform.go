@@ -0,0 +1,387 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventtransform mutates the context attributes and the JSON payload
+// of CloudEvents.
+package eventtransform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// Supported operations
+const (
+	// OpSet sets the value of a field.
+	OpSet = "set"
+	// OpDelete removes a field.
+	OpDelete = "delete"
+	// OpRename moves the value of a field to another field.
+	OpRename = "rename"
+)
+
+// Operation describes a mutation of an event.
+type Operation struct {
+	// Op is one of OpSet, OpDelete or OpRename.
+	Op string
+	// Path of the mutated field, in the format accepted by ParsePath.
+	Path string
+	// To is the destination path of a renamed field.
+	To string
+	// Expression is a CEL expression which computes the value of a set
+	// field.
+	Expression string
+	// Template is a Go template which renders the value of a set field as a
+	// string.
+	Template string
+}
+
+// Transformer applies a sequence of compiled operations to events.
+type Transformer struct {
+	ops []compiledOperation
+}
+
+// compiledOperation is the compiled form of an Operation.
+type compiledOperation struct {
+	op    string
+	path  Path
+	to    Path
+	value valueFunc
+}
+
+// valueFunc computes a value from the given event.
+type valueFunc func(context.Context, *event.Event, *templateData) (interface{}, error)
+
+// Compile compiles the given operations. Expressions use the same syntax as
+// Filter expressions, with inline variable definitions, e.g. "$user.(string)".
+func Compile(ops []Operation) (*Transformer, error) {
+	return compileOperations(ops, compileInlineExpression)
+}
+
+// CompileWithVariables compiles the given operations. Expressions are plain
+// CEL expressions which reference the given variables.
+func CompileWithVariables(ops []Operation, vars []cel.Variable) (*Transformer, error) {
+	return compileOperations(ops, expressionCompiler(vars))
+}
+
+// Validate returns an error if the operation can't be compiled. Expressions
+// use the same syntax as in Compile.
+func (op Operation) Validate() error {
+	_, err := compileOperation(op, compileInlineExpression)
+	return err
+}
+
+// ValidateWithVariables returns an error if the operation can't be compiled.
+// Expressions use the same syntax as in CompileWithVariables.
+func (op Operation) ValidateWithVariables(vars []cel.Variable) error {
+	_, err := compileOperation(op, expressionCompiler(vars))
+	return err
+}
+
+// compileInlineExpression compiles the expression of the given operation,
+// which contains inline variable definitions.
+func compileInlineExpression(op Operation) (cel.ValueExpression, error) {
+	return cel.CompileValueExpression(op.Expression)
+}
+
+// expressionCompiler returns a function which compiles the expression of an
+// operation, which references the given variables.
+func expressionCompiler(vars []cel.Variable) func(Operation) (cel.ValueExpression, error) {
+	return func(op Operation) (cel.ValueExpression, error) {
+		return cel.CompileValueExpressionWithVariables(op.Expression, vars)
+	}
+}
+
+// compileOperations compiles the given operations, using the given function
+// to compile CEL expressions.
+func compileOperations(ops []Operation,
+	compileExpr func(Operation) (cel.ValueExpression, error)) (*Transformer, error) {
+
+	t := &Transformer{
+		ops: make([]compiledOperation, 0, len(ops)),
+	}
+
+	for i, op := range ops {
+		cop, err := compileOperation(op, compileExpr)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+		t.ops = append(t.ops, cop)
+	}
+
+	return t, nil
+}
+
+// compileOperation compiles a single operation.
+func compileOperation(op Operation,
+	compileExpr func(Operation) (cel.ValueExpression, error)) (compiledOperation, error) {
+
+	path, err := ParsePath(op.Path)
+	if err != nil {
+		return compiledOperation{}, err
+	}
+
+	cop := compiledOperation{
+		op:   op.Op,
+		path: path,
+	}
+
+	switch op.Op {
+	case OpSet:
+		switch {
+		case op.Expression != "" && op.Template != "":
+			return compiledOperation{}, errors.New("only one of expression or template can be set")
+
+		case op.Expression != "":
+			expr, err := compileExpr(op)
+			if err != nil {
+				return compiledOperation{}, fmt.Errorf("compiling expression: %w", err)
+			}
+			cop.value = func(ctx context.Context, e *event.Event, _ *templateData) (interface{}, error) {
+				return expr.Evaluate(ctx, *e)
+			}
+
+		case op.Template != "":
+			tpl, err := template.New(op.Path).Option("missingkey=error").Parse(op.Template)
+			if err != nil {
+				return compiledOperation{}, fmt.Errorf("parsing template: %w", err)
+			}
+			cop.value = func(_ context.Context, _ *event.Event, data *templateData) (interface{}, error) {
+				var b strings.Builder
+				if err := tpl.Execute(&b, data.values()); err != nil {
+					return nil, err
+				}
+				return b.String(), nil
+			}
+
+		default:
+			return compiledOperation{}, errors.New("either expression or template must be set")
+		}
+
+	case OpDelete:
+		if path.IsRequiredAttribute() {
+			return compiledOperation{}, fmt.Errorf("required attribute %q can not be deleted", path)
+		}
+
+	case OpRename:
+		if path.IsRequiredAttribute() {
+			return compiledOperation{}, fmt.Errorf("required attribute %q can not be renamed", path)
+		}
+		if cop.to, err = ParsePath(op.To); err != nil {
+			return compiledOperation{}, fmt.Errorf("invalid destination: %w", err)
+		}
+
+	default:
+		return compiledOperation{}, fmt.Errorf("unsupported operation %q", op.Op)
+	}
+
+	return cop, nil
+}
+
+// Apply applies the operations of the Transformer, in order, to a copy of the
+// given event and returns this copy. Expressions and templates are evaluated
+// against the original event.
+func (t *Transformer) Apply(ctx context.Context, e *event.Event) (*event.Event, error) {
+	out := e.Clone()
+	if err := t.ApplyTo(ctx, e, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ApplyTo applies the operations of the Transformer, in order, to the event
+// out. Expressions and templates are evaluated against the event e.
+func (t *Transformer) ApplyTo(ctx context.Context, e, out *event.Event) error {
+	data := &templateData{event: e}
+
+	// the payload is only decoded if an operation refers to it
+	var payload interface{}
+	var payloadDecoded bool
+
+	decodePayload := func() error {
+		if payloadDecoded {
+			return nil
+		}
+		var err error
+		if payload, err = decodeJSON(out.Data()); err != nil {
+			return fmt.Errorf("decoding payload: %w", err)
+		}
+		payloadDecoded = true
+		return nil
+	}
+
+	get := func(p Path) (interface{}, bool, error) {
+		if p.Scope == ScopeAttributes {
+			v, ok := getAttribute(out, p.Keys[0])
+			return v, ok, nil
+		}
+		if err := decodePayload(); err != nil {
+			return nil, false, err
+		}
+		v, ok := getField(payload, p.Keys)
+		return v, ok, nil
+	}
+
+	set := func(p Path, v interface{}) error {
+		if p.Scope == ScopeAttributes {
+			return setAttribute(out, p.Keys[0], v)
+		}
+		if err := decodePayload(); err != nil {
+			return err
+		}
+		var err error
+		payload, err = setField(payload, p.Keys, v)
+		return err
+	}
+
+	del := func(p Path) error {
+		if p.Scope == ScopeAttributes {
+			return deleteAttribute(out, p.Keys[0])
+		}
+		if err := decodePayload(); err != nil {
+			return err
+		}
+		payload = deleteField(payload, p.Keys)
+		return nil
+	}
+
+	for i, op := range t.ops {
+		var err error
+
+		switch op.op {
+		case OpSet:
+			var v interface{}
+			if v, err = op.value(ctx, e, data); err == nil {
+				err = set(op.path, v)
+			}
+
+		case OpDelete:
+			err = del(op.path)
+
+		case OpRename:
+			var v interface{}
+			var ok bool
+			if v, ok, err = get(op.path); err == nil && ok {
+				if err = del(op.path); err == nil {
+					err = set(op.to, v)
+				}
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("applying operation %d (%s %s): %w", i, op.op, op.path, err)
+		}
+	}
+
+	if payloadDecoded {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("encoding payload: %w", err)
+		}
+		contentType := out.DataContentType()
+		if contentType == "" {
+			contentType = cloudevents.ApplicationJSON
+		}
+		if err := out.SetData(contentType, b); err != nil {
+			return fmt.Errorf("setting payload: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// decodeJSON decodes a JSON payload. Numbers are decoded as json.Number so
+// that they are encoded back without loss of precision. An empty payload
+// decodes to nil.
+func decodeJSON(b []byte) (interface{}, error) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// templateData lazily computes the data templates are rendered with.
+type templateData struct {
+	event *event.Event
+	data  map[string]interface{}
+}
+
+// values returns the data templates are rendered with: the context
+// attributes of the event, including extensions, under "ce", and the decoded
+// JSON payload under "data".
+func (d *templateData) values() map[string]interface{} {
+	if d.data != nil {
+		return d.data
+	}
+
+	attrs := make(map[string]interface{}, len(d.event.Extensions())+8)
+	for name, v := range d.event.Extensions() {
+		attrs[name] = v
+	}
+	for _, name := range []string{attrSpecVersion, attrID, attrType, attrSource, attrSubject,
+		attrDataSchema, attrDataContentType, attrTime} {
+		if name == attrSpecVersion {
+			attrs[name] = d.event.SpecVersion()
+			continue
+		}
+		v, _ := getAttribute(d.event, name)
+		attrs[name] = v
+	}
+
+	// payloads which aren't JSON are only available through attributes
+	payload, _ := decodeJSON(d.event.Data())
+
+	d.data = map[string]interface{}{
+		ScopeAttributes: attrs,
+		ScopeData:       payload,
+	}
+	return d.data
+}
+
+// stringValue returns the string representation of the given value.
+func stringValue(v interface{}) string {
+	switch tv := v.(type) {
+	case string:
+		return tv
+	case nil:
+		return ""
+	case json.Number:
+		return tv.String()
+	}
+
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
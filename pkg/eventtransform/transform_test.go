@@ -0,0 +1,244 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventtransform
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPayload = `{"user":{"name":"jane","id":12345678901234567},"items":[1,2,3],"secret":"x"}`
+
+func newTestEvent(t *testing.T) *cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID("1")
+	e.SetType("com.example.order")
+	e.SetSource("example")
+	e.SetExtension("tenant", "acme")
+	require.NoError(t, e.SetData(cloudevents.ApplicationJSON, []byte(testPayload)))
+	return &e
+}
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name       string
+		ops        []Operation
+		expectData string
+		expectAttr map[string]interface{}
+		err        bool
+	}{{
+		name: "set data with expression",
+		ops: []Operation{{
+			Op:         OpSet,
+			Path:       "data.user.greeting",
+			Expression: `"hello " + $user.name.(string)`,
+		}},
+		expectData: `{"user":{"name":"jane","id":12345678901234567,"greeting":"hello jane"},"items":[1,2,3],"secret":"x"}`,
+	}, {
+		name: "set attribute with template",
+		ops: []Operation{{
+			Op:       OpSet,
+			Path:     "ce.type",
+			Template: "{{ .ce.type }}.{{ .ce.tenant }}.{{ .data.user.name }}",
+		}},
+		expectAttr: map[string]interface{}{"type": "com.example.order.acme.jane"},
+	}, {
+		name: "set extension with expression",
+		ops: []Operation{{
+			Op:         OpSet,
+			Path:       "ce.itemcount",
+			Expression: `size($items.(list))`,
+		}},
+		expectAttr: map[string]interface{}{"itemcount": "3"},
+	}, {
+		name: "set array element",
+		ops: []Operation{{
+			Op:         OpSet,
+			Path:       "data.items.1",
+			Expression: `{"n": 2}`,
+		}},
+		expectData: `{"user":{"name":"jane","id":12345678901234567},"items":[1,{"n":2},3],"secret":"x"}`,
+	}, {
+		name: "delete data and extension",
+		ops: []Operation{{
+			Op:   OpDelete,
+			Path: "data.secret",
+		}, {
+			Op:   OpDelete,
+			Path: "data.items.0",
+		}, {
+			Op:   OpDelete,
+			Path: "ce.tenant",
+		}},
+		expectData: `{"user":{"name":"jane","id":12345678901234567},"items":[2,3]}`,
+		expectAttr: map[string]interface{}{"tenant": nil},
+	}, {
+		name: "rename between data and attributes",
+		ops: []Operation{{
+			Op:   OpRename,
+			Path: "data.user.name",
+			To:   "ce.username",
+		}, {
+			Op:   OpRename,
+			Path: "ce.tenant",
+			To:   "data.tenant",
+		}},
+		expectData: `{"user":{"id":12345678901234567},"items":[1,2,3],"secret":"x","tenant":"acme"}`,
+		expectAttr: map[string]interface{}{"username": "jane", "tenant": nil},
+	}, {
+		name: "rename missing field",
+		ops: []Operation{{
+			Op:   OpRename,
+			Path: "data.missing",
+			To:   "data.other",
+		}},
+		expectData: testPayload,
+	}, {
+		name: "replace payload",
+		ops: []Operation{{
+			Op:         OpSet,
+			Path:       "data",
+			Expression: `{"name": $user.name.(string)}`,
+		}},
+		expectData: `{"name":"jane"}`,
+	}, {
+		name: "set required attribute to empty value",
+		ops: []Operation{{
+			Op:       OpSet,
+			Path:     "ce.source",
+			Template: "{{ .ce.subject }}",
+		}},
+		err: true,
+	}, {
+		name: "set key inside scalar",
+		ops: []Operation{{
+			Op:       OpSet,
+			Path:     "data.secret.value",
+			Template: "y",
+		}},
+		err: true,
+	}, {
+		name: "template with missing key",
+		ops: []Operation{{
+			Op:       OpSet,
+			Path:     "ce.subject",
+			Template: "{{ .ce.missing }}",
+		}},
+		err: true,
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			tr, err := Compile(tc.ops)
+			require.NoError(t, err)
+
+			in := newTestEvent(t)
+			out, err := tr.Apply(context.Background(), in)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.JSONEq(t, testPayload, string(in.Data()), "Original event was modified")
+
+			if tc.expectData != "" {
+				assert.JSONEq(t, tc.expectData, string(out.Data()))
+			}
+			for name, val := range tc.expectAttr {
+				v, _ := getAttribute(out, name)
+				assert.Equal(t, val, v, "Unexpected value of attribute %q", name)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	testCases := []struct {
+		name string
+		op   Operation
+		err  bool
+	}{{
+		name: "valid set",
+		op:   Operation{Op: OpSet, Path: "data.a.b", Template: "x"},
+	}, {
+		name: "unknown scope",
+		op:   Operation{Op: OpSet, Path: "body.a", Template: "x"},
+		err:  true,
+	}, {
+		name: "nested attribute",
+		op:   Operation{Op: OpSet, Path: "ce.a.b", Template: "x"},
+		err:  true,
+	}, {
+		name: "invalid attribute name",
+		op:   Operation{Op: OpSet, Path: "ce.Foo", Template: "x"},
+		err:  true,
+	}, {
+		name: "specversion",
+		op:   Operation{Op: OpSet, Path: "ce.specversion", Template: "0.3"},
+		err:  true,
+	}, {
+		name: "empty key",
+		op:   Operation{Op: OpDelete, Path: "data..a"},
+		err:  true,
+	}, {
+		name: "set without value",
+		op:   Operation{Op: OpSet, Path: "data.a"},
+		err:  true,
+	}, {
+		name: "set with expression and template",
+		op:   Operation{Op: OpSet, Path: "data.a", Expression: "1", Template: "x"},
+		err:  true,
+	}, {
+		name: "invalid expression",
+		op:   Operation{Op: OpSet, Path: "data.a", Expression: "1 +"},
+		err:  true,
+	}, {
+		name: "invalid template",
+		op:   Operation{Op: OpSet, Path: "data.a", Template: "{{ .ce.type"},
+		err:  true,
+	}, {
+		name: "delete required attribute",
+		op:   Operation{Op: OpDelete, Path: "ce.id"},
+		err:  true,
+	}, {
+		name: "rename without destination",
+		op:   Operation{Op: OpRename, Path: "data.a"},
+		err:  true,
+	}, {
+		name: "unknown operation",
+		op:   Operation{Op: "copy", Path: "data.a"},
+		err:  true,
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Compile([]Operation{tc.op})
+			if tc.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/serving"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
@@ -40,6 +41,13 @@ import (
 
 const metricsPrometheusPort uint16 = 9092
 
+// Ports exposed by multi-tenant adapters backed by a Deployment.
+const (
+	adapterPortName          = "http"
+	adapterPort        int32 = 8080
+	adapterServicePort int32 = 80
+)
+
 // ComponentName returns the component name for the given source object.
 func ComponentName(src kmeta.OwnerRefable) string {
 	return strings.ToLower(src.GetGroupVersionKind().Kind)
@@ -51,9 +59,16 @@ func MTAdapterObjectName(src kmeta.OwnerRefable) string {
 	return ComponentName(src) + "-" + componentAdapter
 }
 
+// DedicatedAdapterObjectName returns a unique name to apply to all objects
+// related to the dedicated adapter of the given source (Deployment/KnService,
+// ...).
+func DedicatedAdapterObjectName(src kmeta.OwnerRefable) string {
+	return kmeta.ChildName(ComponentName(src)+"-", src.GetObjectMeta().GetName())
+}
+
 // NewAdapterDeployment is a wrapper around resource.NewDeployment which
 // pre-populates attributes common to all adapters backed by a Deployment.
-func NewAdapterDeployment(src v1alpha1.Router, sinkURI *apis.URL, opts ...resource.ObjectOption) *appsv1.Deployment {
+func NewAdapterDeployment(src v1alpha1.Reconcilable, sinkURI *apis.URL, opts ...resource.ObjectOption) *appsv1.Deployment {
 	srcNs := src.GetNamespace()
 	srcName := src.GetName()
 
@@ -62,13 +77,15 @@ func NewAdapterDeployment(src v1alpha1.Router, sinkURI *apis.URL, opts ...resour
 		sinkURIStr = sinkURI.String()
 	}
 
-	return resource.NewDeployment(srcNs, kmeta.ChildName(ComponentName(src)+"-", srcName),
+	return resource.NewDeployment(srcNs, DedicatedAdapterObjectName(src),
 		append(commonAdapterDeploymentOptions(src), append([]resource.ObjectOption{
 			resource.Controller(src),
 
 			resource.Label(appInstanceLabel, srcName),
 			resource.Selector(appInstanceLabel, srcName),
 
+			resource.Port(adapterPortName, adapterPort),
+
 			resource.EnvVar(envSink, sinkURIStr),
 		}, opts...)...)...,
 	)
@@ -77,20 +94,218 @@ func NewAdapterDeployment(src v1alpha1.Router, sinkURI *apis.URL, opts ...resour
 // NewMTAdapterDeployment is a wrapper around resource.NewDeployment which
 // pre-populates attributes common to all multi-tenant adapters backed by a
 // Deployment.
-func NewMTAdapterDeployment(src v1alpha1.Router, opts ...resource.ObjectOption) *appsv1.Deployment {
+func NewMTAdapterDeployment(src v1alpha1.Reconcilable, opts ...resource.ObjectOption) *appsv1.Deployment {
 	srcNs := src.GetNamespace()
 
 	return resource.NewDeployment(srcNs, MTAdapterObjectName(src),
 		append(commonAdapterDeploymentOptions(src), append([]resource.ObjectOption{
+			// distinguishes the Pods of the multi-tenant adapter from
+			// the ones of dedicated adapters
+			resource.Selector(appInstanceLabel, MTAdapterObjectName(src)),
+
+			resource.Port(adapterPortName, adapterPort),
+
 			resource.EnvVar(EnvNamespace, srcNs),
 			resource.EnvVar(system.NamespaceEnvKey, srcNs), // required to enable HA
 		}, opts...)...)...,
 	)
 }
 
+// NewAdapterService returns a ClusterIP Service which exposes the dedicated
+// adapter Deployment of the given source inside the cluster.
+func NewAdapterService(src v1alpha1.Reconcilable, opts ...resource.ObjectOption) *corev1.Service {
+	srcName := src.GetName()
+
+	return resource.NewService(src.GetNamespace(), DedicatedAdapterObjectName(src),
+		append(commonAdapterServiceOptions(src), append([]resource.ObjectOption{
+			resource.Controller(src),
+
+			resource.Label(appInstanceLabel, srcName),
+			resource.Selector(appInstanceLabel, srcName),
+		}, opts...)...)...,
+	)
+}
+
+// NewMTAdapterService returns a ClusterIP Service which exposes the
+// multi-tenant adapter Deployment of the given source's type inside the
+// cluster.
+func NewMTAdapterService(src v1alpha1.Reconcilable, opts ...resource.ObjectOption) *corev1.Service {
+	return resource.NewService(src.GetNamespace(), MTAdapterObjectName(src),
+		append(commonAdapterServiceOptions(src), append([]resource.ObjectOption{
+			resource.Selector(appInstanceLabel, MTAdapterObjectName(src)),
+		}, opts...)...)...,
+	)
+}
+
+// commonAdapterServiceOptions returns a set of ObjectOptions common to all
+// Services which expose adapters backed by a Deployment.
+func commonAdapterServiceOptions(src v1alpha1.Reconcilable) []resource.ObjectOption {
+	app := ComponentName(src)
+
+	return []resource.ObjectOption{
+		resource.Label(appNameLabel, app),
+		resource.Label(appComponentLabel, componentAdapter),
+		resource.Label(appPartOfLabel, partOf),
+		resource.Label(appManagedByLabel, managedBy),
+
+		resource.Selector(appNameLabel, app),
+		resource.Selector(appComponentLabel, componentAdapter),
+
+		resource.ServicePort(adapterPortName, adapterServicePort, adapterPortName),
+	}
+}
+
+// NewRouterAdapterDeployment returns the adapter Deployment of the given
+// router, which is either the multi-tenant adapter of the router's type or,
+// if the router opted into a dedicated adapter, an adapter of its own.
+func NewRouterAdapterDeployment(src v1alpha1.Reconcilable, sinkURI *apis.URL, opts ...resource.ObjectOption) *appsv1.Deployment {
+	if v1alpha1.IsMultiTenant(src) {
+		return NewMTAdapterDeployment(src, opts...)
+	}
+	return NewAdapterDeployment(src, sinkURI, append(append(dedicatedAdapterOptions(src),
+		adapterDeploymentOverridesOptions(v1alpha1.AdapterOverridesOf(src))...), opts...)...)
+}
+
+// NewRouterAdapterKnService returns the adapter Knative Service of the given
+// router, which is either the multi-tenant adapter of the router's type or,
+// if the router opted into a dedicated adapter, an adapter of its own.
+func NewRouterAdapterKnService(src v1alpha1.Reconcilable, sinkURI *apis.URL, opts ...resource.ObjectOption) *servingv1.Service {
+	if v1alpha1.IsMultiTenant(src) {
+		return NewMTAdapterKnService(src, append(alwaysOnAdapterKnServiceOptions(), opts...)...)
+	}
+	return NewAdapterKnService(src, sinkURI, append(append(append(dedicatedAdapterOptions(src),
+		alwaysOnAdapterKnServiceOptions()...),
+		adapterKnServiceOverridesOptions(v1alpha1.AdapterOverridesOf(src))...), opts...)...)
+}
+
+// newRouterAdapterService returns the Service which exposes the adapter
+// Deployment of the given router.
+func newRouterAdapterService(src v1alpha1.Reconcilable) *corev1.Service {
+	if v1alpha1.IsMultiTenant(src) {
+		return NewMTAdapterService(src)
+	}
+	return NewAdapterService(src)
+}
+
+// dedicatedAdapterOptions returns a set of ObjectOptions which allow the
+// adapter of a router type to run as the dedicated adapter of a single router.
+func dedicatedAdapterOptions(src v1alpha1.Reconcilable) []resource.ObjectOption {
+	srcNs := src.GetNamespace()
+
+	return []resource.ObjectOption{
+		resource.EnvVar(EnvNamespace, srcNs),
+		resource.EnvVar(system.NamespaceEnvKey, srcNs), // required to enable HA
+
+		// restricts the adapter to its own router
+		resource.EnvVar(envRouterName, src.GetName()),
+		// leader election leases are named after the component, a
+		// distinct component keeps the leases of the adapter apart
+		// from the ones of other adapters
+		resource.EnvVar(envComponent, DedicatedAdapterObjectName(src)),
+	}
+}
+
+// alwaysOnAdapterKnServiceOptions returns a set of ObjectOptions which
+// prevent a router adapter backed by a Knative Service from scaling to zero.
+// Adapters acknowledge the generation of the routers they load, which only a
+// running adapter can do, and routers aren't Ready until then.
+func alwaysOnAdapterKnServiceOptions() []resource.ObjectOption {
+	return []resource.ObjectOption{
+		resource.PodAnnotation(autoscaling.MinScaleAnnotationKey, "1"),
+	}
+}
+
+// adapterDeploymentOverridesOptions returns a set of ObjectOptions which apply
+// the given adapter overrides to a dedicated adapter backed by a Deployment.
+func adapterDeploymentOverridesOptions(o *v1alpha1.AdapterOverrides) []resource.ObjectOption {
+	if o == nil {
+		return nil
+	}
+
+	opts := adapterOverridesOptions(o)
+
+	// a Deployment isn't autoscaled, so it runs exactly the minimum number
+	// of replicas requested by the user
+	if o.Scaling != nil && o.Scaling.MinScale != nil {
+		opts = append(opts, resource.Replicas(*o.Scaling.MinScale))
+	}
+
+	return opts
+}
+
+// adapterKnServiceOverridesOptions returns a set of ObjectOptions which apply
+// the given adapter overrides to a dedicated adapter backed by a Knative
+// Service.
+func adapterKnServiceOverridesOptions(o *v1alpha1.AdapterOverrides) []resource.ObjectOption {
+	if o == nil {
+		return nil
+	}
+
+	opts := adapterOverridesOptions(o)
+
+	if s := o.Scaling; s != nil {
+		if s.MinScale != nil {
+			opts = append(opts, resource.PodAnnotation(autoscaling.MinScaleAnnotationKey, strconv.Itoa(int(*s.MinScale))))
+		}
+		if s.MaxScale != nil {
+			opts = append(opts, resource.PodAnnotation(autoscaling.MaxScaleAnnotationKey, strconv.Itoa(int(*s.MaxScale))))
+		}
+		if s.Target != nil {
+			opts = append(opts, resource.PodAnnotation(autoscaling.TargetAnnotationKey, strconv.Itoa(int(*s.Target))))
+		}
+	}
+
+	return opts
+}
+
+// SingleReplicaAdapterKnServiceOptions returns a set of ObjectOptions which
+// pin an adapter backed by a Knative Service to exactly one instance. Adapters
+// which keep their state in memory can neither scale out nor scale to zero.
+func SingleReplicaAdapterKnServiceOptions() []resource.ObjectOption {
+	return []resource.ObjectOption{
+		resource.PodAnnotation(autoscaling.MinScaleAnnotationKey, "1"),
+		resource.PodAnnotation(autoscaling.MaxScaleAnnotationKey, "1"),
+	}
+}
+
+// SingleReplicaAdapterDeploymentOptions returns a set of ObjectOptions which
+// pin an adapter backed by a Deployment to exactly one instance.
+func SingleReplicaAdapterDeploymentOptions() []resource.ObjectOption {
+	return []resource.ObjectOption{
+		resource.Replicas(1),
+	}
+}
+
+// adapterOverridesOptions returns a set of ObjectOptions which apply the
+// resources and scheduling attributes of the given adapter overrides.
+func adapterOverridesOptions(o *v1alpha1.AdapterOverrides) []resource.ObjectOption {
+	var opts []resource.ObjectOption
+
+	if r := o.Resources; r != nil {
+		opts = append(opts,
+			resource.Requests(*r.Requests.Cpu(), *r.Requests.Memory()),
+			resource.Limits(*r.Limits.Cpu(), *r.Limits.Memory()),
+		)
+	}
+
+	if len(o.NodeSelector) > 0 {
+		opts = append(opts, resource.NodeSelector(o.NodeSelector))
+	}
+
+	for _, t := range o.Tolerations {
+		opts = append(opts, resource.Toleration(t))
+	}
+
+	if o.PriorityClassName != "" {
+		opts = append(opts, resource.PriorityClassName(o.PriorityClassName))
+	}
+
+	return opts
+}
+
 // commonAdapterDeploymentOptions returns a set of ObjectOptions common to all
 // adapters backed by a Deployment.
-func commonAdapterDeploymentOptions(src v1alpha1.Router) []resource.ObjectOption {
+func commonAdapterDeploymentOptions(src v1alpha1.Reconcilable) []resource.ObjectOption {
 	app := ComponentName(src)
 
 	return []resource.ObjectOption{
@@ -114,8 +329,7 @@ func commonAdapterDeploymentOptions(src v1alpha1.Router) []resource.ObjectOption
 
 // NewAdapterKnService is a wrapper around resource.NewKnService which
 // pre-populates attributes common to all adapters backed by a Knative Service.
-func NewAdapterKnService(src v1alpha1.Router, sinkURI *apis.URL, opts ...resource.ObjectOption) *servingv1.Service {
-	app := ComponentName(src)
+func NewAdapterKnService(src v1alpha1.Reconcilable, sinkURI *apis.URL, opts ...resource.ObjectOption) *servingv1.Service {
 	srcNs := src.GetNamespace()
 	srcName := src.GetName()
 
@@ -124,7 +338,7 @@ func NewAdapterKnService(src v1alpha1.Router, sinkURI *apis.URL, opts ...resourc
 		sinkURIStr = sinkURI.String()
 	}
 
-	return resource.NewKnService(srcNs, kmeta.ChildName(app+"-", srcName),
+	return resource.NewKnService(srcNs, DedicatedAdapterObjectName(src),
 		append(commonAdapterKnServiceOptions(src), append([]resource.ObjectOption{
 			resource.Controller(src),
 
@@ -139,7 +353,7 @@ func NewAdapterKnService(src v1alpha1.Router, sinkURI *apis.URL, opts ...resourc
 // NewMTAdapterKnService is a wrapper around resource.NewKnService which
 // pre-populates attributes common to all multi-tenant adapters backed by a
 // Knative Service.
-func NewMTAdapterKnService(src v1alpha1.Router, opts ...resource.ObjectOption) *servingv1.Service {
+func NewMTAdapterKnService(src v1alpha1.Reconcilable, opts ...resource.ObjectOption) *servingv1.Service {
 	srcNs := src.GetNamespace()
 
 	return resource.NewKnService(srcNs, MTAdapterObjectName(src),
@@ -152,7 +366,7 @@ func NewMTAdapterKnService(src v1alpha1.Router, opts ...resource.ObjectOption) *
 
 // commonAdapterKnServiceOptions returns a set of ObjectOptions common to all
 // adapters backed by a Knative Service.
-func commonAdapterKnServiceOptions(src v1alpha1.Router) []resource.ObjectOption {
+func commonAdapterKnServiceOptions(src v1alpha1.Reconcilable) []resource.ObjectOption {
 	app := ComponentName(src)
 
 	return []resource.ObjectOption{
@@ -176,7 +390,7 @@ func commonAdapterKnServiceOptions(src v1alpha1.Router) []resource.ObjectOption
 
 // newServiceAccount returns a ServiceAccount object with its OwnerReferences
 // metadata attribute populated from the given owners.
-func newServiceAccount(src v1alpha1.Router, owners []kmeta.OwnerRefable) *corev1.ServiceAccount {
+func newServiceAccount(src v1alpha1.Reconcilable, owners []kmeta.OwnerRefable) *corev1.ServiceAccount {
 	ownerRefs := make([]metav1.OwnerReference, len(owners))
 	for i, owner := range owners {
 		ownerRefs[i] = *kmeta.NewControllerRef(owner)
@@ -196,7 +410,7 @@ func newServiceAccount(src v1alpha1.Router, owners []kmeta.OwnerRefable) *corev1
 
 // newRoleBinding returns a RoleBinding object that binds a ServiceAccount
 // (namespace-scoped) to a ClusterRole (cluster-scoped).
-func newRoleBinding(src v1alpha1.Router, owner *corev1.ServiceAccount) *rbacv1.RoleBinding {
+func newRoleBinding(src v1alpha1.Reconcilable, owner *corev1.ServiceAccount) *rbacv1.RoleBinding {
 	crGVK := rbacv1.SchemeGroupVersion.WithKind("ClusterRole")
 	saGVK := corev1.SchemeGroupVersion.WithKind("ServiceAccount")
 
@@ -0,0 +1,170 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/autoscaling"
+
+	"github.com/triggermesh/routing/pkg/apis/flow"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	res "github.com/triggermesh/routing/pkg/reconciler/common/resource"
+)
+
+func TestAdapterOverridesOptions(t *testing.T) {
+	overrides := &v1alpha1.AdapterOverrides{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("100m"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+		Scaling: &v1alpha1.AdapterScaling{
+			MinScale: ptr.Int32(2),
+			MaxScale: ptr.Int32(5),
+		},
+		NodeSelector:      map[string]string{"disktype": "ssd"},
+		PriorityClassName: "high",
+	}
+
+	t.Run("deployment", func(t *testing.T) {
+		d := res.NewDeployment("ns", "name",
+			append(adapterDeploymentOverridesOptions(overrides), res.Image("registry/image"))...)
+
+		assert.Equal(t, ptr.Int32(2), d.Spec.Replicas)
+
+		podSpec := d.Spec.Template.Spec
+		assert.Equal(t, map[string]string{"disktype": "ssd"}, podSpec.NodeSelector)
+		assert.Equal(t, "high", podSpec.PriorityClassName)
+
+		rsrc := podSpec.Containers[0].Resources
+		assert.Equal(t, "100m", rsrc.Requests.Cpu().String())
+		assert.Equal(t, "64Mi", rsrc.Limits.Memory().String())
+		assert.NotContains(t, rsrc.Requests, corev1.ResourceMemory)
+		assert.NotContains(t, rsrc.Limits, corev1.ResourceCPU)
+	})
+
+	t.Run("knservice", func(t *testing.T) {
+		ksvc := res.NewKnService("ns", "name",
+			append(adapterKnServiceOverridesOptions(overrides), res.Image("registry/image"))...)
+
+		assert.Equal(t, map[string]string{
+			autoscaling.MinScaleAnnotationKey: "2",
+			autoscaling.MaxScaleAnnotationKey: "5",
+		}, ksvc.Spec.Template.Annotations)
+		assert.Equal(t, "high", ksvc.Spec.Template.Spec.PriorityClassName)
+	})
+
+	t.Run("no overrides", func(t *testing.T) {
+		assert.Empty(t, adapterDeploymentOverridesOptions(nil))
+		assert.Empty(t, adapterKnServiceOverridesOptions(nil))
+	})
+}
+
+func TestRouterAdapterKnServiceScaling(t *testing.T) {
+	newFilter := func(isolation string, o *v1alpha1.AdapterOverrides) *v1alpha1.Filter {
+		return &v1alpha1.Filter{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        "name",
+				Annotations: map[string]string{flow.AdapterIsolationAnnotation: isolation},
+			},
+			Spec: v1alpha1.FilterSpec{
+				AdapterOverrides: o,
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		filter         *v1alpha1.Filter
+		expectMinScale string
+	}{
+		"shared adapter": {
+			filter:         newFilter(flow.AdapterIsolationShared, nil),
+			expectMinScale: "1",
+		},
+		"dedicated adapter": {
+			filter:         newFilter(flow.AdapterIsolationDedicated, nil),
+			expectMinScale: "1",
+		},
+		"dedicated adapter with overrides": {
+			filter: newFilter(flow.AdapterIsolationDedicated, &v1alpha1.AdapterOverrides{
+				Scaling: &v1alpha1.AdapterScaling{MinScale: ptr.Int32(0)},
+			}),
+			expectMinScale: "0",
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ksvc := NewRouterAdapterKnService(tc.filter, nil, res.Image("registry/image"))
+			assert.Equal(t, tc.expectMinScale, ksvc.Spec.Template.Annotations[autoscaling.MinScaleAnnotationKey])
+		})
+	}
+}
+
+func TestRouterAdapterDeploymentEnv(t *testing.T) {
+	newFilter := func(isolation string) *v1alpha1.Filter {
+		return &v1alpha1.Filter{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        "name",
+				Annotations: map[string]string{flow.AdapterIsolationAnnotation: isolation},
+			},
+		}
+	}
+
+	// env returns the environment of the adapter container of the given
+	// Deployment as a map.
+	env := func(d *appsv1.Deployment) map[string]string {
+		env := make(map[string]string)
+		for _, ev := range d.Spec.Template.Spec.Containers[0].Env {
+			env[ev.Name] = ev.Value
+		}
+		return env
+	}
+
+	t.Run("shared adapter", func(t *testing.T) {
+		d := NewRouterAdapterDeployment(newFilter(flow.AdapterIsolationShared), nil, res.Image("registry/image"))
+
+		e := env(d)
+		assert.Equal(t, "filter", e[envComponent])
+		assert.NotContains(t, e, envRouterName)
+	})
+
+	t.Run("dedicated adapter", func(t *testing.T) {
+		f := newFilter(flow.AdapterIsolationDedicated)
+		d := NewRouterAdapterDeployment(f, nil, res.Image("registry/image"))
+
+		e := env(d)
+		assert.Equal(t, DedicatedAdapterObjectName(f), e[envComponent],
+			"Dedicated adapters use leases of their own")
+		assert.Equal(t, "name", e[envRouterName], "Dedicated adapters only serve their own router")
+	})
+}
@@ -35,14 +35,18 @@ import (
 
 	k8sclient "knative.dev/pkg/client/injection/kube/client"
 	deploymentinformerv1 "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	namespaceinformerv1 "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	k8sserviceinformerv1 "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	sainformerv1 "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount"
 	rbinformerv1 "knative.dev/pkg/client/injection/kube/informers/rbac/v1/rolebinding"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
 	"knative.dev/pkg/resolver"
 	servingclientv1 "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servinginformersv1 "knative.dev/serving/pkg/client/informers/externalversions/serving/v1"
 	servingclient "knative.dev/serving/pkg/client/injection/client"
-	serviceinformerv1 "knative.dev/serving/pkg/client/injection/informers/serving/v1/service"
+	servinginformerfactory "knative.dev/serving/pkg/client/injection/informers/factory"
 	servinglistersv1 "knative.dev/serving/pkg/client/listers/serving/v1"
 )
 
@@ -51,10 +55,12 @@ type GenericDeploymentReconciler struct {
 	// URI resolver for sinks
 	SinkResolver *resolver.URIResolver
 	// API clients
-	Client    func(namespace string) appsclientv1.DeploymentInterface
-	PodClient func(namespace string) coreclientv1.PodInterface
+	Client        func(namespace string) appsclientv1.DeploymentInterface
+	PodClient     func(namespace string) coreclientv1.PodInterface
+	ServiceClient func(namespace string) coreclientv1.ServiceInterface
 	// objects listers
-	Lister func(namespace string) appslistersv1.DeploymentNamespaceLister
+	Lister        func(namespace string) appslistersv1.DeploymentNamespaceLister
+	ServiceLister func(namespace string) corelistersv1.ServiceNamespaceLister
 
 	*GenericRBACReconciler
 }
@@ -71,6 +77,21 @@ type GenericServiceReconciler struct {
 	*GenericRBACReconciler
 }
 
+// GenericAdapterReconciler reconciles the adapters of routers, either
+// multi-tenant or dedicated, as Knative Services or as Deployments exposed by
+// a ClusterIP Service, depending on the AdapterMode which applies to the
+// namespace of the reconciled object.
+type GenericAdapterReconciler struct {
+	// default adapter mode
+	Mode AdapterMode
+	// objects listers
+	NamespaceLister corelistersv1.NamespaceLister
+
+	Deployment GenericDeploymentReconciler
+	// nil when Knative Serving support is disabled
+	Service *GenericServiceReconciler
+}
+
 // GenericRBACReconciler reconciles RBAC objects for source adapters.
 type GenericRBACReconciler struct {
 	// API clients
@@ -88,22 +109,31 @@ func NewGenericDeploymentReconciler(ctx context.Context, gvk schema.GroupVersion
 	adapterHandlerFn func(obj interface{}),
 ) GenericDeploymentReconciler {
 
-	informer := deploymentinformerv1.Get(ctx)
-
-	r := GenericDeploymentReconciler{
-		SinkResolver:          resolver.NewURIResolver(ctx, resolverCallback),
-		Client:                k8sclient.Get(ctx).AppsV1().Deployments,
-		PodClient:             k8sclient.Get(ctx).CoreV1().Pods,
-		Lister:                informer.Lister().Deployments,
-		GenericRBACReconciler: NewGenericRBACReconciler(ctx),
-	}
-
-	informer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+	deploymentinformerv1.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.FilterControllerGVK(gvk),
 		Handler:    controller.HandleAll(adapterHandlerFn),
 	})
 
-	return r
+	return newGenericDeploymentReconciler(ctx, resolverCallback)
+}
+
+// NewMTGenericDeploymentReconciler creates a new GenericDeploymentReconciler
+// for a multi-tenant adapter and attaches a default event handler to its
+// Deployment and Service informers.
+func NewMTGenericDeploymentReconciler(ctx context.Context, typ kmeta.OwnerRefable,
+	resolverCallback func(types.NamespacedName),
+	adapterHandlerFn func(obj interface{}),
+) GenericDeploymentReconciler {
+
+	handler := cache.FilteringResourceEventHandler{
+		FilterFunc: hasAdapterLabelsForType(typ),
+		Handler:    controller.HandleAll(adapterHandlerFn),
+	}
+
+	deploymentinformerv1.Get(ctx).Informer().AddEventHandler(handler)
+	k8sserviceinformerv1.Get(ctx).Informer().AddEventHandler(handler)
+
+	return newGenericDeploymentReconciler(ctx, resolverCallback)
 }
 
 // NewGenericServiceReconciler creates a new GenericServiceReconciler and
@@ -113,7 +143,7 @@ func NewGenericServiceReconciler(ctx context.Context, gvk schema.GroupVersionKin
 	adapterHandlerFn func(obj interface{}),
 ) GenericServiceReconciler {
 
-	serviceinformerv1.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+	knServiceInformer(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.FilterControllerGVK(gvk),
 		Handler:    controller.HandleAll(adapterHandlerFn),
 	})
@@ -129,7 +159,7 @@ func NewMTGenericServiceReconciler(ctx context.Context, typ kmeta.OwnerRefable,
 	adapterHandlerFn func(obj interface{}),
 ) GenericServiceReconciler {
 
-	serviceinformerv1.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+	knServiceInformer(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: hasAdapterLabelsForType(typ),
 		Handler:    controller.HandleAll(adapterHandlerFn),
 	})
@@ -137,6 +167,37 @@ func NewMTGenericServiceReconciler(ctx context.Context, typ kmeta.OwnerRefable,
 	return newGenericServiceReconciler(ctx, resolverCallback)
 }
 
+// NewGenericAdapterReconciler creates a new GenericAdapterReconciler for
+// the given type, using the default AdapterMode read from the environment.
+// Informers for Knative Services are only started when the default mode is
+// AdapterModeKnService, which allows running in clusters where Knative
+// Serving isn't installed.
+func NewGenericAdapterReconciler(ctx context.Context, typ kmeta.OwnerRefable,
+	resolverCallback func(types.NamespacedName),
+	adapterHandlerFn func(obj interface{}),
+) GenericAdapterReconciler {
+
+	mode := MustDefaultAdapterMode()
+
+	nsInformer := namespaceinformerv1.Get(ctx)
+	nsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: onAdapterModeChange(adapterHandlerFn),
+	})
+
+	r := GenericAdapterReconciler{
+		Mode:            mode,
+		NamespaceLister: nsInformer.Lister(),
+		Deployment:      NewMTGenericDeploymentReconciler(ctx, typ, resolverCallback, adapterHandlerFn),
+	}
+
+	if mode == AdapterModeKnService {
+		svcr := NewMTGenericServiceReconciler(ctx, typ, resolverCallback, adapterHandlerFn)
+		r.Service = &svcr
+	}
+
+	return r
+}
+
 // NewGenericRBACReconciler creates a new GenericRBACReconciler.
 func NewGenericRBACReconciler(ctx context.Context) *GenericRBACReconciler {
 	return &GenericRBACReconciler{
@@ -147,7 +208,23 @@ func NewGenericRBACReconciler(ctx context.Context) *GenericRBACReconciler {
 	}
 }
 
-// newMTGenericServiceReconciler creates a new GenericServiceReconciler.
+// newGenericDeploymentReconciler creates a new GenericDeploymentReconciler.
+func newGenericDeploymentReconciler(ctx context.Context,
+	resolverCallback func(types.NamespacedName),
+) GenericDeploymentReconciler {
+
+	return GenericDeploymentReconciler{
+		SinkResolver:          resolver.NewURIResolver(ctx, resolverCallback),
+		Client:                k8sclient.Get(ctx).AppsV1().Deployments,
+		PodClient:             k8sclient.Get(ctx).CoreV1().Pods,
+		ServiceClient:         k8sclient.Get(ctx).CoreV1().Services,
+		Lister:                deploymentinformerv1.Get(ctx).Lister().Deployments,
+		ServiceLister:         k8sserviceinformerv1.Get(ctx).Lister().Services,
+		GenericRBACReconciler: NewGenericRBACReconciler(ctx),
+	}
+}
+
+// newGenericServiceReconciler creates a new GenericServiceReconciler.
 func newGenericServiceReconciler(ctx context.Context,
 	resolverCallback func(types.NamespacedName),
 ) GenericServiceReconciler {
@@ -155,10 +232,30 @@ func newGenericServiceReconciler(ctx context.Context,
 	return GenericServiceReconciler{
 		SinkResolver:          resolver.NewURIResolver(ctx, resolverCallback),
 		Client:                servingclient.Get(ctx).ServingV1().Services,
-		Lister:                serviceinformerv1.Get(ctx).Lister().Services,
+		Lister:                knServiceInformer(ctx).Lister().Services,
 		GenericRBACReconciler: NewGenericRBACReconciler(ctx),
 	}
+}
+
+// knServiceInformer returns a started informer for Knative Services.
+//
+// Unlike other informers, this one isn't registered with the injection
+// framework, because informers registered that way are all started and
+// synced by sharedmain, which would block indefinitely in clusters where the
+// Knative Serving API is not available.
+func knServiceInformer(ctx context.Context) servinginformersv1.ServiceInformer {
+	f := servinginformerfactory.Get(ctx)
+
+	inf := f.Serving().V1().Services()
+	informer := inf.Informer()
+
+	// Start only runs informers which haven't been started yet.
+	f.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		logging.FromContext(ctx).Panic("Failed to wait for the Knative Service informer to sync")
+	}
 
+	return inf
 }
 
 // filteredGlobalResyncFunc is a function that enqueues all objects from the
@@ -23,5 +23,6 @@ const (
 
 	envSink                  = "K_SINK"
 	envComponent             = "K_COMPONENT"
+	envRouterName            = "ROUTER_NAME"
 	envMetricsPrometheusPort = "METRICS_PROMETHEUS_PORT"
 )
@@ -35,10 +35,18 @@ const (
 	ReasonFailedAdapterCreate = "FailedAdapterCreate"
 	// ReasonFailedAdapterUpdate indicates that the update of an adapter object failed.
 	ReasonFailedAdapterUpdate = "FailedAdapterUpdate"
+	// ReasonAdapterDelete indicates that an adapter object was successfully deleted.
+	ReasonAdapterDelete = "DeleteAdapter"
+	// ReasonFailedAdapterDelete indicates that the deletion of an adapter object failed.
+	ReasonFailedAdapterDelete = "FailedAdapterDelete"
+	// ReasonInvalidAdapterMode indicates that the adapter mode of a namespace is invalid.
+	ReasonInvalidAdapterMode = "InvalidAdapterMode"
 
 	// ReasonBadSinkURI indicates that the URI of a sink can't be determined.
 	ReasonBadSinkURI = "BadSinkURI"
 
 	// ReasonInvalidSpec indicates that spec of a reconciled object is invalid.
 	ReasonInvalidSpec = "InvalidSpec"
+	// ReasonInvalidExpression indicates that an expression of a reconciled object does not compile.
+	ReasonInvalidExpression = "InvalidExpression"
 )
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AdapterMode is the method used for running multi-tenant adapters.
+type AdapterMode string
+
+// Supported adapter modes.
+const (
+	// AdapterModeKnService runs adapters as Knative Services. Requires
+	// Knative Serving.
+	AdapterModeKnService AdapterMode = "knservice"
+	// AdapterModeDeployment runs adapters as Deployments exposed by a
+	// ClusterIP Service.
+	AdapterModeDeployment AdapterMode = "deployment"
+)
+
+// AdapterModeAnnotation is an annotation which can be set on a Namespace to
+// override the default AdapterMode of the controller for all objects created
+// inside that Namespace.
+const AdapterModeAnnotation = "flow.triggermesh.io/adapter-mode"
+
+// adapterModeConfig contains the controller-wide adapter mode.
+// It is automatically populated by envconfig.
+type adapterModeConfig struct {
+	Mode AdapterMode `envconfig:"ADAPTER_MODE" default:"knservice"`
+}
+
+// MustDefaultAdapterMode returns the default AdapterMode read from the
+// environment, and panics if this value is invalid.
+func MustDefaultAdapterMode() AdapterMode {
+	cfg := &adapterModeConfig{}
+	envconfig.MustProcess("", cfg)
+
+	if err := cfg.Mode.Validate(); err != nil {
+		panic(fmt.Errorf("reading ADAPTER_MODE from the environment: %w", err))
+	}
+
+	return cfg.Mode
+}
+
+// Validate returns an error if the AdapterMode is not supported.
+func (m AdapterMode) Validate() error {
+	switch m {
+	case AdapterModeKnService, AdapterModeDeployment:
+		return nil
+	default:
+		return fmt.Errorf("unsupported adapter mode %q, expected one of [%s, %s]",
+			m, AdapterModeKnService, AdapterModeDeployment)
+	}
+}
+
+// namespaceAdapterMode returns the AdapterMode set on the given Namespace, or
+// the given default mode if the Namespace doesn't override it.
+func namespaceAdapterMode(ns *corev1.Namespace, defaultMode AdapterMode) (AdapterMode, error) {
+	val, ok := ns.Annotations[AdapterModeAnnotation]
+	if !ok {
+		return defaultMode, nil
+	}
+
+	mode := AdapterMode(val)
+	if err := mode.Validate(); err != nil {
+		return "", err
+	}
+
+	return mode, nil
+}
+
+// onAdapterModeChange returns an update handler for Namespaces which invokes
+// the given adapter handler whenever the adapter mode of a Namespace changes.
+func onAdapterModeChange(adapterHandlerFn func(obj interface{})) func(oldObj, newObj interface{}) {
+	return func(oldObj, newObj interface{}) {
+		oldNs, ok := oldObj.(*corev1.Namespace)
+		if !ok {
+			return
+		}
+		newNs, ok := newObj.(*corev1.Namespace)
+		if !ok {
+			return
+		}
+
+		if oldNs.Annotations[AdapterModeAnnotation] == newNs.Annotations[AdapterModeAnnotation] {
+			return
+		}
+
+		// adapter handlers operate on the namespace of the given object
+		adapterHandlerFn(&metav1.ObjectMeta{
+			Namespace: newNs.Name,
+		})
+	}
+}
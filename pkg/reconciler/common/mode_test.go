@@ -0,0 +1,99 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespaceAdapterMode(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expectMode  AdapterMode
+		expectErr   bool
+	}{
+		"no annotation": {
+			annotations: nil,
+			expectMode:  AdapterModeKnService,
+		},
+		"deployment mode": {
+			annotations: map[string]string{AdapterModeAnnotation: "deployment"},
+			expectMode:  AdapterModeDeployment,
+		},
+		"knservice mode": {
+			annotations: map[string]string{AdapterModeAnnotation: "knservice"},
+			expectMode:  AdapterModeKnService,
+		},
+		"invalid mode": {
+			annotations: map[string]string{AdapterModeAnnotation: "pod"},
+			expectErr:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					Annotations: tc.annotations,
+				},
+			}
+
+			mode, err := namespaceAdapterMode(ns, AdapterModeKnService)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectMode, mode)
+		})
+	}
+}
+
+func TestOnAdapterModeChange(t *testing.T) {
+	var handled []interface{}
+	handlerFn := onAdapterModeChange(func(obj interface{}) {
+		handled = append(handled, obj)
+	})
+
+	newNs := func(mode string) *corev1.Namespace {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+		}
+		if mode != "" {
+			ns.Annotations = map[string]string{AdapterModeAnnotation: mode}
+		}
+		return ns
+	}
+
+	handlerFn(newNs(""), newNs(""))
+	assert.Empty(t, handled, "Handler should not be invoked when the mode is unchanged")
+
+	handlerFn(newNs(""), newNs("deployment"))
+	if assert.Len(t, handled, 1, "Handler should be invoked when the mode changes") {
+		assert.Equal(t, "test", handled[0].(metav1.Object).GetNamespace())
+	}
+}
@@ -31,9 +31,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/network"
 	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
 	"knative.dev/serving/pkg/apis/serving"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
@@ -59,22 +62,191 @@ type RBACOwnersLister interface {
 // objects related to a source's adapter backed by a Deployment.
 type AdapterDeploymentBuilder interface {
 	RBACOwnersLister
-	BuildAdapter(r v1alpha1.Router, sinkURI *apis.URL) *appsv1.Deployment
+	BuildAdapter(r v1alpha1.Reconcilable, sinkURI *apis.URL) *appsv1.Deployment
 }
 
 // AdapterServiceBuilder provides all the necessary information for building
 // objects related to a source's adapter backed by a Knative Service.
 type AdapterServiceBuilder interface {
 	RBACOwnersLister
-	BuildAdapter(r v1alpha1.Router, sinkURI *apis.URL) *servingv1.Service
+	BuildAdapter(r v1alpha1.Reconcilable, sinkURI *apis.URL) *servingv1.Service
+}
+
+// AdapterBuilder provides all the necessary information for building
+// objects related to the adapter of a router in any of the supported adapter
+// modes.
+type AdapterBuilder interface {
+	RBACOwnersLister
+	BuildAdapterDeployment(r v1alpha1.Reconcilable, sinkURI *apis.URL) *appsv1.Deployment
+	BuildAdapterKnService(r v1alpha1.Reconcilable, sinkURI *apis.URL) *servingv1.Service
+}
+
+// adapterDeploymentBuilder adapts an AdapterBuilder to the
+// AdapterDeploymentBuilder interface.
+type adapterDeploymentBuilder struct {
+	AdapterBuilder
+}
+
+// BuildAdapter implements AdapterDeploymentBuilder.
+func (b adapterDeploymentBuilder) BuildAdapter(r v1alpha1.Reconcilable, sinkURI *apis.URL) *appsv1.Deployment {
+	return b.BuildAdapterDeployment(r, sinkURI)
+}
+
+// adapterServiceBuilder adapts an AdapterBuilder to the
+// AdapterServiceBuilder interface.
+type adapterServiceBuilder struct {
+	AdapterBuilder
+}
+
+// BuildAdapter implements AdapterServiceBuilder.
+func (b adapterServiceBuilder) BuildAdapter(r v1alpha1.Reconcilable, sinkURI *apis.URL) *servingv1.Service {
+	return b.BuildAdapterKnService(r, sinkURI)
+}
+
+// ReconcileAdapter reconciles the adapter of a router using the AdapterMode
+// which applies to the router's namespace.
+func (r *GenericAdapterReconciler) ReconcileAdapter(ctx context.Context, ab AdapterBuilder) reconciler.Event {
+	router := v1alpha1.RouterFromContext(ctx)
+
+	mode, err := r.adapterMode(router.GetNamespace())
+	if err != nil {
+		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning,
+			ReasonInvalidAdapterMode, "Could not determine the adapter mode: %s", err))
+	}
+
+	if err := r.deleteStaleAdapters(ctx, mode); err != nil {
+		return err
+	}
+
+	// the adapter acknowledges the configuration it loaded independently
+	// from the reconciliation of its Deployment/KnService
+	router.GetStatusManager().PropagateAdapterSync(router)
+
+	switch mode {
+	case AdapterModeDeployment:
+		return r.Deployment.ReconcileAdapter(ctx, adapterDeploymentBuilder{ab})
+	default:
+		return r.Service.ReconcileAdapter(ctx, adapterServiceBuilder{ab})
+	}
+}
+
+// adapterMode returns the AdapterMode which applies to the given namespace.
+func (r *GenericAdapterReconciler) adapterMode(namespace string) (AdapterMode, error) {
+	ns, err := r.NamespaceLister.Get(namespace)
+	if err != nil {
+		return "", fmt.Errorf("getting Namespace %q from cache: %w", namespace, err)
+	}
+
+	mode, err := namespaceAdapterMode(ns, r.Mode)
+	if err != nil {
+		return "", fmt.Errorf("reading annotation %s of Namespace %q: %w", AdapterModeAnnotation, namespace, err)
+	}
+
+	if mode == AdapterModeKnService && r.Service == nil {
+		return "", fmt.Errorf("adapter mode %q was requested by Namespace %q but support for "+
+			"Knative Serving is disabled in the controller", mode, namespace)
+	}
+
+	return mode, nil
+}
+
+// deleteStaleAdapters deletes the adapter objects which may have been left
+// behind for the router in the current namespace after the adapter mode of
+// that namespace changed, or after the router opted in or out of a dedicated
+// adapter.
+func (r *GenericAdapterReconciler) deleteStaleAdapters(ctx context.Context, mode AdapterMode) error {
+	router := v1alpha1.RouterFromContext(ctx)
+
+	isMultiTenant := v1alpha1.IsMultiTenant(router)
+
+	// The multi-tenant adapter is shared between all routers of a given
+	// type, so it is only stale if it was created for another adapter mode.
+	// Dedicated adapters are only deleted if owned by the current router.
+	if mode != AdapterModeKnService {
+		if err := r.deleteKnService(ctx, MTAdapterObjectName(router), nil); err != nil {
+			return err
+		}
+	}
+	if mode != AdapterModeDeployment {
+		if err := r.deleteDeployment(ctx, MTAdapterObjectName(router), nil); err != nil {
+			return err
+		}
+	}
+	if isMultiTenant || mode != AdapterModeKnService {
+		if err := r.deleteKnService(ctx, DedicatedAdapterObjectName(router), router); err != nil {
+			return err
+		}
+	}
+	if isMultiTenant || mode != AdapterModeDeployment {
+		if err := r.deleteDeployment(ctx, DedicatedAdapterObjectName(router), router); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteKnService deletes the adapter Knative Service with the given name,
+// if it exists and is controlled by the given owner. A nil owner matches any
+// Knative Service.
+func (r *GenericAdapterReconciler) deleteKnService(ctx context.Context, name string, owner metav1.Object) error {
+	if r.Service == nil {
+		return nil
+	}
+
+	ns := v1alpha1.RouterFromContext(ctx).GetNamespace()
+
+	ksvc, err := r.Service.Lister(ns).Get(name)
+	return deleteStaleObject(ctx, "Knative Service", ksvc, err, owner, r.Service.Client(ns).Delete)
+}
+
+// deleteDeployment deletes the adapter Deployment with the given name and the
+// Service which exposes it, if they exist and are controlled by the given
+// owner. A nil owner matches any object.
+func (r *GenericAdapterReconciler) deleteDeployment(ctx context.Context, name string, owner metav1.Object) error {
+	ns := v1alpha1.RouterFromContext(ctx).GetNamespace()
+
+	depl, err := r.Deployment.Lister(ns).Get(name)
+	if err := deleteStaleObject(ctx, "Deployment", depl, err, owner, r.Deployment.Client(ns).Delete); err != nil {
+		return err
+	}
+
+	svc, err := r.Deployment.ServiceLister(ns).Get(name)
+	return deleteStaleObject(ctx, "Service", svc, err, owner, r.Deployment.ServiceClient(ns).Delete)
+}
+
+// deleteStaleObject deletes an adapter object that was looked up in a cache,
+// unless the lookup yielded no result or the object isn't controlled by the
+// given owner.
+func deleteStaleObject(ctx context.Context, kind string, obj metav1.Object, getErr error, owner metav1.Object,
+	deleteFn func(context.Context, string, metav1.DeleteOptions) error) error {
+
+	switch {
+	case apierrors.IsNotFound(getErr):
+		return nil
+	case getErr != nil:
+		return fmt.Errorf("failed to get adapter %s from cache: %w", kind, getErr)
+	}
+
+	if owner != nil && !metav1.IsControlledBy(obj, owner) {
+		return nil
+	}
+
+	err := deleteFn(ctx, obj.GetName(), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return reconciler.NewEvent(corev1.EventTypeWarning, ReasonFailedAdapterDelete,
+			"Failed to delete stale adapter %s %q: %s", kind, obj.GetName(), err)
+	}
+	event.Normal(ctx, ReasonAdapterDelete, "Deleted stale adapter %s %q", kind, obj.GetName())
+
+	return nil
 }
 
 // ReconcileSource reconciles an event source type.
 func (r *GenericDeploymentReconciler) ReconcileAdapter(ctx context.Context, ab AdapterDeploymentBuilder) reconciler.Event {
 	router := v1alpha1.RouterFromContext(ctx)
 
-	router.GetStatusManager().CloudEventAttributes = CreateCloudEventAttributes(
-		router.AsRouter(), router.GetEventTypes())
+	router.GetStatusManager().CloudEventAttributes = routerCloudEventAttributes(router)
 
 	sinkURI, err := r.resolveSinkURL(ctx)
 	if err != nil {
@@ -82,7 +254,9 @@ func (r *GenericDeploymentReconciler) ReconcileAdapter(ctx context.Context, ab A
 		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning,
 			ReasonBadSinkURI, "Could not resolve sink URI: %s", err))
 	}
-	router.GetStatusManager().MarkSink(sinkURI)
+	if err := markSinks(ctx, r.SinkResolver, sinkURI); err != nil {
+		return err
+	}
 
 	desiredAdapter := ab.BuildAdapter(router, sinkURI)
 
@@ -100,13 +274,66 @@ func (r *GenericDeploymentReconciler) ReconcileAdapter(ctx context.Context, ab A
 // resolveSinkURL resolves the URL of a sink reference.
 func (r *GenericDeploymentReconciler) resolveSinkURL(ctx context.Context) (*apis.URL, error) {
 	router := v1alpha1.RouterFromContext(ctx)
-	sink := *router.GetSink()
 
-	if sinkRef := &router.GetSink().Ref; *sinkRef != nil && (*sinkRef).Namespace == "" {
-		(*sinkRef).Namespace = router.GetNamespace()
+	return resolveDestination(ctx, r.SinkResolver, router, router.GetSink())
+}
+
+// resolveDestination resolves the URL of the given destination, which may be
+// nil in the case of optional sinks.
+func resolveDestination(ctx context.Context, sr *resolver.URIResolver,
+	router v1alpha1.Reconcilable, dest *duckv1.Destination) (*apis.URL, error) {
+
+	if dest == nil {
+		return nil, nil
+	}
+
+	if dest.Ref != nil && dest.Ref.Namespace == "" {
+		dest.Ref.Namespace = router.GetNamespace()
 	}
 
-	return r.SinkResolver.URIFromDestinationV1(ctx, sink, router)
+	return sr.URIFromDestinationV1(ctx, *dest, router)
+}
+
+// markSinks resolves the sinks of the router's routes and its error sink, if
+// any, and reflects the resolved URIs in the router's status.
+func markSinks(ctx context.Context, sr *resolver.URIResolver, sinkURI *apis.URL) error {
+	router := v1alpha1.RouterFromContext(ctx)
+
+	errorSinkURI, err := resolveDestination(ctx, sr, router, v1alpha1.ErrorSink(router))
+	if err != nil {
+		router.GetStatusManager().MarkNoSink()
+		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning,
+			ReasonBadSinkURI, "Could not resolve error sink URI: %s", err))
+	}
+	router.GetStatusManager().MarkErrorSink(errorSinkURI)
+
+	deadLetterSinkURI, err := resolveDestination(ctx, sr, router, v1alpha1.DeadLetterSink(router))
+	if err != nil {
+		router.GetStatusManager().MarkNoSink()
+		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning,
+			ReasonBadSinkURI, "Could not resolve dead letter sink URI: %s", err))
+	}
+	router.GetStatusManager().MarkDeadLetterSink(deadLetterSinkURI)
+
+	routeSinks := v1alpha1.RouteSinks(router)
+	if routeSinks == nil {
+		router.GetStatusManager().MarkSink(sinkURI)
+		return nil
+	}
+
+	routeURIs := make([]*apis.URL, len(routeSinks))
+	for i, sink := range routeSinks {
+		uri, err := resolveDestination(ctx, sr, router, sink)
+		if err != nil {
+			router.GetStatusManager().MarkNoSink()
+			return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning,
+				ReasonBadSinkURI, "Could not resolve sink URI of route %d: %s", i, err))
+		}
+		routeURIs[i] = uri
+	}
+	router.GetStatusManager().MarkRouteSinks(sinkURI, routeURIs)
+
+	return nil
 }
 
 // reconcileAdapter reconciles the state of the source's adapter.
@@ -140,6 +367,26 @@ func (r *GenericDeploymentReconciler) reconcileAdapter(ctx context.Context,
 	}
 	router.GetStatusManager().PropagateDeploymentAvailability(ctx, currentAdapter, r.PodClient(router.GetNamespace()))
 
+	// adapters are not addressable by themselves when backed by a
+	// Deployment, so they are exposed via a Service
+	desiredSvc := newRouterAdapterService(router)
+	if v1alpha1.IsMultiTenant(router) {
+		OwnByServiceAccount(desiredSvc, sa)
+	}
+
+	currentSvc, err := r.getOrCreateAdapterService(ctx, desiredSvc)
+	if err != nil {
+		return err
+	}
+
+	currentSvc, err = r.syncAdapterService(ctx, currentSvc, desiredSvc)
+	if err != nil {
+		return fmt.Errorf("failed to synchronize adapter Service: %w", err)
+	}
+
+	router.GetStatusManager().SetAddress(serviceURL(currentSvc))
+	router.GetStatusManager().SetRoute(URLPath(router))
+
 	return nil
 }
 
@@ -191,12 +438,67 @@ func (r *GenericDeploymentReconciler) syncAdapterDeployment(ctx context.Context,
 	return adapter, nil
 }
 
+// getOrCreateAdapterService returns the existing Service which exposes the
+// adapter Deployment of a given source, or creates it if it is missing.
+func (r *GenericDeploymentReconciler) getOrCreateAdapterService(ctx context.Context, desiredSvc *corev1.Service) (*corev1.Service, error) {
+	svc, err := r.ServiceLister(desiredSvc.Namespace).Get(desiredSvc.Name)
+	switch {
+	case apierrors.IsNotFound(err):
+		svc, err = r.ServiceClient(desiredSvc.Namespace).Create(ctx, desiredSvc, metav1.CreateOptions{})
+		if err != nil {
+			return nil, reconciler.NewEvent(corev1.EventTypeWarning, ReasonFailedAdapterCreate,
+				"Failed to create adapter Service %q: %s", desiredSvc.Name, err)
+		}
+		event.Normal(ctx, ReasonAdapterCreate, "Created adapter Service %q", svc.Name)
+
+	case err != nil:
+		return nil, fmt.Errorf("failed to get adapter Service from cache: %w", err)
+	}
+
+	return svc, nil
+}
+
+// syncAdapterService synchronizes the desired state of the Service which
+// exposes an adapter Deployment against its current state in the running
+// cluster.
+func (r *GenericDeploymentReconciler) syncAdapterService(ctx context.Context,
+	currentSvc, desiredSvc *corev1.Service) (*corev1.Service, error) {
+
+	if semantic.Semantic.DeepEqual(desiredSvc, currentSvc) {
+		return currentSvc, nil
+	}
+
+	// resourceVersion must be returned to the API server unmodified for
+	// optimistic concurrency, as per Kubernetes API conventions
+	desiredSvc.ResourceVersion = currentSvc.ResourceVersion
+
+	// the cluster IP is immutable once allocated
+	desiredSvc.Spec.ClusterIP = currentSvc.Spec.ClusterIP
+	desiredSvc.Spec.ClusterIPs = currentSvc.Spec.ClusterIPs
+
+	svc, err := r.ServiceClient(currentSvc.Namespace).Update(ctx, desiredSvc, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, reconciler.NewEvent(corev1.EventTypeWarning, ReasonFailedAdapterUpdate,
+			"Failed to update adapter Service %q: %s", desiredSvc.Name, err)
+	}
+	event.Normal(ctx, ReasonAdapterUpdate, "Updated adapter Service %q", svc.Name)
+
+	return svc, nil
+}
+
+// serviceURL returns the cluster-local URL of the given Service.
+func serviceURL(svc *corev1.Service) *apis.URL {
+	return &apis.URL{
+		Scheme: "http",
+		Host:   network.GetServiceHostname(svc.Name, svc.Namespace),
+	}
+}
+
 // ReconcileSource reconciles an event source type.
 func (r *GenericServiceReconciler) ReconcileAdapter(ctx context.Context, ab AdapterServiceBuilder) reconciler.Event {
 	router := v1alpha1.RouterFromContext(ctx)
 
-	router.GetStatusManager().CloudEventAttributes = CreateCloudEventAttributes(
-		router.AsRouter(), router.GetEventTypes())
+	router.GetStatusManager().CloudEventAttributes = routerCloudEventAttributes(router)
 
 	sinkURI, err := r.resolveSinkURL(ctx)
 	if err != nil {
@@ -204,7 +506,9 @@ func (r *GenericServiceReconciler) ReconcileAdapter(ctx context.Context, ab Adap
 		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning,
 			ReasonBadSinkURI, "Could not resolve sink URI: %s", err))
 	}
-	router.GetStatusManager().MarkSink(sinkURI)
+	if err := markSinks(ctx, r.SinkResolver, sinkURI); err != nil {
+		return err
+	}
 
 	desiredAdapter := ab.BuildAdapter(router, sinkURI)
 
@@ -222,13 +526,8 @@ func (r *GenericServiceReconciler) ReconcileAdapter(ctx context.Context, ab Adap
 // resolveSinkURL resolves the URL of a sink reference.
 func (r *GenericServiceReconciler) resolveSinkURL(ctx context.Context) (*apis.URL, error) {
 	router := v1alpha1.RouterFromContext(ctx)
-	sink := *router.GetSink()
 
-	if sinkRef := &router.GetSink().Ref; *sinkRef != nil && (*sinkRef).Namespace == "" {
-		(*sinkRef).Namespace = router.GetNamespace()
-	}
-
-	return r.SinkResolver.URIFromDestinationV1(ctx, sink, router)
+	return resolveDestination(ctx, r.SinkResolver, router, router.GetSink())
 }
 
 // reconcileAdapter reconciles the state of the source's adapter.
@@ -264,9 +563,9 @@ func (r *GenericServiceReconciler) reconcileAdapter(ctx context.Context,
 	}
 
 	router.GetStatusManager().PropagateServiceAvailability(currentAdapter)
-	if isMultiTenant {
-		router.GetStatusManager().SetRoute(URLPath(router))
-	}
+	// adapters, including dedicated ones, select the router which handles
+	// an event based on the request's URL path
+	router.GetStatusManager().SetRoute(URLPath(router))
 
 	return nil
 }
@@ -328,7 +627,7 @@ func (r *GenericServiceReconciler) syncAdapterService(ctx context.Context,
 
 // findAdapter returns the adapter object for a given source if it exists.
 func findAdapter(genericReconciler interface{},
-	router v1alpha1.Router, owner *metav1.OwnerReference) (metav1.Object, error) {
+	router v1alpha1.Reconcilable, owner *metav1.OwnerReference) (metav1.Object, error) {
 
 	ls := CommonObjectLabels(router)
 
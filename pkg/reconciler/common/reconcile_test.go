@@ -0,0 +1,93 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/resolver"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestMarkSinksDeadLetterSink(t *testing.T) {
+	sinkURI := apis.HTTP("sink.ns.svc.cluster.local")
+	dlsURI := apis.HTTP("dls.ns.svc.cluster.local")
+
+	testCases := map[string]struct {
+		delivery  *eventingduckv1.DeliverySpec
+		expectDLS *apis.URL
+		expectErr bool
+	}{
+		"no delivery spec": {
+			delivery: nil,
+		},
+		"no dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{},
+		},
+		"dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{URI: dlsURI},
+			},
+			expectDLS: dlsURI,
+		},
+		"unresolvable dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Path: "/relative"}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			f := &v1alpha1.Filter{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "test"},
+				Spec: v1alpha1.FilterSpec{
+					Delivery: tc.delivery,
+				},
+			}
+			// stale value which must be overwritten
+			f.Status.DeadLetterSinkURI = apis.HTTP("stale.ns.svc.cluster.local")
+
+			ctx := v1alpha1.WithRouter(context.Background(), f)
+
+			// URI destinations are resolved without looking up objects
+			err := markSinks(ctx, &resolver.URIResolver{}, sinkURI)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, f.Status.SinkURI)
+				assert.Nil(t, f.Status.DeadLetterSinkURI)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, sinkURI, f.Status.SinkURI)
+			assert.Equal(t, tc.expectDLS, f.Status.DeadLetterSinkURI)
+		})
+	}
+}
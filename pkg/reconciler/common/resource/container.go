@@ -220,13 +220,23 @@ func resourcesFrom(object interface{}) (resources *corev1.ResourceRequirements)
 	return
 }
 
+// setResources sets the CPU and memory quantities of the given ResourceList.
+// Zero quantities are ignored.
 func setResources(res *corev1.ResourceList, cpu, mem resource.Quantity) {
+	if cpu.IsZero() && mem.IsZero() {
+		return
+	}
+
 	if *res == nil {
 		*res = make(corev1.ResourceList, 2)
 	}
 
-	(*res)[corev1.ResourceCPU] = cpu
-	(*res)[corev1.ResourceMemory] = mem
+	if !cpu.IsZero() {
+		(*res)[corev1.ResourceCPU] = cpu
+	}
+	if !mem.IsZero() {
+		(*res)[corev1.ResourceMemory] = mem
+	}
 }
 
 // TerminationErrorToLogs sets the TerminationMessagePolicy of a container to
@@ -18,6 +18,7 @@ package resource
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -45,18 +46,37 @@ func NewDeployment(ns, name string, opts ...ObjectOption) *appsv1.Deployment {
 }
 
 // Selector adds a label selector to a Deployment's spec, ensuring a
-// corresponding label exists in the Pod template.
+// corresponding label exists in the Pod template. When applied to a Service,
+// the label is added to the selector of the Pods targeted by that Service.
 func Selector(key, val string) ObjectOption {
 	return func(object interface{}) {
-		d := object.(*appsv1.Deployment)
+		switch o := object.(type) {
+		case *appsv1.Deployment:
+			selector := &o.Spec.Selector
+
+			if *selector == nil {
+				*selector = &metav1.LabelSelector{}
+			}
+			*selector = metav1.AddLabelToSelector(*selector, key, val)
 
-		selector := &d.Spec.Selector
+			PodLabel(key, val)(o)
 
-		if *selector == nil {
-			*selector = &metav1.LabelSelector{}
+		case *corev1.Service:
+			selector := &o.Spec.Selector
+
+			if *selector == nil {
+				*selector = make(map[string]string, 1)
+			}
+			(*selector)[key] = val
 		}
-		*selector = metav1.AddLabelToSelector(*selector, key, val)
+	}
+}
+
+// Replicas sets the number of replicas of a Deployment.
+func Replicas(n int32) ObjectOption {
+	return func(object interface{}) {
+		d := object.(*appsv1.Deployment)
 
-		PodLabel(key, val)(d)
+		d.Spec.Replicas = &n
 	}
 }
@@ -26,6 +26,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"knative.dev/pkg/ptr"
 )
 
 func TestNewDeploymentWithDefaultContainer(t *testing.T) {
@@ -48,6 +50,11 @@ func TestNewDeploymentWithDefaultContainer(t *testing.T) {
 		Requests(resource.MustParse("250m"), resource.MustParse("100Mi")),
 		Limits(resource.MustParse("250m"), resource.MustParse("100Mi")),
 		TerminationErrorToLogs,
+		Replicas(3),
+		PodAnnotation("test.podannotation/1", "val1"),
+		NodeSelector(map[string]string{"test.node/1": "val1"}),
+		Toleration(corev1.Toleration{Key: "test.taint/1", Operator: corev1.TolerationOpExists}),
+		PriorityClassName("high"),
 	)
 
 	expectDepl := &appsv1.Deployment{
@@ -60,6 +67,7 @@ func TestNewDeploymentWithDefaultContainer(t *testing.T) {
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.Int32(3),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"test.selector/1": "val1",
@@ -74,9 +82,20 @@ func TestNewDeploymentWithDefaultContainer(t *testing.T) {
 						"test.podlabel/1": "val1",
 						"test.podlabel/2": "val2",
 					},
+					Annotations: map[string]string{
+						"test.podannotation/1": "val1",
+					},
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: "god-mode",
+					NodeSelector: map[string]string{
+						"test.node/1": "val1",
+					},
+					Tolerations: []corev1.Toleration{{
+						Key:      "test.taint/1",
+						Operator: corev1.TolerationOpExists,
+					}},
+					PriorityClassName: "high",
 					Containers: []corev1.Container{{
 						Name:  defaultContainerName,
 						Image: tImg,
@@ -44,6 +44,10 @@ func TestNewServiceWithDefaultContainer(t *testing.T) {
 		ServiceAccount("god-mode"),
 		Requests(resource.MustParse("250m"), resource.MustParse("100Mi")),
 		Limits(resource.MustParse("250m"), resource.MustParse("100Mi")),
+		PodAnnotation("test.podannotation/1", "val1"),
+		NodeSelector(map[string]string{"test.node/1": "val1"}),
+		Toleration(corev1.Toleration{Key: "test.taint/1", Operator: corev1.TolerationOpExists}),
+		PriorityClassName("high"),
 	)
 
 	expectKsvc := &servingv1.Service{
@@ -63,10 +67,21 @@ func TestNewServiceWithDefaultContainer(t *testing.T) {
 							"test.podlabel/1": "val1",
 							"test.podlabel/2": "val2",
 						},
+						Annotations: map[string]string{
+							"test.podannotation/1": "val1",
+						},
 					},
 					Spec: servingv1.RevisionSpec{
 						PodSpec: corev1.PodSpec{
 							ServiceAccountName: "god-mode",
+							NodeSelector: map[string]string{
+								"test.node/1": "val1",
+							},
+							Tolerations: []corev1.Toleration{{
+								Key:      "test.taint/1",
+								Operator: corev1.TolerationOpExists,
+							}},
+							PriorityClassName: "high",
 							Containers: []corev1.Container{{
 								Name:  defaultContainerName,
 								Image: tImg,
@@ -40,6 +40,29 @@ func PodLabel(key, val string) ObjectOption {
 	}
 }
 
+// PodAnnotation sets the value of an annotation of a PodSpecable's Pod
+// template.
+func PodAnnotation(key, val string) ObjectOption {
+	return func(object interface{}) {
+		var metaObj metav1.Object
+
+		switch o := object.(type) {
+		case *appsv1.Deployment:
+			metaObj = &o.Spec.Template
+		case *servingv1.Service:
+			metaObj = &o.Spec.Template
+		}
+
+		anns := metaObj.GetAnnotations()
+
+		if anns == nil {
+			anns = make(map[string]string, 1)
+			metaObj.SetAnnotations(anns)
+		}
+		anns[key] = val
+	}
+}
+
 // Container adds a container to a PodSpecable's Pod template.
 func Container(c *corev1.Container) ObjectOption {
 	return func(object interface{}) {
@@ -69,3 +92,39 @@ func ServiceAccount(sa string) ObjectOption {
 		*saName = sa
 	}
 }
+
+// NodeSelector sets the node selector of a PodSpecable.
+func NodeSelector(sel map[string]string) ObjectOption {
+	return func(object interface{}) {
+		podSpecFrom(object).NodeSelector = sel
+	}
+}
+
+// Toleration adds a toleration to a PodSpecable.
+func Toleration(t corev1.Toleration) ObjectOption {
+	return func(object interface{}) {
+		tolerations := &podSpecFrom(object).Tolerations
+		*tolerations = append(*tolerations, t)
+	}
+}
+
+// PriorityClassName sets the name of the PriorityClass of a PodSpecable.
+func PriorityClassName(name string) ObjectOption {
+	return func(object interface{}) {
+		podSpecFrom(object).PriorityClassName = name
+	}
+}
+
+// podSpecFrom returns the PodSpec of a PodSpecable's Pod template.
+func podSpecFrom(object interface{}) *corev1.PodSpec {
+	var podSpec *corev1.PodSpec
+
+	switch o := object.(type) {
+	case *appsv1.Deployment:
+		podSpec = &o.Spec.Template.Spec
+	case *servingv1.Service:
+		podSpec = &o.Spec.Template.Spec.PodSpec
+	}
+
+	return podSpec
+}
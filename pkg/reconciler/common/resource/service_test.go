@@ -0,0 +1,69 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestNewService(t *testing.T) {
+	svc := NewService(tNs, tName,
+		Label("test.label/1", "val1"),
+		Selector("test.selector/1", "val1"),
+		ServicePort("http", 80, "http"),
+		ServicePort("metrics", 9090, "metrics"),
+		Label("test.label/2", "val2"),
+		Selector("test.selector/2", "val2"),
+		ServicePort("http", 8080, "h2c"), // overrides previously defined port
+	)
+
+	expectSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: tNs,
+			Name:      tName,
+			Labels: map[string]string{
+				"test.label/1": "val1",
+				"test.label/2": "val2",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"test.selector/1": "val1",
+				"test.selector/2": "val2",
+			},
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       8080,
+				TargetPort: intstr.FromString("h2c"),
+			}, {
+				Name:       "metrics",
+				Port:       9090,
+				TargetPort: intstr.FromString("metrics"),
+			}},
+		},
+	}
+
+	if d := cmp.Diff(expectSvc, svc); d != "" {
+		t.Errorf("Unexpected diff: (-:expect, +:got) %s", d)
+	}
+}
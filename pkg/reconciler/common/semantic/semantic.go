@@ -37,6 +37,7 @@ import (
 var Semantic = conversion.EqualitiesOrDie(
 	deploymentEqual,
 	knServiceEqual,
+	serviceEqual,
 )
 
 // eq is an instance of Equalities for internal deep derivative comparisons
@@ -129,3 +130,23 @@ func knServiceEqual(a, b *servingv1.Service) bool {
 
 	return true
 }
+
+// serviceEqual returns whether two Services are semantically equivalent.
+func serviceEqual(a, b *corev1.Service) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+
+	if !eq.DeepDerivative(&a.ObjectMeta, &b.ObjectMeta) {
+		return false
+	}
+
+	if !eq.DeepDerivative(&a.Spec, &b.Spec) {
+		return false
+	}
+
+	return true
+}
@@ -16,7 +16,11 @@ limitations under the License.
 
 package common
 
-import duckv1 "knative.dev/pkg/apis/duck/v1"
+import (
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
 
 // CreateCloudEventAttributes returns CloudEvent attributes for the event types
 // supported by the source.
@@ -32,3 +36,12 @@ func CreateCloudEventAttributes(source string, eventTypes []string) []duckv1.Clo
 
 	return ceAttributes
 }
+
+// routerCloudEventAttributes returns the CloudEvent attributes of the events
+// produced by the given router.
+func routerCloudEventAttributes(r v1alpha1.Reconcilable) []duckv1.CloudEventAttributes {
+	if attrs := v1alpha1.CloudEventAttributes(r); attrs != nil {
+		return attrs
+	}
+	return CreateCloudEventAttributes(r.AsRouter(), r.GetEventTypes())
+}
@@ -18,6 +18,7 @@ package filter
 
 import (
 	"context"
+	"fmt"
 
 	"knative.dev/pkg/reconciler"
 
@@ -25,13 +26,16 @@ import (
 	routingv1alpha1 "github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
 	filterreconciler "github.com/triggermesh/routing/pkg/client/generated/injection/reconciler/flow/v1alpha1/filter"
 	listersv1alpha1 "github.com/triggermesh/routing/pkg/client/generated/listers/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+	"github.com/triggermesh/routing/pkg/eventtransform"
 	"github.com/triggermesh/routing/pkg/reconciler/common"
+	"github.com/triggermesh/routing/pkg/reconciler/common/event"
 )
 
 // Reconciler implements addressableservicereconciler.Interface for
 // AddressableService resources.
 type Reconciler struct {
-	base         common.GenericServiceReconciler
+	base         common.GenericAdapterReconciler
 	filterLister func(namespace string) listersv1alpha1.FilterNamespaceLister
 	adapterCfg   *adapterConfig
 }
@@ -44,5 +48,43 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, o *routingv1alpha1.Filte
 	// inject source into context for usage in reconciliation logic
 	ctx = v1alpha1.WithRouter(ctx, o)
 
+	reconcileExpression(ctx, o)
+
 	return r.base.ReconcileAdapter(ctx, r)
 }
+
+// reconcileExpression compiles the expression and the transform of the given
+// Filter and reflects the outcome in its status. A Filter whose expression
+// does not compile can still be persisted if it was not admitted by the
+// webhook, e.g. after an upgrade.
+func reconcileExpression(ctx context.Context, f *routingv1alpha1.Filter) {
+	if err := compile(f); err != nil {
+		cond := f.Status.GetCondition(v1alpha1.ConditionExpressionValid)
+		f.GetStatusManager().MarkExpressionInvalid(err)
+
+		// avoid recording the same event at each reconciliation
+		if newCond := f.Status.GetCondition(v1alpha1.ConditionExpressionValid); cond == nil ||
+			cond.Status != newCond.Status || cond.Message != newCond.Message {
+
+			event.Warn(ctx, common.ReasonInvalidExpression, "Failed to compile expression: %s", err)
+		}
+		return
+	}
+
+	f.GetStatusManager().MarkExpressionValid()
+}
+
+// compile compiles the expression and the transform of the given Filter.
+func compile(f *routingv1alpha1.Filter) error {
+	if _, err := cel.CompileExpression(f.Spec.Expression); err != nil {
+		return err
+	}
+
+	if t := f.Spec.Transform; t != nil {
+		if _, err := eventtransform.Compile(t.TransformOperations()); err != nil {
+			return fmt.Errorf("transform: %w", err)
+		}
+	}
+
+	return nil
+}
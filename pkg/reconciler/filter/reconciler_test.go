@@ -0,0 +1,162 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"knative.dev/pkg/controller"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/reconciler/common"
+)
+
+func TestReconcileExpression(t *testing.T) {
+	testCases := map[string]struct {
+		expression      string
+		transform       *v1alpha1.FilterTransform
+		expectStatus    corev1.ConditionStatus
+		expectMsgSubstr string
+	}{
+		"valid expression": {
+			expression:   `ce.type == "test"`,
+			expectStatus: corev1.ConditionTrue,
+		},
+		"valid transform": {
+			expression: `ce.type == "test"`,
+			transform: &v1alpha1.FilterTransform{
+				Operations: []v1alpha1.TransformOperation{{
+					Operation:  v1alpha1.TransformOperationSet,
+					Path:       "ce.type",
+					Expression: `"test.transformed"`,
+				}},
+			},
+			expectStatus: corev1.ConditionTrue,
+		},
+		"invalid expression": {
+			expression:      `ce.type ==`,
+			expectStatus:    corev1.ConditionFalse,
+			expectMsgSubstr: "The expression does not compile",
+		},
+		"invalid transform": {
+			expression: `ce.type == "test"`,
+			transform: &v1alpha1.FilterTransform{
+				Operations: []v1alpha1.TransformOperation{{
+					Operation:  v1alpha1.TransformOperationSet,
+					Path:       "ce.type",
+					Expression: `ce.type +`,
+				}},
+			},
+			expectStatus:    corev1.ConditionFalse,
+			expectMsgSubstr: "transform: ",
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			f := newTestFilter(tc.expression, tc.transform)
+			er := record.NewFakeRecorder(10)
+
+			reconcileExpression(newTestContext(er, f), f)
+
+			cond := f.Status.GetCondition(v1alpha1.ConditionExpressionValid)
+			require.NotNil(t, cond, "ExpressionValid condition should be set")
+			assert.Equal(t, tc.expectStatus, cond.Status)
+
+			events := drainEvents(er)
+
+			if tc.expectStatus == corev1.ConditionTrue {
+				assert.Empty(t, cond.Reason)
+				assert.Empty(t, events)
+				return
+			}
+
+			assert.Equal(t, v1alpha1.ReasonInvalidExpression, cond.Reason)
+			assert.Contains(t, cond.Message, tc.expectMsgSubstr)
+			require.Len(t, events, 1)
+			assert.Contains(t, events[0], corev1.EventTypeWarning+" "+common.ReasonInvalidExpression)
+		})
+	}
+}
+
+func TestReconcileExpressionWarnings(t *testing.T) {
+	f := newTestFilter(`ce.type ==`, nil)
+	er := record.NewFakeRecorder(10)
+	ctx := newTestContext(er, f)
+
+	reconcileExpression(ctx, f)
+	assert.Len(t, drainEvents(er), 1, "Expected a warning for the invalid expression")
+
+	reconcileExpression(ctx, f)
+	assert.Empty(t, drainEvents(er), "Expected no warning for an unchanged error")
+
+	f.Spec.Expression = `ce.source ==`
+	reconcileExpression(ctx, f)
+	assert.Len(t, drainEvents(er), 1, "Expected a warning for a different error")
+
+	f.Spec.Expression = `ce.type == "test"`
+	reconcileExpression(ctx, f)
+	assert.Empty(t, drainEvents(er), "Expected no warning for a valid expression")
+	assert.True(t, f.Status.GetCondition(v1alpha1.ConditionExpressionValid).IsTrue())
+
+	f.Spec.Expression = `ce.source ==`
+	reconcileExpression(ctx, f)
+	assert.Len(t, drainEvents(er), 1, "Expected a warning when the expression becomes invalid again")
+}
+
+// newTestFilter returns a Filter with the given expression and transform.
+func newTestFilter(expression string, transform *v1alpha1.FilterTransform) *v1alpha1.Filter {
+	return &v1alpha1.Filter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "test",
+		},
+		Spec: v1alpha1.FilterSpec{
+			Expression: expression,
+			Transform:  transform,
+		},
+	}
+}
+
+// newTestContext returns a context which records events of the given Filter
+// to the given recorder.
+func newTestContext(er record.EventRecorder, f *v1alpha1.Filter) context.Context {
+	ctx := controller.WithEventRecorder(context.Background(), er)
+	return v1alpha1.WithRouter(ctx, f)
+}
+
+// drainEvents returns the events recorded so far by the given recorder.
+func drainEvents(er *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case ev := <-er.Events:
+			events = append(events, ev)
+		default:
+			return events
+		}
+	}
+}
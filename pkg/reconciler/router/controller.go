@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"time"
+
+	"knative.dev/eventing/pkg/reconciler/source"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	routerinformer "github.com/triggermesh/routing/pkg/client/generated/injection/informers/flow/v1alpha1/router"
+	routerreconciler "github.com/triggermesh/routing/pkg/client/generated/injection/reconciler/flow/v1alpha1/router"
+	"github.com/triggermesh/routing/pkg/reconciler/common"
+)
+
+// the resync period ensures we regularly re-check the state of Routers.
+const informerResyncPeriod = time.Minute * 5
+
+// New creates a Reconciler and returns the result of NewImpl.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	typ := (*v1alpha1.Router)(nil)
+	app := common.ComponentName(typ)
+	informer := routerinformer.Get(ctx)
+
+	// Calling envconfig.Process() with a prefix appends that prefix
+	// (uppercased) to the Go field name, e.g. MYSOURCE_IMAGE.
+	adapterCfg := &adapterConfig{
+		configs: source.WatchConfigurations(ctx, app, cmw, source.WithLogging, source.WithMetrics, source.WithTracing),
+	}
+	envconfig.MustProcess(app, adapterCfg)
+
+	r := &Reconciler{
+		adapterCfg:   adapterCfg,
+		routerLister: informer.Lister().Routers,
+	}
+
+	impl := routerreconciler.NewImpl(ctx, r)
+	logger := logging.FromContext(ctx)
+
+	r.base = common.NewGenericAdapterReconciler(
+		ctx,
+		typ,
+		impl.EnqueueKey,
+		common.EnqueueObjectsInNamespaceOf(informer.Informer(), impl.FilteredGlobalResync, logger),
+	)
+
+	informer.Informer().AddEventHandlerWithResyncPeriod(controller.HandleAll(impl.Enqueue), informerResyncPeriod)
+
+	return impl
+}
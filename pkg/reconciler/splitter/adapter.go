@@ -19,6 +19,7 @@ package splitter
 import (
 	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"knative.dev/eventing/pkg/reconciler/source"
 	"knative.dev/pkg/apis"
@@ -40,18 +41,26 @@ type adapterConfig struct {
 	configs source.ConfigAccessor
 }
 
-// Verify that Reconciler implements common.AdapterServiceBuilder.
-var _ common.AdapterServiceBuilder = (*Reconciler)(nil)
+// Verify that Reconciler implements common.AdapterBuilder.
+var _ common.AdapterBuilder = (*Reconciler)(nil)
 
-// BuildAdapter implements common.AdapterDeploymentBuilder.
-func (r *Reconciler) BuildAdapter(src v1alpha1.Router, _ *apis.URL) *servingv1.Service {
-	return common.NewMTAdapterKnService(src,
+// BuildAdapterKnService implements common.AdapterBuilder.
+func (r *Reconciler) BuildAdapterKnService(src v1alpha1.Reconcilable, sinkURI *apis.URL) *servingv1.Service {
+	return common.NewRouterAdapterKnService(src, sinkURI,
 		resource.Image(r.adapterCfg.Image),
 		resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
 	)
 }
 
-// RBACOwners implements common.AdapterDeploymentBuilder.
+// BuildAdapterDeployment implements common.AdapterBuilder.
+func (r *Reconciler) BuildAdapterDeployment(src v1alpha1.Reconcilable, sinkURI *apis.URL) *appsv1.Deployment {
+	return common.NewRouterAdapterDeployment(src, sinkURI,
+		resource.Image(r.adapterCfg.Image),
+		resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
+	)
+}
+
+// RBACOwners implements common.AdapterBuilder.
 func (r *Reconciler) RBACOwners(namespace string) ([]kmeta.OwnerRefable, error) {
 	srcs, err := r.splitterLister(namespace).List(labels.Everything())
 	if err != nil {
@@ -47,7 +47,7 @@ func NewController(
 	// Calling envconfig.Process() with a prefix appends that prefix
 	// (uppercased) to the Go field name, e.g. MYSOURCE_IMAGE.
 	adapterCfg := &adapterConfig{
-		configs: source.WatchConfigurations(ctx, app, cmw, source.WithLogging, source.WithMetrics),
+		configs: source.WatchConfigurations(ctx, app, cmw, source.WithLogging, source.WithMetrics, source.WithTracing),
 	}
 	envconfig.MustProcess(app, adapterCfg)
 
@@ -59,7 +59,7 @@ func NewController(
 	impl := splitterreconciler.NewImpl(ctx, r)
 	logger := logging.FromContext(ctx)
 
-	r.base = common.NewMTGenericServiceReconciler(
+	r.base = common.NewGenericAdapterReconciler(
 		ctx,
 		typ,
 		impl.EnqueueKey,
@@ -31,7 +31,7 @@ import (
 // Reconciler implements addressableservicereconciler.Interface for
 // AddressableService resources.
 type Reconciler struct {
-	base           common.GenericServiceReconciler
+	base           common.GenericAdapterReconciler
 	splitterLister func(namespace string) listersv1alpha1.SplitterNamespaceLister
 	adapterCfg     *adapterConfig
 }
@@ -0,0 +1,78 @@
+/*
+Copyright (c) 2020-2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synchronizer
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"knative.dev/eventing/pkg/reconciler/source"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/reconciler/common"
+	"github.com/triggermesh/routing/pkg/reconciler/common/resource"
+)
+
+// adapterConfig contains properties used to configure the source's adapter.
+// These are automatically populated by envconfig.
+type adapterConfig struct {
+	// Container image
+	Image string `default:"gcr.io/triggermesh/synchronizer-adapter"`
+
+	// Configuration accessor for logging/metrics/tracing
+	configs source.ConfigAccessor
+}
+
+// Verify that Reconciler implements common.AdapterBuilder.
+var _ common.AdapterBuilder = (*Reconciler)(nil)
+
+// BuildAdapterKnService implements common.AdapterBuilder.
+func (r *Reconciler) BuildAdapterKnService(src v1alpha1.Reconcilable, sinkURI *apis.URL) *servingv1.Service {
+	// pending requests are kept in memory
+	return common.NewRouterAdapterKnService(src, sinkURI, append([]resource.ObjectOption{
+		resource.Image(r.adapterCfg.Image),
+		resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
+	}, common.SingleReplicaAdapterKnServiceOptions()...)...)
+}
+
+// BuildAdapterDeployment implements common.AdapterBuilder.
+func (r *Reconciler) BuildAdapterDeployment(src v1alpha1.Reconcilable, sinkURI *apis.URL) *appsv1.Deployment {
+	// pending requests are kept in memory
+	return common.NewRouterAdapterDeployment(src, sinkURI, append([]resource.ObjectOption{
+		resource.Image(r.adapterCfg.Image),
+		resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
+	}, common.SingleReplicaAdapterDeploymentOptions()...)...)
+}
+
+// RBACOwners implements common.AdapterBuilder.
+func (r *Reconciler) RBACOwners(namespace string) ([]kmeta.OwnerRefable, error) {
+	srcs, err := r.synchronizerLister(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing objects from cache: %w", err)
+	}
+
+	ownerRefables := make([]kmeta.OwnerRefable, len(srcs))
+	for i := range srcs {
+		ownerRefables[i] = srcs[i]
+	}
+
+	return ownerRefables, nil
+}
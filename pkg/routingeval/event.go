@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routingeval
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"regexp"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+// requestLine matches the request line of an HTTP request, e.g.
+// "POST / HTTP/1.1".
+var requestLine = regexp.MustCompile(`^[A-Z]+ \S+ HTTP/\d`)
+
+// ReadEvents reads the CloudEvents contained in the given data, in one of the
+// following formats:
+//   - structured JSON event
+//   - JSON array of structured events (batched)
+//   - binary event as an HTTP message: "ce-" prefixed headers, an empty line
+//     and the data, optionally preceded by an HTTP request line
+func ReadEvents(ctx context.Context, data []byte) ([]*event.Event, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("no event data")
+	}
+
+	switch trimmed[0] {
+	case '{':
+		e := event.New()
+		if err := json.Unmarshal(trimmed, &e); err != nil {
+			return nil, fmt.Errorf("decoding structured event: %w", err)
+		}
+		return []*event.Event{&e}, nil
+
+	case '[':
+		var batch []event.Event
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return nil, fmt.Errorf("decoding batch of structured events: %w", err)
+		}
+		events := make([]*event.Event, len(batch))
+		for i := range batch {
+			events[i] = &batch[i]
+		}
+		return events, nil
+	}
+
+	e, err := readBinaryEvent(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	return []*event.Event{e}, nil
+}
+
+// readBinaryEvent reads a CloudEvent in binary mode from an HTTP message.
+func readBinaryEvent(ctx context.Context, data []byte) (*event.Event, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+
+	var header http.Header
+
+	if first, _ := r.Peek(len("OPTIONS / HTTP/1")); requestLine.Match(first) {
+		req, err := http.ReadRequest(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading HTTP request: %w", err)
+		}
+		header = req.Header
+		r = bufio.NewReader(req.Body)
+	} else {
+		mimeHeader, err := textproto.NewReader(r).ReadMIMEHeader()
+		// headers which aren't followed by any data are terminated by EOF
+		if err != nil && !(err == io.EOF && len(mimeHeader) > 0) {
+			return nil, fmt.Errorf("reading binary event headers: %w", err)
+		}
+		header = http.Header(mimeHeader)
+	}
+
+	msg := cehttp.NewMessage(header, ioutil.NopCloser(r))
+	defer func() { _ = msg.Finish(nil) }()
+
+	e, err := binding.ToEvent(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("decoding binary event: %w", err)
+	}
+	return e, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routingeval
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/eventfilter"
+	"github.com/triggermesh/routing/pkg/eventfilter/cel"
+)
+
+// Decision is what a Filter does with an event.
+type Decision string
+
+// Filter decisions
+const (
+	// DecisionPass forwards the event to the sink.
+	DecisionPass Decision = "pass"
+	// DecisionDrop discards the event.
+	DecisionDrop Decision = "drop"
+	// DecisionRoute forwards the event to the error sink.
+	DecisionRoute Decision = "route"
+)
+
+// FilterEvaluator evaluates the expression of a Filter against events.
+type FilterEvaluator struct {
+	filter *v1alpha1.Filter
+	cond   cel.ConditionalFilter
+}
+
+// NewFilterEvaluator compiles the expression of the given Filter.
+func NewFilterEvaluator(f *v1alpha1.Filter) (*FilterEvaluator, error) {
+	cond, err := cel.CompileExpression(f.Spec.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("compiling expression: %w", err)
+	}
+
+	return &FilterEvaluator{
+		filter: f,
+		cond:   cond,
+	}, nil
+}
+
+// Evaluate returns the decision made by the Filter for the given event, the
+// same way the filter adapter does. When the evaluation of the expression
+// fails, the error is returned along with the decision made according to the
+// Filter's error policy.
+func (fe *FilterEvaluator) Evaluate(ctx context.Context, e *event.Event) (Decision, error) {
+	res, err := fe.cond.Evaluate(ctx, *e)
+	if err != nil {
+		switch fe.filter.Spec.OnError {
+		case v1alpha1.ErrorPolicyDrop:
+			return DecisionDrop, err
+		case v1alpha1.ErrorPolicyRoute:
+			return DecisionRoute, err
+		default:
+			return DecisionPass, err
+		}
+	}
+
+	if res == eventfilter.FailFilter {
+		return DecisionDrop, nil
+	}
+	return DecisionPass, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package routingeval evaluates routing objects against CloudEvents without
+// a Kubernetes cluster.
+package routingeval
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/yaml"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+	"github.com/triggermesh/routing/pkg/apis/flow/v1beta1"
+)
+
+// defaultNamespace is the namespace of objects which don't have one.
+const defaultNamespace = "default"
+
+// Object is a routing object which can be evaluated offline, either a
+// *v1alpha1.Filter or a *v1alpha1.Splitter.
+type Object interface {
+	apis.Defaultable
+	apis.Validatable
+	metav1.Object
+	GetGroupVersionKind() schema.GroupVersionKind
+}
+
+// ErrNoObject is returned when a manifest doesn't contain any object which
+// can be evaluated.
+var ErrNoObject = errors.New("no Filter or Splitter found in manifest")
+
+// ReadObject returns the first Filter or Splitter found in the given YAML or
+// JSON manifest, which may contain multiple documents. Objects of higher API
+// versions are converted to v1alpha1, and all objects are defaulted and
+// validated the same way the routing-webhook does.
+func ReadObject(ctx context.Context, r io.Reader) (Object, error) {
+	docs := utilyaml.NewYAMLReader(bufio.NewReader(r))
+
+	for {
+		doc, err := docs.Read()
+		if err == io.EOF {
+			return nil, ErrNoObject
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading manifest: %w", err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, err := decodeObject(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+		if obj == nil {
+			continue
+		}
+
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(defaultNamespace)
+		}
+
+		ctx = apis.WithinCreate(ctx)
+		obj.SetDefaults(ctx)
+		if err := obj.Validate(ctx); err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", obj.GetGroupVersionKind().Kind, obj.GetName(), err)
+		}
+
+		return obj, nil
+	}
+}
+
+// decodeObject decodes a single manifest document. It returns a nil Object
+// for documents which aren't Filters or Splitters.
+func decodeObject(ctx context.Context, doc []byte) (Object, error) {
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+		return nil, fmt.Errorf("decoding type of object: %w", err)
+	}
+
+	var obj, hub interface {
+		Object
+		apis.Convertible
+	}
+
+	switch typeMeta.GroupVersionKind() {
+	case v1alpha1.SchemeGroupVersion.WithKind("Filter"):
+		obj = &v1alpha1.Filter{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Splitter"):
+		obj = &v1alpha1.Splitter{}
+	case v1beta1.SchemeGroupVersion.WithKind("Filter"):
+		obj, hub = &v1beta1.Filter{}, &v1alpha1.Filter{}
+	case v1beta1.SchemeGroupVersion.WithKind("Splitter"):
+		obj, hub = &v1beta1.Splitter{}, &v1alpha1.Splitter{}
+	default:
+		return nil, nil
+	}
+
+	if err := yaml.UnmarshalStrict(doc, obj); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", typeMeta.Kind, err)
+	}
+
+	if hub != nil {
+		if err := hub.ConvertFrom(ctx, obj); err != nil {
+			return nil, fmt.Errorf("converting %s to %s: %w", typeMeta.Kind, v1alpha1.SchemeGroupVersion, err)
+		}
+		obj = hub
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 Triggermesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routingeval
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/triggermesh/routing/pkg/apis/flow/v1alpha1"
+)
+
+func TestReadObject(t *testing.T) {
+	const otherDoc = `
+apiVersion: sources.knative.dev/v1beta2
+kind: PingSource
+metadata:
+  name: ps
+`
+	const alphaFilter = `
+apiVersion: flow.triggermesh.io/v1alpha1
+kind: Filter
+metadata:
+  name: f
+spec:
+  expression: $id.(int64) == 5
+  sink:
+    uri: http://sink
+`
+	const betaSplitter = `
+apiVersion: flow.triggermesh.io/v1beta1
+kind: Splitter
+metadata:
+  name: s
+spec:
+  input:
+    path: items
+  dispatch:
+    ordered: true
+  sink:
+    uri: http://sink
+`
+
+	testCases := []struct {
+		name     string
+		manifest string
+		assert   func(*testing.T, Object)
+		err      string
+	}{{
+		name:     "v1alpha1 Filter after other object",
+		manifest: otherDoc + "---" + alphaFilter,
+		assert: func(t *testing.T, obj Object) {
+			f, ok := obj.(*v1alpha1.Filter)
+			require.True(t, ok, "Unexpected object type %T", obj)
+			assert.Equal(t, "default", f.Namespace)
+			assert.Equal(t, v1alpha1.ErrorPolicyPass, f.Spec.OnError)
+		},
+	}, {
+		name:     "v1beta1 Splitter",
+		manifest: betaSplitter,
+		assert: func(t *testing.T, obj Object) {
+			s, ok := obj.(*v1alpha1.Splitter)
+			require.True(t, ok, "Unexpected object type %T", obj)
+			assert.Equal(t, "items", s.Spec.Path)
+			assert.True(t, s.Spec.Ordered)
+			assert.Equal(t, "splitter/s", s.Spec.CEContext.Source)
+		},
+	}, {
+		name:     "no routing object",
+		manifest: otherDoc,
+		err:      ErrNoObject.Error(),
+	}, {
+		name:     "invalid object",
+		manifest: strings.Replace(alphaFilter, "$id.(int64) == 5", "$id.(int64) +", 1),
+		err:      `invalid Filter "f"`,
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			obj, err := ReadObject(context.Background(), strings.NewReader(tc.manifest))
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			tc.assert(t, obj)
+		})
+	}
+}
+
+func TestReadEvents(t *testing.T) {
+	testCases := []struct {
+		name   string
+		data   string
+		expect []string
+		err    bool
+	}{{
+		name:   "structured",
+		data:   `{"specversion":"1.0","id":"1","type":"t","source":"s","data":{"a":1}}`,
+		expect: []string{"1"},
+	}, {
+		name:   "batch",
+		data:   `[{"specversion":"1.0","id":"1","type":"t","source":"s"},{"specversion":"1.0","id":"2","type":"t","source":"s"}]`,
+		expect: []string{"1", "2"},
+	}, {
+		name: "binary HTTP request",
+		data: "POST / HTTP/1.1\r\nHost: localhost\r\nCe-Specversion: 1.0\r\nCe-Id: 1\r\nCe-Type: t\r\nCe-Source: s\r\n" +
+			"Content-Type: application/json\r\nContent-Length: 7\r\n\r\n{\"a\":1}",
+		expect: []string{"1"},
+	}, {
+		name:   "binary headers",
+		data:   "ce-specversion: 1.0\nce-id: 1\nce-type: t\nce-source: s\ncontent-type: application/json\n\n{\"a\":1}\n",
+		expect: []string{"1"},
+	}, {
+		name:   "binary headers without data",
+		data:   "ce-specversion: 1.0\nce-id: 1\nce-type: t\nce-source: s\n",
+		expect: []string{"1"},
+	}, {
+		name: "not an event",
+		data: "foo",
+		err:  true,
+	}}
+
+	for _, tc := range testCases {
+		//nolint:scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := ReadEvents(context.Background(), []byte(tc.data))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var ids []string
+			for _, e := range events {
+				ids = append(ids, e.ID())
+			}
+			assert.Equal(t, tc.expect, ids)
+		})
+	}
+}
+
+func TestFilterEvaluator(t *testing.T) {
+	event := cloudevents.NewEvent()
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, []byte(`{"id":5,"tags":[]}`)))
+
+	testCases := []struct {
+		expression string
+		onError    v1alpha1.ErrorPolicy
+		expect     Decision
+		err        bool
+	}{
+		{expression: `$id.(int64) == 5`, expect: DecisionPass},
+		{expression: `$id.(int64) == 6`, expect: DecisionDrop},
+		{expression: `$tags.(list)[0] == "a"`, expect: DecisionPass, err: true},
+		{expression: `$tags.(list)[0] == "a"`, onError: v1alpha1.ErrorPolicyDrop, expect: DecisionDrop, err: true},
+		{expression: `$tags.(list)[0] == "a"`, onError: v1alpha1.ErrorPolicyRoute, expect: DecisionRoute, err: true},
+	}
+
+	for _, tc := range testCases {
+		f := &v1alpha1.Filter{Spec: v1alpha1.FilterSpec{
+			Expression: tc.expression,
+			OnError:    tc.onError,
+		}}
+
+		fe, err := NewFilterEvaluator(f)
+		require.NoError(t, err)
+
+		decision, err := fe.Evaluate(context.Background(), &event)
+		assert.Equal(t, tc.expect, decision, tc.expression)
+		assert.Equal(t, tc.err, err != nil, tc.expression)
+	}
+}